@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DanielJonesEB/claudit/internal/cli"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aggregateOnto   string
+	aggregateAuto   bool
+	aggregateForce  bool
+	aggregateDryRun bool
+)
+
+var aggregateCmd = &cobra.Command{
+	Use:     "aggregate [<range>]",
+	Short:   "Combine a branch's conversations onto a squash commit",
+	GroupID: "human",
+	Long: `Combines the conversations stored on a range of commits into a single
+multi-session note on another commit. This preserves AI context when a
+feature branch is squash-merged: the original commits (and their notes)
+disappear from the main history, and the squash commit has none.
+
+The per-commit boundaries are kept, so 'claudit show' and the web UI render
+the aggregated conversation commit by commit.
+
+With --auto, claudit finds the original commits itself: first by looking for
+a PR number in the commit message ("Title (#123)" or a "PR: #123" trailer)
+and a matching fetched pull request ref, then by comparing patch IDs with
+unmerged local and remote-tracking branches. --auto accepts a commit or a
+range; every commit without a conversation is checked.
+
+Examples:
+  claudit aggregate main..feature --onto HEAD
+  claudit aggregate --auto                  # Check HEAD
+  claudit aggregate --auto origin/main~20..origin/main`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAggregate,
+}
+
+func init() {
+	aggregateCmd.Flags().StringVar(&aggregateOnto, "onto", "", "Commit to attach the aggregated conversation to")
+	aggregateCmd.Flags().BoolVar(&aggregateAuto, "auto", false, "Detect squash commits and their original commits automatically")
+	aggregateCmd.Flags().BoolVarP(&aggregateForce, "force", "f", false, "Overwrite an existing conversation on the target commit")
+	aggregateCmd.Flags().BoolVarP(&aggregateDryRun, "dry-run", "n", false, "Show what would be aggregated without writing notes")
+	aggregateCmd.MarkFlagsMutuallyExclusive("onto", "auto")
+	rootCmd.AddCommand(aggregateCmd)
+}
+
+func runAggregate(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	if aggregateAuto {
+		target := "HEAD"
+		if len(args) > 0 {
+			target = args[0]
+		}
+		return runAutoAggregate(target)
+	}
+
+	if len(args) == 0 || aggregateOnto == "" {
+		return fmt.Errorf("a revision range and --onto are required (or use --auto)")
+	}
+
+	onto, err := git.ResolveRef(aggregateOnto + "^{commit}")
+	if err != nil {
+		return fmt.Errorf("could not resolve reference '%s': not a valid commit", aggregateOnto)
+	}
+
+	return aggregateOntoCommit(args[0], onto)
+}
+
+// runAutoAggregate checks each commit selected by target for squash sources
+func runAutoAggregate(target string) error {
+	var commits []string
+	if git.IsRevisionRange(target) {
+		var err error
+		commits, err = git.RevList("--reverse", target)
+		if err != nil {
+			return fmt.Errorf("could not resolve revision range '%s': %w", target, err)
+		}
+	} else {
+		sha, err := git.ResolveRef(target + "^{commit}")
+		if err != nil {
+			return fmt.Errorf("could not resolve reference '%s': not a valid commit", target)
+		}
+		commits = []string{sha}
+	}
+
+	found := 0
+	for _, sha := range commits {
+		if git.HasNote(sha) && !aggregateForce {
+			cli.LogDebug("aggregate: %s already has a conversation, skipping", sha[:8])
+			continue
+		}
+
+		source, err := git.FindSquashSource(sha)
+		if err != nil {
+			cli.LogDebug("aggregate: could not inspect %s: %v", sha[:8], err)
+			continue
+		}
+		if source == nil {
+			cli.LogDebug("aggregate: no squash source found for %s", sha[:8])
+			continue
+		}
+
+		fmt.Printf("%s: found original commits on %s (by %s)\n", sha[:7], source.Ref, source.Method)
+		if err := aggregateOntoCommit(source.Range, sha); err != nil {
+			cli.LogWarning("could not aggregate onto %s: %v", sha[:8], err)
+			continue
+		}
+		found++
+	}
+
+	if found == 0 {
+		fmt.Println("no squash-merged commits with conversations found")
+	}
+	return nil
+}
+
+// aggregateOntoCommit combines the conversations in revRange and stores them on onto
+func aggregateOntoCommit(revRange, onto string) error {
+	commits, err := git.RevList("--reverse", "--topo-order", revRange)
+	if err != nil {
+		return fmt.Errorf("could not resolve revision range '%s': %w", revRange, err)
+	}
+
+	var annotated []string
+	for _, sha := range commits {
+		if sha != onto && git.HasNote(sha) {
+			annotated = append(annotated, sha)
+		}
+	}
+	if len(annotated) == 0 {
+		return fmt.Errorf("no conversations found in %s", revRange)
+	}
+
+	if git.HasNote(onto) && !aggregateForce {
+		return fmt.Errorf("commit %s already has a conversation (use --force to overwrite)", onto[:7])
+	}
+
+	if aggregateDryRun {
+		fmt.Printf("would aggregate %d conversations onto %s:\n", len(annotated), onto[:7])
+		for _, sha := range annotated {
+			message, _, _ := git.GetCommitInfo(sha)
+			fmt.Printf("  %s %s\n", sha[:7], message)
+		}
+		return nil
+	}
+
+	stored, err := storage.AggregateCommits(annotated)
+	if err != nil {
+		return fmt.Errorf("could not aggregate conversations: %w", err)
+	}
+	summarize(stored)
+
+	noteContent, err := stored.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversation: %w", err)
+	}
+
+	if err := git.AddNote(onto, noteContent); err != nil {
+		return fmt.Errorf("failed to add git note: %w", err)
+	}
+
+	fmt.Printf("aggregated %d conversations onto %s (%d messages)\n", len(stored.SourceCommits), onto[:7], stored.MessageCount)
+	return nil
+}
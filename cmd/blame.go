@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var blameCmd = &cobra.Command{
+	Use:         "blame [<rev>] <file>",
+	Short:       "Show which conversation wrote each line of a file",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Annotates a file like 'git blame', grouping lines by the commit that last
+changed them. For commits with a stored conversation, finds the Edit, Write,
+MultiEdit or NotebookEdit tool call that produced the lines and shows the
+user prompt and assistant explanation behind it.
+
+With --format json, jsonl or yaml each hunk is a record with the fields
+commit_sha, summary, author, date, start_line, lines, and for lines written
+in a conversation, session_id and edit (tool, tool_use_id, prompt and
+explanation).
+
+Examples:
+  claudit blame main.go
+  claudit blame HEAD~3 internal/server.go`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runBlame,
+}
+
+func init() {
+	rootCmd.AddCommand(blameCmd)
+}
+
+func runBlame(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	rev, file := "", args[0]
+	if len(args) == 2 {
+		rev, file = args[0], args[1]
+	}
+
+	hunks, err := attribution.Blame(rev, file)
+	if err != nil {
+		return fmt.Errorf("could not blame %s: %w", file, err)
+	}
+
+	if !out.IsText() {
+		if hunks == nil {
+			hunks = []attribution.BlameHunk{}
+		}
+		return out.Write(hunks)
+	}
+
+	width := len(fmt.Sprint(lastLine(hunks)))
+	for i, hunk := range hunks {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s %s %q\n", util.ShortSHA(hunk.CommitSHA), hunk.Author, hunk.Date, hunk.Summary)
+		for j, line := range hunk.Lines {
+			fmt.Printf("  %*d │ %s\n", width, hunk.StartLine+j, line)
+		}
+
+		if hunk.Edit != nil {
+			fmt.Printf("  %s ↳ %s in session %s\n", strings.Repeat(" ", width), hunk.Edit.Tool, util.ShortSHA(hunk.SessionID))
+			printBlameContext(width, "Prompt", hunk.Edit.Prompt)
+			printBlameContext(width, "Why", hunk.Edit.Explanation)
+		} else if hunk.SessionID != "" {
+			fmt.Printf("  %s ↳ conversation %s (no matching tool call)\n", strings.Repeat(" ", width), util.ShortSHA(hunk.SessionID))
+		}
+	}
+	return nil
+}
+
+// printBlameContext prints the first line of a prompt or explanation under a hunk
+func printBlameContext(width int, label, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Printf("  %s   %s: %s\n", strings.Repeat(" ", width), label, util.OneLine(text, 100))
+}
+
+// lastLine returns the highest line number in the blame output
+func lastLine(hunks []attribution.BlameHunk) int {
+	if len(hunks) == 0 {
+		return 0
+	}
+	last := hunks[len(hunks)-1]
+	return last.StartLine + len(last.Lines) - 1
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/DanielJonesEB/claudit/internal/cli"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/session"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var commitMsgCmd = &cobra.Command{
+	Use:     "commit-msg <message-file>",
+	Short:   "Add Claude session trailers to a commit message",
+	GroupID: "hooks",
+	Long: `Appends Claude-Session and Claude-Transcript-Checksum trailers to a commit
+message when a Claude Code session is active.
+
+Reviewers can't see git notes on hosting platforms, but they can see
+trailers. The trailers also let 'claudit show', 'claudit list' and
+'claudit reattach' find the conversation for a commit whose note was lost.
+
+This command is designed to be called by the git commit-msg hook, which
+'claudit init --trailers' installs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCommitMsg,
+}
+
+func init() {
+	rootCmd.AddCommand(commitMsgCmd)
+}
+
+func runCommitMsg(cmd *cobra.Command, args []string) error {
+	messageFile := args[0]
+
+	if !git.IsInsideWorkTree() {
+		cli.LogDebug("commit-msg: not inside a git repository, skipping")
+		return nil
+	}
+
+	projectPath, err := git.GetRepoRoot()
+	if err != nil {
+		cli.LogDebug("commit-msg: failed to get repo root: %v", err)
+		return nil
+	}
+
+	activeSession, err := session.DiscoverSession(projectPath)
+	if err != nil || activeSession == nil {
+		cli.LogDebug("commit-msg: no active session found (err=%v)", err)
+		return nil // Not a Claude-assisted commit
+	}
+
+	trailers := []string{git.TrailerSession + ": " + activeSession.SessionID}
+
+	// The checksum pins the transcript as it was at commit time
+	if data, err := os.ReadFile(activeSession.TranscriptPath); err == nil {
+		trailers = append(trailers, git.TrailerChecksum+": "+storage.Checksum(data))
+	} else {
+		cli.LogDebug("commit-msg: could not read transcript: %v", err)
+	}
+
+	if err := git.AddTrailers(messageFile, trailers...); err != nil {
+		// Never block a commit because of trailers
+		cli.LogWarning("could not add session trailers: %v", err)
+		return nil
+	}
+
+	cli.LogDebug("commit-msg: added trailers for session %s", activeSession.SessionID)
+	return nil
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/pager"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// defaultDiffWidth is the width of the side-by-side view when not writing
+// to a terminal
+const defaultDiffWidth = 160
+
+var diffConversationNoPager bool
+
+var diffConversationCmd = &cobra.Command{
+	Use:         "diff-conversation <refA> <refB>",
+	Short:       "Compare the conversations of two commits",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Compares the conversations stored for two commits, e.g. a session and one
+resumed from an older commit that went a different way, or two commits of
+the same session.
+
+Entries are aligned by their UUIDs and parents. The history both
+conversations share is shown once, then the entries after it are shown side
+by side, those of refA on the left and those of refB on the right.
+
+With --format json, jsonl or yaml the comparison is a record with the commit
+fields of 'claudit list' for each ref (commit_a and commit_b), common (the
+shared entries), fork_uuid (the last of them), and a and b (the entries
+after it).
+
+Examples:
+  claudit diff-conversation abc1234 def5678
+  claudit diff-conversation HEAD~3 HEAD  # What happened in between`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiffConversation,
+}
+
+func init() {
+	diffConversationCmd.Flags().BoolVar(&diffConversationNoPager, "no-pager", false, "Don't page the output")
+	rootCmd.AddCommand(diffConversationCmd)
+}
+
+// comparedCommit is one side of a comparison
+type comparedCommit struct {
+	sha        string
+	stored     *storage.StoredConversation
+	transcript *claude.Transcript
+}
+
+func runDiffConversation(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	a, err := loadComparedCommit(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := loadComparedCommit(args[1])
+	if err != nil {
+		return err
+	}
+	divergence := claude.Diverge(a.transcript.Entries, b.transcript.Entries)
+
+	if !out.IsText() {
+		return writeConversationDiff(a, b, divergence)
+	}
+
+	width := pager.Width()
+	if !diffConversationNoPager {
+		defer pager.Start()()
+	}
+	if width <= 0 {
+		width = defaultDiffWidth
+	}
+
+	messageA, _, _ := git.GetCommitInfo(a.sha)
+	messageB, _, _ := git.GetCommitInfo(b.sha)
+	fmt.Printf("A: %s %s (session %s)\n", util.ShortSHA(a.sha), messageA, util.ShortSHA(a.stored.SessionID))
+	fmt.Printf("B: %s %s (session %s)\n", util.ShortSHA(b.sha), messageB, util.ShortSHA(b.stored.SessionID))
+	fmt.Printf("Common: %d entries, then %d in A and %d in B\n", len(divergence.Common), len(divergence.A), len(divergence.B))
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Println()
+
+	renderer := claude.NewRenderer(os.Stdout)
+	renderer.SetWidth(width)
+	if err := renderer.RenderEntries(divergence.Common); err != nil {
+		return err
+	}
+
+	if len(divergence.A) == 0 && len(divergence.B) == 0 {
+		fmt.Println()
+		fmt.Println("The conversations are the same")
+		return nil
+	}
+
+	fmt.Println()
+	if divergence.ForkUUID != "" {
+		fmt.Printf("%s Diverged after %s %s\n", strings.Repeat("─", 3), util.ShortSHA(divergence.ForkUUID), strings.Repeat("─", 40))
+	} else {
+		fmt.Printf("%s No history in common %s\n", strings.Repeat("─", 3), strings.Repeat("─", 40))
+	}
+	left := renderTail(fmt.Sprintf("A: %s", util.ShortSHA(a.sha)), divergence.A, width)
+	right := renderTail(fmt.Sprintf("B: %s", util.ShortSHA(b.sha)), divergence.B, width)
+	fmt.Print(claude.SideBySide(left, right, width))
+	return nil
+}
+
+// loadComparedCommit resolves a ref and reads its conversation
+func loadComparedCommit(ref string) (*comparedCommit, error) {
+	sha, err := git.ResolveRef(ref)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve reference '%s': not a valid commit", ref)
+	}
+	stored, err := storage.GetStoredConversation(sha)
+	if err != nil {
+		return nil, fmt.Errorf("could not read conversation: %w", err)
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("no conversation found for commit %s", util.ShortSHA(sha))
+	}
+	transcript, err := stored.ParseTranscript()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse transcript for %s: %w", util.ShortSHA(sha), err)
+	}
+	return &comparedCommit{sha: sha, stored: stored, transcript: transcript}, nil
+}
+
+// renderTail renders one side's entries after the common history into a
+// column of the side-by-side view
+func renderTail(title string, entries []claude.TranscriptEntry, width int) string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, title)
+	fmt.Fprintln(&buf)
+	if len(entries) == 0 {
+		fmt.Fprintln(&buf, "(no further entries)")
+		return buf.String()
+	}
+	renderer := claude.NewRenderer(&buf)
+	renderer.SetWidth(claude.ColumnWidth(width))
+	_ = renderer.RenderEntries(entries)
+	return buf.String()
+}
+
+// writeConversationDiff writes the comparison record for --format
+func writeConversationDiff(a, b *comparedCommit, divergence claude.Divergence) error {
+	metaA, err := commitMeta(a.sha)
+	if err != nil {
+		return err
+	}
+	metaB, err := commitMeta(b.sha)
+	if err != nil {
+		return err
+	}
+	return out.Write(output.ConversationDiff{
+		CommitA:    commitRecord(metaA, a.stored, checksumStatus(a.stored)),
+		CommitB:    commitRecord(metaB, b.stored, checksumStatus(b.stored)),
+		Divergence: divergence,
+	})
+}
@@ -9,13 +9,15 @@ import (
 	"strings"
 
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var doctorCmd = &cobra.Command{
-	Use:     "doctor",
-	Short:   "Diagnose claudit configuration issues",
-	GroupID: "human",
+	Use:         "doctor",
+	Short:       "Diagnose claudit configuration issues",
+	GroupID:     "human",
+	Annotations: machineReadable,
 	Long: `Checks the claudit configuration and reports any issues that might
 prevent conversations from being stored.
 
@@ -23,7 +25,11 @@ This command checks:
 - Git repository status
 - Claude Code hook configuration
 - Git hooks installation
-- PATH configuration`,
+- PATH configuration
+
+With --format json, jsonl or yaml the report is a record with the fields ok
+and checks, each check having a name, a status (ok, fail or skip), messages
+and warnings. The command exits non-zero if any check failed.`,
 	RunE: runDoctor,
 }
 
@@ -32,154 +38,176 @@ func init() {
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	repoRoot, _ := git.GetRepoRoot()
+	diagnosis := output.Diagnosis{
+		OK: true,
+		Checks: []output.Check{
+			checkGitRepo(),
+			checkClauditInPath(),
+			checkClaudeHooks(repoRoot),
+			checkGitHooks(repoRoot),
+		},
+	}
+	for _, check := range diagnosis.Checks {
+		if check.Status == output.CheckFail {
+			diagnosis.OK = false
+		}
+	}
+
+	if out.IsText() {
+		printDiagnosis(diagnosis)
+	} else if err := out.Write(diagnosis); err != nil {
+		return err
+	}
+
+	if !diagnosis.OK {
+		return fmt.Errorf("configuration issues detected")
+	}
+	return nil
+}
+
+// printDiagnosis prints the result of each check and a summary
+func printDiagnosis(diagnosis output.Diagnosis) {
 	fmt.Println("Claudit Doctor")
 	fmt.Println("==============")
 	fmt.Println()
 
-	hasErrors := false
+	for _, check := range diagnosis.Checks {
+		fmt.Printf("Checking %s... ", check.Name)
+		switch check.Status {
+		case output.CheckSkip:
+			fmt.Printf("SKIP (%s)\n", strings.Join(check.Messages, ", "))
+			fmt.Println()
+			continue
+		case output.CheckFail:
+			fmt.Println("FAIL")
+		default:
+			fmt.Println("OK")
+		}
+		for _, message := range check.Messages {
+			fmt.Printf("  %s\n", message)
+		}
+		for _, warning := range check.Warnings {
+			fmt.Printf("  WARN: %s\n", warning)
+			fmt.Println("        Run 'claudit init' to add")
+		}
+		fmt.Println()
+	}
 
-	// Check 1: Git repository
-	fmt.Print("Checking git repository... ")
+	// Summary
+	if !diagnosis.OK {
+		fmt.Println("Issues found. Run 'claudit init' to fix configuration.")
+		return
+	}
+	fmt.Println("All checks passed! Claudit is properly configured.")
+}
+
+// failed returns a failed check with messages explaining why
+func failed(name string, messages ...string) output.Check {
+	return output.Check{Name: name, Status: output.CheckFail, Messages: messages}
+}
+
+// checkGitRepo checks that claudit is run inside a git repository
+func checkGitRepo() output.Check {
+	const name = "git repository"
 	if !git.IsInsideWorkTree() {
-		fmt.Println("FAIL")
-		fmt.Println("  Not inside a git repository")
-		hasErrors = true
-	} else {
-		repoRoot, _ := git.GetRepoRoot()
-		fmt.Println("OK")
-		fmt.Printf("  Repository: %s\n", repoRoot)
+		return failed(name, "Not inside a git repository")
 	}
-	fmt.Println()
+	repoRoot, _ := git.GetRepoRoot()
+	return output.Check{Name: name, Status: output.CheckOK, Messages: []string{"Repository: " + repoRoot}}
+}
 
-	// Check 2: claudit in PATH
-	fmt.Print("Checking claudit in PATH... ")
+// checkClauditInPath checks that hooks can find the claudit binary
+func checkClauditInPath() output.Check {
+	const name = "claudit in PATH"
 	clauditPath, err := exec.LookPath("claudit")
 	if err != nil {
-		fmt.Println("FAIL")
-		fmt.Println("  'claudit' is not in your PATH")
-		fmt.Println("  Claude Code hooks will not be able to find claudit")
-		fmt.Println("  Install with: go install github.com/DanielJonesEB/claudit@latest")
-		hasErrors = true
-	} else {
-		fmt.Println("OK")
-		fmt.Printf("  Found: %s\n", clauditPath)
+		return failed(name,
+			"'claudit' is not in your PATH",
+			"Claude Code hooks will not be able to find claudit",
+			"Install with: go install github.com/DanielJonesEB/claudit@latest")
 	}
-	fmt.Println()
+	return output.Check{Name: name, Status: output.CheckOK, Messages: []string{"Found: " + clauditPath}}
+}
 
-	// Check 3: Claude settings file
-	fmt.Print("Checking Claude Code hook configuration... ")
-	repoRoot, _ := git.GetRepoRoot()
+// checkClaudeHooks checks that Claude Code is configured to run claudit hooks
+func checkClaudeHooks(repoRoot string) output.Check {
+	const name = "Claude Code hook configuration"
 	if repoRoot == "" {
-		fmt.Println("SKIP (not in git repo)")
+		return output.Check{Name: name, Status: output.CheckSkip, Messages: []string{"not in git repo"}}
+	}
+
+	settingsPath := filepath.Join(repoRoot, ".claude", "settings.local.json")
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return failed(name, "No .claude/settings.local.json found", "Run 'claudit init' to configure")
+	}
+
+	// Check hook format
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return failed(name, fmt.Sprintf("Invalid JSON in settings file: %v", err))
+	}
+
+	// Check for correct nested structure
+	hooks, hasHooks := settings["hooks"].(map[string]interface{})
+	if !hasHooks {
+		return failed(name, "Missing 'hooks' key in settings", "Run 'claudit init' to fix")
+	}
+	postToolUse, hasPostToolUse := hooks["PostToolUse"]
+	if !hasPostToolUse {
+		return failed(name, "Missing 'hooks.PostToolUse' configuration", "Run 'claudit init' to fix")
+	}
+
+	// Check for claudit store command
+	check := output.Check{Name: name, Status: output.CheckOK}
+	if !hasClauditCommand(postToolUse, "claudit store") {
+		check = failed(name, "'claudit store' hook not found in PostToolUse", "Run 'claudit init' to fix")
 	} else {
-		settingsPath := filepath.Join(repoRoot, ".claude", "settings.local.json")
-		data, err := os.ReadFile(settingsPath)
-		if err != nil {
-			fmt.Println("FAIL")
-			fmt.Println("  No .claude/settings.local.json found")
-			fmt.Println("  Run 'claudit init' to configure")
-			hasErrors = true
+		check.Messages = append(check.Messages, "Found PostToolUse hook configuration")
+	}
+
+	// Check for SessionStart and SessionEnd hooks
+	for _, hook := range []struct{ event, command string }{
+		{"SessionStart", "claudit session-start"},
+		{"SessionEnd", "claudit session-end"},
+	} {
+		config, ok := hooks[hook.event]
+		if !ok || !hasClauditCommand(config, hook.command) {
+			check.Warnings = append(check.Warnings, fmt.Sprintf("Missing %s hook (manual commit capture won't work)", hook.event))
 		} else {
-			// Check hook format
-			var settings map[string]interface{}
-			if err := json.Unmarshal(data, &settings); err != nil {
-				fmt.Println("FAIL")
-				fmt.Printf("  Invalid JSON in settings file: %v\n", err)
-				hasErrors = true
-			} else {
-				// Check for correct nested structure
-				hooks, hasHooks := settings["hooks"].(map[string]interface{})
-				if !hasHooks {
-					fmt.Println("FAIL")
-					fmt.Println("  Missing 'hooks' key in settings")
-					fmt.Println("  Run 'claudit init' to fix")
-					hasErrors = true
-				} else {
-					postToolUse, hasPostToolUse := hooks["PostToolUse"]
-					if !hasPostToolUse {
-						fmt.Println("FAIL")
-						fmt.Println("  Missing 'hooks.PostToolUse' configuration")
-						fmt.Println("  Run 'claudit init' to fix")
-						hasErrors = true
-					} else {
-						// Check for claudit store command
-						foundClaudit := hasClauditCommand(postToolUse, "claudit store")
-						if !foundClaudit {
-							fmt.Println("FAIL")
-							fmt.Println("  'claudit store' hook not found in PostToolUse")
-							fmt.Println("  Run 'claudit init' to fix")
-							hasErrors = true
-						} else {
-							fmt.Println("OK")
-							fmt.Printf("  Found PostToolUse hook configuration\n")
-						}
-
-						// Check for SessionStart hook
-						sessionStart, hasSessionStart := hooks["SessionStart"]
-						if !hasSessionStart || !hasClauditCommand(sessionStart, "claudit session-start") {
-							fmt.Println("  WARN: Missing SessionStart hook (manual commit capture won't work)")
-							fmt.Println("        Run 'claudit init' to add")
-						} else {
-							fmt.Println("  Found SessionStart hook")
-						}
-
-						// Check for SessionEnd hook
-						sessionEnd, hasSessionEnd := hooks["SessionEnd"]
-						if !hasSessionEnd || !hasClauditCommand(sessionEnd, "claudit session-end") {
-							fmt.Println("  WARN: Missing SessionEnd hook (manual commit capture won't work)")
-							fmt.Println("        Run 'claudit init' to add")
-						} else {
-							fmt.Println("  Found SessionEnd hook")
-						}
-					}
-				}
-			}
+			check.Messages = append(check.Messages, fmt.Sprintf("Found %s hook", hook.event))
 		}
 	}
-	fmt.Println()
+	return check
+}
 
-	// Check 4: Git hooks
-	fmt.Print("Checking git hooks... ")
+// checkGitHooks checks that the git hooks which sync notes are installed
+func checkGitHooks(repoRoot string) output.Check {
+	const name = "git hooks"
 	if repoRoot == "" {
-		fmt.Println("SKIP (not in git repo)")
-	} else {
-		gitDir, _ := git.EnsureGitDir()
-		if gitDir == "" {
-			fmt.Println("FAIL")
-			fmt.Println("  Could not find .git directory")
-			hasErrors = true
-		} else {
-			missingHooks := []string{}
-			for _, hook := range []string{"pre-push", "post-merge", "post-checkout", "post-commit"} {
-				hookPath := filepath.Join(gitDir, "hooks", hook)
-				data, err := os.ReadFile(hookPath)
-				if err != nil {
-					missingHooks = append(missingHooks, hook)
-				} else if !strings.Contains(string(data), "claudit") {
-					missingHooks = append(missingHooks, hook+" (no claudit)")
-				}
-			}
-			if len(missingHooks) > 0 {
-				fmt.Println("FAIL")
-				fmt.Printf("  Missing or incomplete hooks: %v\n", missingHooks)
-				fmt.Println("  Run 'claudit init' to fix")
-				hasErrors = true
-			} else {
-				fmt.Println("OK")
-				fmt.Println("  All git hooks installed")
-			}
-		}
+		return output.Check{Name: name, Status: output.CheckSkip, Messages: []string{"not in git repo"}}
 	}
-	fmt.Println()
 
-	// Summary
-	if hasErrors {
-		fmt.Println("Issues found. Run 'claudit init' to fix configuration.")
-		return fmt.Errorf("configuration issues detected")
+	gitDir, _ := git.EnsureGitDir()
+	if gitDir == "" {
+		return failed(name, "Could not find .git directory")
 	}
 
-	fmt.Println("All checks passed! Claudit is properly configured.")
-	return nil
+	missingHooks := []string{}
+	for _, hook := range []string{"pre-push", "post-merge", "post-checkout", "post-commit"} {
+		hookPath := filepath.Join(gitDir, "hooks", hook)
+		data, err := os.ReadFile(hookPath)
+		if err != nil {
+			missingHooks = append(missingHooks, hook)
+		} else if !strings.Contains(string(data), "claudit") {
+			missingHooks = append(missingHooks, hook+" (no claudit)")
+		}
+	}
+	if len(missingHooks) > 0 {
+		return failed(name, fmt.Sprintf("Missing or incomplete hooks: %v", missingHooks), "Run 'claudit init' to fix")
+	}
+	return output.Check{Name: name, Status: output.CheckOK, Messages: []string{"All git hooks installed"}}
 }
 
 // hasClauditCommand checks if a hook list contains a specific claudit command
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/export"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/DanielJonesEB/claudit/internal/web"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportFull   bool
+	exportOutput string
+	exportSite   string
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export [<revision>|<revision-range>...]",
+	Short:   "Export conversations to Markdown, HTML or JSONL",
+	GroupID: "human",
+	Long: `Exports the conversations of commits as a document to attach to design
+reviews and audits.
+
+A revision exports that commit's conversation (HEAD by default); a revision
+range such as main..feature exports every commit in it with a conversation,
+oldest first. Like 'claudit show', only the conversation since the previous
+commit of the session is exported unless --full is given.
+
+Formats (--format):
+  markdown  Markdown with tool calls in collapsible sections and edits as
+            diff code blocks (the default)
+  html      A self-contained HTML page with collapsible tool calls and
+            highlighted diffs
+  jsonl     The transcript entries exactly as recorded, one per line
+
+With --site, writes a static copy of the web UI ('claudit serve') for every
+commit with a conversation to a directory, which can be opened from disk or
+published without a server.
+
+Examples:
+  claudit export > conversation.md
+  claudit export --format html -o review.html main..feature
+  claudit export --full --format jsonl abc1234
+  claudit export --site ./conversations`,
+	RunE: runExport,
+}
+
+func init() {
+	// Exports have formats of their own rather than the output formats
+	exportCmd.Flags().StringVar(&exportFormat, "format", export.Markdown, "Export format: "+strings.Join(export.Formats, ", "))
+	exportCmd.Flags().BoolVarP(&exportFull, "full", "f", false, "Export the full session instead of the conversation since the previous commit")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to a file instead of stdout")
+	exportCmd.Flags().StringVar(&exportSite, "site", "", "Write a static site of all conversations to a directory")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+	if exportSite != "" {
+		return runExportSite(cmd, args)
+	}
+
+	valid := false
+	for _, f := range export.Formats {
+		valid = valid || f == exportFormat
+	}
+	if !valid {
+		return fmt.Errorf("unknown export format %q (use %s)", exportFormat, strings.Join(export.Formats, ", "))
+	}
+
+	convs, err := exportConversations(args)
+	if err != nil {
+		return err
+	}
+
+	if exportOutput == "" {
+		return export.Write(os.Stdout, exportFormat, convs)
+	}
+	f, err := os.Create(exportOutput)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", exportOutput, err)
+	}
+	if err := export.Write(f, exportFormat, convs); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("could not write %s: %w", exportOutput, err)
+	}
+	return f.Close()
+}
+
+// runExportSite writes the web UI and its data for every conversation
+func runExportSite(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 || exportOutput != "" || cmd.Flags().Changed("full") || cmd.Flags().Changed("format") {
+		return fmt.Errorf("--site exports every conversation and cannot be combined with revisions, --output, --full or --format")
+	}
+
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("could not determine repository root: %w", err)
+	}
+	count, err := web.NewServer(0, repoRoot).ExportSite(exportSite)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported the conversations of %d commits to %s\n", count, exportSite)
+	fmt.Printf("Open %s in a browser to view them\n", strings.TrimSuffix(exportSite, "/")+"/index.html")
+	return nil
+}
+
+// exportConversations loads the conversations of the given revisions.
+// Single revisions must have a conversation; ranges are filtered to the
+// commits that do.
+func exportConversations(args []string) ([]export.Conversation, error) {
+	if len(args) == 0 {
+		args = []string{"HEAD"}
+	}
+
+	var metas []git.CommitMeta
+	isRange := false
+	for _, arg := range args {
+		isRange = isRange || git.IsRevisionRange(arg)
+	}
+	if isRange {
+		var err error
+		if metas, err = git.LogCommits(append([]string{"--reverse"}, args...)...); err != nil {
+			return nil, fmt.Errorf("could not read history: %w", err)
+		}
+	} else {
+		for _, arg := range args {
+			sha, err := git.ResolveRef(arg)
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve reference '%s': not a valid commit", arg)
+			}
+			meta, err := commitMeta(sha)
+			if err != nil {
+				return nil, err
+			}
+			metas = append(metas, meta)
+		}
+	}
+
+	var convs []export.Conversation
+	for _, meta := range metas {
+		conv, err := attribution.LoadCommitConversation(meta.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("could not read conversation for %s: %w", util.ShortSHA(meta.SHA), err)
+		}
+		if conv == nil {
+			if isRange {
+				continue
+			}
+			return nil, fmt.Errorf("no conversation found for commit %s", util.ShortSHA(meta.SHA))
+		}
+
+		exported := export.Conversation{
+			SHA:       meta.SHA,
+			Subject:   meta.Subject,
+			Author:    meta.Author,
+			Date:      meta.Date,
+			SessionID: conv.Stored.SessionID,
+			Branch:    conv.Stored.GitBranch,
+			ParentSHA: conv.ParentSHA,
+			Entries:   conv.Entries,
+		}
+		if exportFull {
+			exported.ParentSHA = ""
+			exported.Entries = conv.Transcript.Entries
+		}
+		convs = append(convs, exported)
+	}
+
+	if len(convs) == 0 {
+		return nil, fmt.Errorf("no conversations found in %s", strings.Join(args, " "))
+	}
+	return convs, nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/cli"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDryRun     bool
+	importSessionDir string
+)
+
+var importCmd = &cobra.Command{
+	Use:         "import",
+	Short:       "Import conversations from existing Claude Code session files",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Scans the Claude Code session files kept for this repository (in
+~/.claude/projects/<encoded-path>/) and stores the conversation behind each
+commit that has no note yet, for history made before claudit was set up.
+
+A commit is matched to a session when:
+  - the session ran 'git commit' and the output names the commit, or
+  - the commit was made while the session was active in the repository (its
+    last message at most 5 minutes before), on the branch the session was on
+
+Each commit gets the session as it was when the commit was made. Commits
+made while several sessions were active are reported and skipped.
+
+With --format json, jsonl or yaml each commit is a record with the fields of
+'claudit list', plus match, session_file and imported.
+
+Examples:
+  claudit import --dry-run   # Preview what would be imported
+  claudit import`,
+	Args: cobra.NoArgs,
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().BoolVarP(&importDryRun, "dry-run", "n", false, "Show what would be imported without writing notes")
+	importCmd.Flags().StringVar(&importSessionDir, "session-dir", "", "Directory of session files (default: Claude's directory for this repository)")
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("could not determine repository root: %w", err)
+	}
+
+	sessionDir := importSessionDir
+	if sessionDir == "" {
+		if sessionDir, err = claude.GetSessionDir(repoRoot); err != nil {
+			return err
+		}
+	}
+
+	noted, err := git.CommitsWithNotesSet()
+	if err != nil {
+		return fmt.Errorf("could not list conversations: %w", err)
+	}
+	result, err := storage.FindImports(sessionDir, repoRoot, noted)
+	if err != nil {
+		return err
+	}
+
+	records := []output.Import{}
+	for _, match := range result.Matches {
+		meta, err := commitMeta(match.CommitSHA)
+		if err != nil {
+			return err
+		}
+
+		if !importDryRun {
+			summarize(match.Stored)
+			noteContent, err := match.Stored.Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to marshal conversation: %w", err)
+			}
+			if err := git.AddNote(match.CommitSHA, noteContent); err != nil {
+				return fmt.Errorf("failed to add git note: %w", err)
+			}
+		}
+
+		if !out.IsText() {
+			records = append(records, output.Import{
+				Commit:      commitRecord(meta, match.Stored, output.ChecksumValid),
+				Match:       match.Method,
+				SessionFile: match.SessionFile,
+				Imported:    !importDryRun,
+			})
+			continue
+		}
+		action := "imported"
+		if importDryRun {
+			action = "would import"
+		}
+		fmt.Printf("%s %s from session %s (%d messages, matched by %s): %s\n",
+			action, util.ShortSHA(match.CommitSHA), util.ShortSHA(match.Stored.SessionID), match.Stored.MessageCount, match.Method, meta.Subject)
+	}
+
+	for _, ambiguity := range result.Ambiguous {
+		cli.LogWarning("skipped %s: made while sessions %s were active", util.ShortSHA(ambiguity.CommitSHA), strings.Join(ambiguity.SessionIDs, ", "))
+	}
+
+	if !out.IsText() {
+		return out.Write(records)
+	}
+	switch {
+	case result.Sessions == 0:
+		fmt.Printf("no session files found in %s\n", sessionDir)
+	case len(result.Matches) == 0:
+		fmt.Printf("no conversations to import from %d session files\n", result.Sessions)
+	case importDryRun:
+		fmt.Printf("%d conversations would be imported from %d session files\n", len(result.Matches), result.Sessions)
+	default:
+		fmt.Printf("imported %d conversations from %d session files\n", len(result.Matches), result.Sessions)
+	}
+	return nil
+}
@@ -24,11 +24,28 @@ This command:
 - Uses refs/notes/claude-conversations for note storage
 - Creates/updates .claude/settings.local.json with PostToolUse hook
 - Installs git hooks for automatic note syncing
-- Configures git settings for notes visibility`,
+- Configures git settings for notes visibility
+
+With --trailers, also installs a commit-msg hook that adds Claude-Session
+and Claude-Transcript-Checksum trailers to commits made during a session.
+
+With --log-pager, also pages git log and git show through 'claudit
+textconv', unless they have a pager already, so notes show as a short
+header. To undo it:
+
+  git config --unset pager.log
+  git config --unset pager.show`,
 	RunE: runInit,
 }
 
+var (
+	initTrailers bool
+	initLogPager bool
+)
+
 func init() {
+	initCmd.Flags().BoolVar(&initTrailers, "trailers", false, "Also add Claude session trailers to commit messages")
+	initCmd.Flags().BoolVar(&initLogPager, "log-pager", false, "Also page git log and git show through 'claudit textconv'")
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -52,6 +69,16 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✓ Configured notes ref: %s\n", git.NotesRef)
 	fmt.Println("✓ Configured git notes settings (displayRef, rewriteRef)")
 
+	if initLogPager {
+		configured, err := configureLogPager()
+		if err != nil {
+			return fmt.Errorf("failed to configure git pager: %w", err)
+		}
+		if len(configured) > 0 {
+			fmt.Printf("✓ Configured git to show conversation notes as summaries (%s)\n", strings.Join(configured, ", "))
+		}
+	}
+
 	// Configure Claude hooks
 	cli.LogDebug("init: configuring Claude hooks")
 	claudeDir := filepath.Join(repoRoot, ".claude")
@@ -82,6 +109,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("✓ Installed git hooks (pre-push, post-merge, post-checkout, post-commit)")
 
+	if initTrailers {
+		if err := git.InstallTrailerHook(gitDir); err != nil {
+			return err
+		}
+		fmt.Println("✓ Installed commit-msg hook (Claude-Session trailers)")
+	}
+
 	// Add .claudit/ to .gitignore
 	cli.LogDebug("init: ensuring .claudit/ is in .gitignore")
 	if err := ensureGitignoreEntry(repoRoot, ".claudit/"); err != nil {
@@ -161,11 +195,50 @@ func configureGitSettings(notesRef string) error {
 		return fmt.Errorf("failed to set notes.displayRef: %w", err)
 	}
 
-	// Configure notes.rewriteRef so notes follow commits during rebase
-	cmd = exec.Command("git", "config", "notes.rewriteRef", notesRef)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to set notes.rewriteRef: %w", err)
+	// Configure notes.rewriteRef so conversations and their labels follow
+	// commits during rebase and amend
+	for _, ref := range []string{notesRef, git.LabelsRef} {
+		if err := addRewriteRef(ref); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// addRewriteRef adds ref to notes.rewriteRef, which can hold several refs,
+// unless it's there already
+func addRewriteRef(ref string) error {
+	current, _ := exec.Command("git", "config", "--get-all", "notes.rewriteRef").Output()
+	for _, value := range strings.Split(string(current), "\n") {
+		if strings.TrimSpace(value) == ref {
+			return nil
+		}
+	}
+	if err := exec.Command("git", "config", "--add", "notes.rewriteRef", ref).Run(); err != nil {
+		return fmt.Errorf("failed to add %s to notes.rewriteRef: %w", ref, err)
+	}
+	return nil
+}
+
+// textconvPager is the pager that shortens conversation notes in git log
+const textconvPager = "claudit textconv"
+
+// configureLogPager makes git log and git show page through 'claudit
+// textconv', so that they show a note's header instead of its payload.
+// Commands that already have a pager configured are left alone. Returns the
+// settings made.
+func configureLogPager() ([]string, error) {
+	var configured []string
+	for _, key := range []string{"pager.log", "pager.show"} {
+		current, _ := exec.Command("git", "config", "--get", key).Output()
+		if value := strings.TrimSpace(string(current)); value != "" && value != textconvPager {
+			continue
+		}
+		if err := exec.Command("git", "config", key, textconvPager).Run(); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		configured = append(configured, key)
+	}
+	return configured, nil
+}
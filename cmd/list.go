@@ -2,16 +2,37 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
 	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/util"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listBranches    []string
+	listSince       string
+	listUntil       string
+	listAuthor      string
+	listSession     string
+	listMinMessages int
+	listLimit       int
+	listSkip        int
+	listSort        string
+	listLabels      []string
+)
+
+// listSortOrders are the orders accepted by --sort
+var listSortOrders = []string{"newest", "oldest", "messages"}
+
 var listCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List commits with stored conversations",
-	GroupID: "human",
+	Use:         "list [<revision-range>...]",
+	Short:       "List commits with stored conversations",
+	GroupID:     "human",
+	Annotations: machineReadable,
 	Long: `Lists all commits in the repository that have associated Claude Code
 conversations stored as Git Notes.
 
@@ -20,14 +41,50 @@ Shows:
   - Commit date
   - Commit message (truncated)
   - Number of messages in conversation
+  - The conversation's labels, given with 'claudit tag'
+  - The conversation's summary
 
 Example output:
-  abc1234 2024-01-15 feat: add user auth (42 messages)
-  def5678 2024-01-14 fix: login bug (15 messages)`,
+  abc1234 2024-01-15 feat: add user auth (42 messages) · Add OAuth login · edited auth.go · Edit ×6
+  def5678 2024-01-14 fix: login bug (15 messages) · Fix the redirect · edited auth.go · Edit ×2
+
+Commits are selected by git itself: pass revisions and ranges as you would
+to 'git log' (e.g. main..feature or v1.0..), or other options that limit
+git log's commits, such as --first-parent, after '--'. Without revisions or --branch, every branch is searched.
+--branch, --since, --until and --author are passed through to git log.
+
+--session, --label, --min-messages, --sort, --skip and --limit apply to the commits
+with conversations, in that order, so --skip and --limit page through the
+filtered list.
+
+With --format json, jsonl or yaml each commit is a record with the fields
+sha, date, author, author_email, message, session_id, branch, provider,
+message_count, checksum (valid, invalid, or missing when only a
+Claude-Session trailer remains), summary and labels. For example:
+
+  claudit list --template '{{short .sha}} {{.session_id}} {{.checksum}}'
+
+Examples:
+  claudit list main..feature
+  claudit list --branch main --since 2.weeks --author alice
+  claudit list --session 3f2a --sort oldest
+  claudit list --label incident --label "needs review"
+  claudit list --min-messages 50 --sort messages --limit 10
+  claudit list --skip 20 --limit 20 -- --first-parent`,
 	RunE: runList,
 }
 
 func init() {
+	listCmd.Flags().StringSliceVar(&listBranches, "branch", nil, "Only list commits reachable from this branch (repeatable)")
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only list commits more recent than a date, e.g. 2.weeks or 2024-01-01")
+	listCmd.Flags().StringVar(&listUntil, "until", "", "Only list commits older than a date")
+	listCmd.Flags().StringVar(&listAuthor, "author", "", "Only list commits whose author matches a pattern")
+	listCmd.Flags().StringVar(&listSession, "session", "", "Only list commits from sessions whose ID starts with this")
+	listCmd.Flags().StringArrayVar(&listLabels, "label", nil, "Only list conversations with this label (repeatable, all must match)")
+	listCmd.Flags().IntVar(&listMinMessages, "min-messages", 0, "Only list conversations with at least this many messages")
+	listCmd.Flags().IntVarP(&listLimit, "limit", "n", 0, "Show at most this many commits (0 for no limit)")
+	listCmd.Flags().IntVar(&listSkip, "skip", 0, "Skip this many commits before listing")
+	listCmd.Flags().StringVar(&listSort, "sort", "newest", "Sort order: "+strings.Join(listSortOrders, ", "))
 	rootCmd.AddCommand(listCmd)
 }
 
@@ -36,51 +93,216 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err := git.RequireGitRepo(); err != nil {
 		return err
 	}
+	if err := validateListFlags(); err != nil {
+		return err
+	}
 
-	// Get list of commits with notes
-	commits, err := git.ListCommitsWithNotes()
+	// Let git select the commits, then read only those with conversations
+	selected, err := git.RevList(listLogArgs(args)...)
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+	noted, err := git.CommitsWithNotesSet()
 	if err != nil {
 		return fmt.Errorf("could not list conversations: %w", err)
 	}
 
-	if len(commits) == 0 {
-		fmt.Println("no conversations found")
-		return nil
+	// Commits whose notes were lost can still be found via their trailers
+	var unnoted []string
+	for _, sha := range selected {
+		if !noted[sha] {
+			unnoted = append(unnoted, sha)
+		}
+	}
+	trailerSessions := map[string]string{}
+	if len(unnoted) > 0 {
+		if trailerSessions, err = git.CommitsWithTrailerOf(unnoted, git.TrailerSession); err != nil {
+			return fmt.Errorf("could not read trailers: %w", err)
+		}
 	}
 
-	// Display each commit with conversation metadata
-	for _, commitSHA := range commits {
-		// Get commit info
-		message, date, err := git.GetCommitInfo(commitSHA)
-		if err != nil {
-			// Skip commits we can't get info for
-			continue
+	var shas []string
+	for _, sha := range selected {
+		if _, ok := trailerSessions[sha]; noted[sha] || ok {
+			shas = append(shas, sha)
+		}
+	}
+	var commits []git.CommitMeta
+	if len(shas) > 0 {
+		if commits, err = git.LogCommitsOf(shas, "--no-walk=unsorted"); err != nil {
+			return fmt.Errorf("could not read commits: %w", err)
 		}
+	}
 
-		// Truncate message
-		if len(message) > 50 {
-			message = message[:47] + "..."
+	labels, err := storage.AllLabels()
+	if err != nil {
+		return fmt.Errorf("could not read labels: %w", err)
+	}
+
+	records := []output.Commit{}
+	for _, meta := range commits {
+		if !noted[meta.SHA] {
+			if sessionID, ok := trailerSessions[meta.SHA]; ok {
+				record := commitRecord(meta, nil, output.ChecksumMissing)
+				record.SessionID = sessionID
+				records = append(records, record)
+			}
+			continue
 		}
 
 		// Get conversation metadata
-		stored, err := storage.GetStoredConversation(commitSHA)
+		stored, err := storage.GetStoredConversation(meta.SHA)
 		if err != nil || stored == nil {
 			continue
 		}
+		record := commitRecord(meta, stored, checksumStatus(stored))
+		record.Labels = labels[meta.SHA]
+		records = append(records, record)
+	}
+	records = filterListRecords(records)
+
+	if !out.IsText() {
+		return out.Write(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Println("no conversations found")
+		return nil
+	}
+
+	// Display each commit with conversation metadata
+	for _, record := range records {
+		// Truncate message
+		message := record.Message
+		if len(message) > 50 {
+			message = message[:47] + "..."
+		}
 
 		// Format date (take just the date part)
-		shortDate := date
-		if len(date) >= 10 {
-			shortDate = date[:10]
+		shortDate := record.Date
+		if len(shortDate) >= 10 {
+			shortDate = shortDate[:10]
 		}
 
-		fmt.Printf("%s %s %s (%d messages)\n",
-			commitSHA[:7],
+		if record.Checksum == output.ChecksumMissing {
+			fmt.Printf("%s %s %s (note missing, session %s)\n",
+				record.SHA[:7],
+				shortDate,
+				message,
+				util.ShortSHA(record.SessionID),
+			)
+			continue
+		}
+
+		suffix := ""
+		if len(record.Labels) > 0 {
+			suffix = " [" + strings.Join(record.Labels, ", ") + "]"
+		}
+		if record.Summary != "" {
+			suffix += " · " + record.Summary
+		}
+		fmt.Printf("%s %s %s (%d messages)%s\n",
+			record.SHA[:7],
 			shortDate,
 			message,
-			stored.MessageCount,
+			record.MessageCount,
+			suffix,
 		)
 	}
 
 	return nil
 }
+
+func validateListFlags() error {
+	valid := false
+	for _, order := range listSortOrders {
+		valid = valid || listSort == order
+	}
+	if !valid {
+		return fmt.Errorf("unknown sort order %q (expected %s)", listSort, strings.Join(listSortOrders, ", "))
+	}
+	if listLimit < 0 || listSkip < 0 || listMinMessages < 0 {
+		return fmt.Errorf("--limit, --skip and --min-messages must not be negative")
+	}
+	return nil
+}
+
+// listLogArgs builds the git rev-list arguments that select the commits to
+// list
+func listLogArgs(args []string) []string {
+	logArgs := []string{"--topo-order"}
+	if listSince != "" {
+		logArgs = append(logArgs, "--since="+listSince)
+	}
+	if listUntil != "" {
+		logArgs = append(logArgs, "--until="+listUntil)
+	}
+	if listAuthor != "" {
+		logArgs = append(logArgs, "--author="+listAuthor)
+	}
+
+	revisions := append(append([]string{}, listBranches...), args...)
+	if !hasRevision(revisions) {
+		logArgs = append(logArgs, "--all")
+	}
+	return append(logArgs, revisions...)
+}
+
+// hasRevision returns true if the arguments name a revision rather than
+// only git log options
+func hasRevision(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// filterListRecords applies --session, --min-messages, --sort, --skip and
+// --limit to commits in git's order
+func filterListRecords(records []output.Commit) []output.Commit {
+	filtered := []output.Commit{}
+	for _, record := range records {
+		if listSession != "" && !strings.HasPrefix(record.SessionID, listSession) {
+			continue
+		}
+		if record.MessageCount < listMinMessages {
+			continue
+		}
+		if !hasAllLabels(record.Labels, listLabels) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+
+	switch listSort {
+	case "oldest":
+		for i, j := 0, len(filtered)-1; i < j; i, j = i+1, j-1 {
+			filtered[i], filtered[j] = filtered[j], filtered[i]
+		}
+	case "messages":
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].MessageCount > filtered[j].MessageCount
+		})
+	}
+
+	if listSkip >= len(filtered) {
+		return []output.Commit{}
+	}
+	filtered = filtered[listSkip:]
+	if listLimit > 0 && listLimit < len(filtered) {
+		filtered = filtered[:listLimit]
+	}
+	return filtered
+}
+
+// hasAllLabels returns true if labels include every one of want
+func hasAllLabels(labels, want []string) bool {
+	for _, label := range want {
+		if !storage.HasLabel(labels, label) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var logGraph bool
+
+var logCmd = &cobra.Command{
+	Use:         "log [<revision-range>...]",
+	Short:       "Show git log with a digest of each commit's conversation",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Shows the commit history like 'git log', following each commit that has a
+stored conversation with a digest of the part of the session that led to it:
+the user's prompts, the assistant's final summary, the tools it called and
+the files it edited.
+
+Commits are selected like 'git log' (HEAD by default); pass other git log
+options such as --author or --since after '--'. Use --graph to draw the
+commit graph alongside.
+
+With --format json, jsonl or yaml each commit is a record with the fields of
+'claudit list', plus parent_sha and a digest (prompts, summary, tools and
+files) for commits with a conversation.
+
+Examples:
+  claudit log
+  claudit log --graph main..feature
+  claudit log -- --since=1.week --author=alice`,
+	RunE: runLog,
+}
+
+func init() {
+	logCmd.Flags().BoolVar(&logGraph, "graph", false, "Draw the commit graph")
+	rootCmd.AddCommand(logCmd)
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	var lines []git.GraphLine
+	if logGraph && out.IsText() {
+		var err error
+		if lines, err = git.LogGraph(args...); err != nil {
+			return fmt.Errorf("could not read history: %w", err)
+		}
+	} else {
+		commits, err := git.LogCommits(args...)
+		if err != nil {
+			return fmt.Errorf("could not read history: %w", err)
+		}
+		for i := range commits {
+			lines = append(lines, git.GraphLine{Commit: &commits[i]})
+		}
+	}
+
+	// List the noted commits once, rather than asking git about each commit
+	noted, err := git.CommitsWithNotesSet()
+	if err != nil {
+		return fmt.Errorf("could not list conversations: %w", err)
+	}
+
+	repoRoot, _ := git.GetRepoRoot()
+	entries := []output.LogEntry{}
+	renderer := claude.NewRenderer(os.Stdout)
+	for _, line := range lines {
+		if line.Commit == nil {
+			fmt.Println(line.Graph)
+			continue
+		}
+
+		entry, err := logEntry(*line.Commit, repoRoot, noted)
+		if err != nil {
+			return err
+		}
+		if !out.IsText() {
+			entries = append(entries, entry)
+			continue
+		}
+		printLogEntry(renderer, line.Graph, entry)
+	}
+
+	if !out.IsText() {
+		return out.Write(entries)
+	}
+	return nil
+}
+
+// logEntry describes a commit and digests the conversation since the
+// previous commit of its session. Only commits in noted have one.
+func logEntry(meta git.CommitMeta, repoRoot string, noted map[string]bool) (output.LogEntry, error) {
+	if !noted[meta.SHA] {
+		return output.LogEntry{Commit: commitRecord(meta, nil, output.ChecksumNone)}, nil
+	}
+	conv, err := attribution.LoadCommitConversation(meta.SHA)
+	if err != nil {
+		return output.LogEntry{}, fmt.Errorf("could not read conversation for %s: %w", util.ShortSHA(meta.SHA), err)
+	}
+	if conv == nil {
+		return output.LogEntry{Commit: commitRecord(meta, nil, output.ChecksumNone)}, nil
+	}
+
+	digest := claude.NewDigest(conv.Entries)
+	digest.RelativizeFiles(repoRoot)
+	return output.LogEntry{
+		Commit:    commitRecord(meta, conv.Stored, checksumStatus(conv.Stored)),
+		ParentSHA: conv.ParentSHA,
+		Digest:    &digest,
+	}, nil
+}
+
+// printLogEntry prints a commit line and, for commits with a conversation,
+// its digest. graph is the commit's line of the graph, if drawn.
+func printLogEntry(renderer *claude.Renderer, graph string, entry output.LogEntry) {
+	fmt.Printf("%s%s %s (%s, %s)\n", graph, util.ShortSHA(entry.SHA), entry.Message, entry.Author, entry.Date[:10])
+	if entry.Digest == nil {
+		return
+	}
+
+	// Continue the graph's lines alongside the digest
+	prefix := strings.ReplaceAll(graph, "*", "|")
+	scope := "full session"
+	if entry.ParentSHA != "" {
+		scope = "since " + util.ShortSHA(entry.ParentSHA)
+	}
+	fmt.Printf("%s  Session %s, %d messages (%s)\n", prefix, util.ShortSHA(entry.SessionID), entry.MessageCount, scope)
+	if entry.Digest.IsEmpty() {
+		fmt.Printf("%s  (no new messages)\n", prefix)
+	} else {
+		renderer.RenderDigest(*entry.Digest, prefix)
+	}
+	fmt.Println(strings.TrimRight(prefix, " "))
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/summary"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputFormat   string
+	outputTemplate string
+
+	// out writes the results of commands that support --format
+	out *output.Writer
+)
+
+// annotationOutput marks commands (and their subcommands) that support
+// machine-readable output
+const annotationOutput = "claudit/output"
+
+// machineReadable is the annotation for commands that support --format
+var machineReadable = map[string]string{annotationOutput: "true"}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", output.Text,
+		"Output format: "+strings.Join(output.Formats, ", "))
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "",
+		"Go template for each record, e.g. '{{.sha}} {{.message_count}}' (implies --format template)")
+}
+
+// setupOutput validates --format and --template and creates the writer for
+// the command being run
+func setupOutput(cmd *cobra.Command) error {
+	format := outputFormat
+	if outputTemplate != "" && !cmd.Flags().Changed("format") {
+		format = output.Template
+	}
+	if format != output.Text && !supportsOutput(cmd) {
+		return fmt.Errorf("'%s' does not support --format %s", cmd.CommandPath(), format)
+	}
+
+	w, err := output.New(os.Stdout, format, outputTemplate)
+	if err != nil {
+		return err
+	}
+	out = w
+	return nil
+}
+
+// supportsOutput returns true if a command or one of its parents is
+// annotated as supporting --format
+func supportsOutput(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[annotationOutput] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// commitMeta reads the metadata of a single commit
+func commitMeta(sha string) (git.CommitMeta, error) {
+	metas, err := git.LogCommits("-1", sha)
+	if err != nil {
+		return git.CommitMeta{}, fmt.Errorf("could not read commit %s: %w", util.ShortSHA(sha), err)
+	}
+	if len(metas) == 0 {
+		return git.CommitMeta{}, fmt.Errorf("could not read commit %s", util.ShortSHA(sha))
+	}
+	return metas[0], nil
+}
+
+// commitRecord describes a commit and its stored conversation, if any
+func commitRecord(meta git.CommitMeta, stored *storage.StoredConversation, checksum string) output.Commit {
+	record := output.Commit{
+		SHA:         meta.SHA,
+		Date:        meta.Date.Format(time.RFC3339),
+		Author:      meta.Author,
+		AuthorEmail: meta.AuthorEmail,
+		Message:     meta.Subject,
+		Checksum:    checksum,
+	}
+	if stored != nil {
+		record.SessionID = stored.SessionID
+		record.Branch = stored.GitBranch
+		record.Provider = stored.ProviderName()
+		record.MessageCount = stored.MessageCount
+		record.Aggregate = stored.IsAggregate()
+		record.Summary = summary.Of(stored)
+	}
+	return record
+}
+
+// checksumStatus verifies a stored transcript against its checksum
+func checksumStatus(stored *storage.StoredConversation) string {
+	if ok, err := stored.VerifyIntegrity(); err != nil || !ok {
+		return output.ChecksumInvalid
+	}
+	return output.ChecksumValid
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DanielJonesEB/claudit/internal/cli"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reattachDryRun bool
+	reattachAll    bool
+)
+
+var reattachCmd = &cobra.Command{
+	Use:     "reattach [<ref>|<range>]",
+	Short:   "Restore lost conversation notes using commit trailers",
+	GroupID: "human",
+	Long: `Finds commits that carry a Claude-Session trailer but have no conversation
+note, and reattaches their conversation as a note.
+
+The transcript is taken from another commit's note in the same session, or
+from Claude's local session file. If the commit also carries a
+Claude-Transcript-Checksum trailer, the transcript is truncated to the exact
+state it was in when the commit was made.
+
+Trailers are added by the commit-msg hook installed with
+'claudit init --trailers'.
+
+Examples:
+  claudit reattach                  # Reattach HEAD
+  claudit reattach main~10..main    # Reattach every commit in a range
+  claudit reattach --all --dry-run  # Preview all recoverable commits`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReattach,
+}
+
+func init() {
+	reattachCmd.Flags().BoolVarP(&reattachDryRun, "dry-run", "n", false, "Show what would be reattached without writing notes")
+	reattachCmd.Flags().BoolVar(&reattachAll, "all", false, "Check every commit with a Claude-Session trailer")
+	rootCmd.AddCommand(reattachCmd)
+}
+
+func runReattach(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	commits, err := reattachCandidates(args)
+	if err != nil {
+		return err
+	}
+
+	reattached := 0
+	for _, sha := range commits {
+		if git.HasNote(sha) {
+			continue
+		}
+
+		recovered, err := storage.RecoverConversation(sha)
+		if err != nil {
+			cli.LogWarning("could not recover conversation for %s: %v", sha[:8], err)
+			continue
+		}
+		if recovered == nil {
+			cli.LogDebug("reattach: nothing found for %s", sha[:8])
+			continue
+		}
+
+		status := "verified"
+		if !recovered.Verified {
+			status = "unverified"
+		}
+
+		if reattachDryRun {
+			fmt.Printf("would reattach %s from %s (%d messages, %s)\n", sha[:7], recovered.Source, recovered.Stored.MessageCount, status)
+			reattached++
+			continue
+		}
+
+		summarize(recovered.Stored)
+		noteContent, err := recovered.Stored.Marshal()
+		if err != nil {
+			return fmt.Errorf("failed to marshal conversation: %w", err)
+		}
+		if err := git.AddNote(sha, noteContent); err != nil {
+			return fmt.Errorf("failed to add git note: %w", err)
+		}
+
+		fmt.Printf("reattached %s from %s (%d messages, %s)\n", sha[:7], recovered.Source, recovered.Stored.MessageCount, status)
+		reattached++
+	}
+
+	if reattached == 0 {
+		fmt.Println("no recoverable conversations found")
+	}
+	return nil
+}
+
+// reattachCandidates returns the commits to check for lost conversations
+func reattachCandidates(args []string) ([]string, error) {
+	if reattachAll {
+		trailers, err := git.ListCommitsWithTrailer(git.TrailerSession)
+		if err != nil {
+			return nil, fmt.Errorf("could not read commit trailers: %w", err)
+		}
+		var commits []string
+		for sha := range trailers {
+			commits = append(commits, sha)
+		}
+		return commits, nil
+	}
+
+	ref := "HEAD"
+	if len(args) > 0 {
+		ref = args[0]
+	}
+
+	if git.IsRevisionRange(ref) {
+		commits, err := git.RevList("--reverse", ref)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve revision range '%s': %w", ref, err)
+		}
+		return commits, nil
+	}
+
+	sha, err := git.ResolveRef(ref + "^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve reference '%s': not a valid commit", ref)
+	}
+	return []string{sha}, nil
+}
+
+// logRecovered reports that a conversation was found via trailers rather than a note
+func logRecovered(commitSHA string, recovered *storage.RecoveredConversation) {
+	cli.LogInfo("no note for %s; found conversation via %s trailer in %s", commitSHA[:7], git.TrailerSession, recovered.Source)
+	if !recovered.Verified {
+		cli.LogWarning("transcript does not match the %s trailer; it may include later messages", git.TrailerChecksum)
+	}
+}
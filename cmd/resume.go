@@ -10,7 +10,9 @@ import (
 	"github.com/DanielJonesEB/claudit/internal/claude"
 	"github.com/DanielJonesEB/claudit/internal/cli"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/provider"
 	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/summary"
 	"github.com/spf13/cobra"
 )
 
@@ -72,6 +74,10 @@ func runResume(cmd *cobra.Command, args []string) error {
 
 	cli.LogDebug("resume: session=%s branch=%s messages=%d", stored.SessionID, stored.GitBranch, stored.MessageCount)
 
+	if name := stored.ProviderName(); name != provider.Claude {
+		return fmt.Errorf("conversation for commit %s is from %s; only Claude Code sessions can be resumed", commitSHA[:8], name)
+	}
+
 	// Verify integrity
 	valid, err := stored.VerifyIntegrity()
 	if err != nil {
@@ -118,7 +124,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 		stored.GitBranch,
 		transcriptData,
 		stored.MessageCount,
-		"Restored session",
+		summary.OrDefault(stored, "Restored session"),
 	)
 	if err != nil {
 		return fmt.Errorf("could not restore session: %w", err)
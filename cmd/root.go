@@ -17,7 +17,18 @@ var rootCmd = &cobra.Command{
 	Short: "Store and resume Claude Code conversations as Git Notes",
 	Long: `Claudit captures Claude Code conversation history and stores it as Git Notes
 attached to commits. This enables teams to preserve AI-assisted development
-context alongside their code and resume interrupted sessions.`,
+context alongside their code and resume interrupted sessions.
+
+Commands that report data (list, show, why, blame, doctor, sync and stats)
+accept --format json, jsonl, yaml or template for use in scripts. The
+records follow stable schemas: fields may be added but are never renamed or
+removed. With --template, each record is formatted with a Go template using
+its JSON field names, like 'git log --format':
+
+  claudit list --template '{{short .sha}} {{.session_id}} {{.message_count}}'`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupOutput(cmd)
+	},
 }
 
 func Execute() error {
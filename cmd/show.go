@@ -3,30 +3,108 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/DanielJonesEB/claudit/internal/attribution"
 	"github.com/DanielJonesEB/claudit/internal/claude"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/pager"
+	"github.com/DanielJonesEB/claudit/internal/provider"
+	"github.com/DanielJonesEB/claudit/internal/stats"
 	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/termimage"
+	"github.com/DanielJonesEB/claudit/internal/util"
 	"github.com/spf13/cobra"
 )
 
-var showFull bool
+var (
+	showFull     bool
+	showDiff     bool
+	showTimeline bool
+	showNoPager  bool
+	showFilter   showFilterFlags
+	showExtract  string
+	showTree     bool
+	showBranches bool
+)
+
+// showFilterFlags are the flags that filter the entries shown
+type showFilterFlags struct {
+	only           []string
+	noToolResults  bool
+	tools          []string
+	thinking       string
+	maxResultLines int
+	grep           string
+}
 
 var showCmd = &cobra.Command{
-	Use:     "show [ref]",
-	Short:   "Show conversation history for a commit",
-	GroupID: "human",
+	Use:         "show [ref]",
+	Short:       "Show conversation history for a commit",
+	GroupID:     "human",
+	Annotations: machineReadable,
 	Long: `Displays the Claude Code conversation history stored for a commit.
 
 By default, shows only the conversation since the last commit (incremental view).
 Use --full to see the complete session history.
 
+Use --diff to interleave the commit's diff with the tool calls that produced
+it. Edits that never made it into the commit are flagged, and changes that no
+tool call explains (human edits) are listed at the end.
+
+Use --timeline to show when the session was active or idle and when commits
+were made, instead of the conversation itself.
+
+Edits are shown as unified diffs. Write calls are shown as diffs against
+what an earlier Write or Edit in the conversation left in the file or,
+without --full, against the file at the parent commit. Other Writes show
+the whole content written.
+
+On a terminal the conversation is wrapped to its width and shown through
+the pager git uses ($GIT_PAGER, core.pager, $PAGER or less); use --no-pager
+to print it directly. The assistant's Markdown is rendered, and code in
+Write and Edit calls and Bash commands is syntax highlighted.
+
+Images and documents are shown as placeholders with their type, size and
+dimensions. In terminals that support kitty graphics or sixel, images are
+also drawn inline when the output isn't paged (e.g. with --no-pager). Use
+--extract-attachments <dir> to save them instead of showing the
+conversation.
+
+Long sessions can be narrowed down: --only keeps entries of the given types
+(user, assistant, system), --tools keeps only the calls to the given tools
+and their results, --no-tool-results drops tool results, --max-result-lines
+truncates them, --thinking full, summary or hide sets how much thinking is
+shown, and --grep keeps only the entries matching a regular expression.
+
+Use --tree to follow the conversation's branches: when a message is edited
+or the conversation is rewound, the messages after it are abandoned but
+stay in the transcript. --tree shows only the path that led to the commit,
+with a line where each abandoned branch forked off; add --branches to show
+those branches in full. --tree can't be used with aggregated conversations.
+
 If no ref is provided, shows the conversation for HEAD.
 
+With --format json, jsonl or yaml the conversation is a single record with
+the fields of 'claudit list' plus incremental, parent_sha, entries (the
+transcript entries shown, with the fields claudit reads: uuid, type,
+timestamp, message and so on), source_commits for aggregated conversations,
+diff or timeline when --diff or --timeline is given, and with --tree,
+branches (each abandoned branch's fork_uuid and entries).
+
 Examples:
   claudit show           # Show conversation since last commit
   claudit show --full    # Show full session history
+  claudit show --diff    # Show which tool calls produced the commit's diff
+  claudit show --timeline --full  # Show the timing of the whole session
+  claudit show --only user,assistant --no-tool-results  # Show just the talk
+  claudit show --tools Bash,Edit --thinking hide  # Show commands and edits
+  claudit show --tree --branches  # Show what was tried before an edit
+  claudit show --extract-attachments shots  # Save pasted screenshots
   claudit show abc1234   # Show conversation for specific commit
   claudit show HEAD~1    # Show conversation for previous commit`,
 	Args: cobra.MaximumNArgs(1),
@@ -35,14 +113,37 @@ Examples:
 
 func init() {
 	showCmd.Flags().BoolVarP(&showFull, "full", "f", false, "Show full session history instead of incremental")
+	showCmd.Flags().BoolVar(&showDiff, "diff", false, "Interleave the commit's diff with the tool calls that produced it")
+	showCmd.Flags().BoolVar(&showTimeline, "timeline", false, "Show session timing instead of the conversation")
+	showCmd.Flags().BoolVar(&showNoPager, "no-pager", false, "Don't page the output")
+	showCmd.Flags().StringVar(&showExtract, "extract-attachments", "", "Write the conversation's images and documents to this directory instead of showing it")
+	showCmd.Flags().BoolVar(&showTree, "tree", false, "Show only the path to the commit, marking abandoned branches")
+	showCmd.Flags().BoolVar(&showBranches, "branches", false, "With --tree, show abandoned branches in full")
+	showCmd.Flags().StringSliceVar(&showFilter.only, "only", nil, "Show only entries of these types (user, assistant, system)")
+	showCmd.Flags().BoolVar(&showFilter.noToolResults, "no-tool-results", false, "Hide tool results")
+	showCmd.Flags().StringSliceVar(&showFilter.tools, "tools", nil, "Show only calls to these tools and their results")
+	showCmd.Flags().StringVar(&showFilter.thinking, "thinking", "", "How to show thinking: full, summary or hide")
+	showCmd.Flags().IntVar(&showFilter.maxResultLines, "max-result-lines", 0, "Truncate tool results to this many lines")
+	showCmd.Flags().StringVar(&showFilter.grep, "grep", "", "Show only entries matching this regular expression")
 	rootCmd.AddCommand(showCmd)
 }
 
+// validateShowFlags rejects flags that only apply with others
+func validateShowFlags() error {
+	if showBranches && !showTree {
+		return fmt.Errorf("--branches requires --tree")
+	}
+	return nil
+}
+
 func runShow(cmd *cobra.Command, args []string) error {
 	// Verify we're in a git repository
 	if err := git.RequireGitRepo(); err != nil {
 		return err
 	}
+	if err := validateShowFlags(); err != nil {
+		return err
+	}
 
 	// Determine the ref to show
 	ref := "HEAD"
@@ -61,8 +162,30 @@ func runShow(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("could not read conversation: %w", err)
 	}
-	if stored == nil {
-		return fmt.Errorf("no conversation found for commit %s", fullSHA[:7])
+	checksum := ""
+	if stored != nil {
+		checksum = checksumStatus(stored)
+	} else {
+		// The note may have been lost; fall back to the commit's session trailers
+		recovered, err := storage.RecoverConversation(fullSHA)
+		if err != nil || recovered == nil {
+			return fmt.Errorf("no conversation found for commit %s", fullSHA[:7])
+		}
+		logRecovered(fullSHA, recovered)
+		stored = recovered.Stored
+		checksum = output.ChecksumUnverified
+		if recovered.Verified {
+			checksum = output.ChecksumValid
+		}
+	}
+
+	filters, err := showFilter.options().Filters()
+	if err != nil {
+		return err
+	}
+	if showTree && stored.IsAggregate() {
+		// The sessions of an aggregate are shown one source commit at a time
+		return fmt.Errorf("--tree can't be used with the aggregated conversation of %s", fullSHA[:7])
 	}
 
 	// Parse the transcript
@@ -71,12 +194,28 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not parse transcript: %w", err)
 	}
 
+	// Wrap to the terminal before paging takes it over
+	width := pager.Width()
+	paging := out.IsText() && !showNoPager && showExtract == "" && pager.Enabled()
+	if paging {
+		defer pager.Start()()
+	}
+	// Images can only be drawn straight to the terminal, not through a pager
+	images := termimage.None
+	if !paging && width > 0 {
+		images = termimage.Detect()
+	}
+
+	if stored.IsAggregate() && !showTimeline && showExtract == "" && out.IsText() {
+		return renderAggregate(fullSHA, stored, transcript, width, images, filters)
+	}
+
 	// Find parent conversation boundary (unless --full is specified)
 	var parentSHA string
 	var lastEntryUUID string
 	var isIncremental bool
 
-	if !showFull {
+	if !showFull && !stored.IsAggregate() {
 		parentSHA, lastEntryUUID = storage.FindParentConversationBoundary(fullSHA, stored.SessionID)
 		isIncremental = lastEntryUUID != ""
 	}
@@ -89,21 +228,337 @@ func runShow(cmd *cobra.Command, args []string) error {
 		entries = transcript.Entries
 	}
 
+	// The diff annotation and timeline use every entry, whatever is shown
+	all := entries
+	var tree *claude.Tree
+	var branches []claude.Branch
+	if showTree {
+		tree = claude.NewTree(transcript.Entries)
+		if isIncremental {
+			entries = tree.PathSince(lastEntryUUID)
+		} else {
+			entries = tree.ActivePath()
+		}
+		branches = tree.Branches(entries)
+	}
+	unfiltered := len(entries)
+	entries = claude.ApplyFilters(entries, filters...)
+	if showTree {
+		branches = tree.PlaceBranches(entries, claude.FilterBranches(branches, filters...))
+	}
+
+	if showExtract != "" {
+		return extractAttachments(entries, showExtract)
+	}
+	if !out.IsText() {
+		return writeConversation(fullSHA, stored, checksum, all, entries, branches, parentSHA, isIncremental)
+	}
+
 	// Print header
 	message, date, _ := git.GetCommitInfo(fullSHA)
 	fmt.Printf("Conversation for %s (%s)\n", fullSHA[:7], date[:10])
 	fmt.Printf("Commit: %s\n", message)
+	if name := stored.ProviderName(); name != provider.Claude {
+		fmt.Printf("Provider: %s\n", name)
+	}
+	labels, err := storage.GetLabels(fullSHA)
+	if err != nil {
+		return fmt.Errorf("could not read labels: %w", err)
+	}
+	if len(labels) > 0 {
+		fmt.Printf("Labels: %s\n", strings.Join(labels, ", "))
+	}
 
 	if isIncremental {
 		fmt.Printf("Showing: %d entries since %s\n", len(entries), parentSHA[:7])
 	} else {
 		fmt.Printf("Showing: %d entries (full session)\n", len(entries))
 	}
+	if hidden := unfiltered - len(entries); hidden > 0 {
+		fmt.Printf("Filtered: %d entries hidden\n", hidden)
+	}
+	if len(branches) > 0 {
+		fmt.Printf("Branches: %d abandoned\n", len(branches))
+	}
 
 	fmt.Println(strings.Repeat("─", 60))
 	fmt.Println()
 
+	if showTimeline {
+		return renderTimeline(fullSHA, stored.SessionID, all)
+	}
+
 	// Render the entries
+	renderer := newShowRenderer(width, images)
+	if !showFull {
+		// The parent holds the files as they were before the commit's own
+		// entries, but not before earlier commits' ones
+		renderer.SetPreviousContent(attribution.PreviousContent(fullSHA, stored.ProjectPath))
+	}
+	annotation, err := annotateDiff(renderer, fullSHA, all)
+	if err != nil {
+		return err
+	}
+	if showTree {
+		renderBranches(renderer, branches)
+	}
+	if err := renderer.RenderEntries(entries); err != nil {
+		return err
+	}
+	renderHumanHunks(renderer, annotation)
+	return nil
+}
+
+// renderBranches has the renderer show each branch after the entry it was
+// placed after. Branches placed before every entry are rendered right away.
+func renderBranches(renderer *claude.Renderer, branches []claude.Branch) {
+	after := make(map[string][]claude.Branch)
+	for _, branch := range branches {
+		after[branch.After] = append(after[branch.After], branch)
+	}
+	for _, branch := range after[""] {
+		renderer.RenderBranch(branch, showBranches)
+		fmt.Println()
+	}
+	renderer.SetEntryHook(func(entry *claude.TranscriptEntry) {
+		for _, branch := range after[entry.UUID] {
+			renderer.RenderBranch(branch, showBranches)
+		}
+	})
+}
+
+// options returns the entry filters the flags describe
+func (f showFilterFlags) options() claude.FilterOptions {
+	options := claude.FilterOptions{
+		NoToolResults:  f.noToolResults,
+		Tools:          f.tools,
+		Thinking:       f.thinking,
+		MaxResultLines: f.maxResultLines,
+		Grep:           f.grep,
+	}
+	for _, t := range f.only {
+		options.Only = append(options.Only, claude.MessageType(t))
+	}
+	return options
+}
+
+// newShowRenderer returns a renderer for showing a conversation
+func newShowRenderer(width int, images termimage.Protocol) *claude.Renderer {
 	renderer := claude.NewRenderer(os.Stdout)
-	return renderer.RenderEntries(entries)
+	renderer.SetWidth(width)
+	renderer.SetInlineImages(images)
+	renderer.SetFullThinking(showFilter.thinking == claude.ThinkingFull)
+	return renderer
+}
+
+// extractAttachments writes the images and documents in entries to dir
+func extractAttachments(entries []claude.TranscriptEntry, dir string) error {
+	attachments := claude.Attachments(entries)
+	if len(attachments) == 0 {
+		fmt.Println("No attachments in this conversation")
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create %s: %w", dir, err)
+	}
+
+	extracted := 0
+	for _, a := range attachments {
+		if len(a.Data) == 0 {
+			fmt.Printf("Skipped %s (%s)\n", a.ID, a.Describe())
+			continue
+		}
+		path := filepath.Join(dir, a.FileName())
+		if rel, err := filepath.Rel(dir, path); err != nil || rel != filepath.Base(path) {
+			return fmt.Errorf("attachment %q would be written outside %s", a.ID, dir)
+		}
+		if err := os.WriteFile(path, a.Data, 0644); err != nil {
+			return fmt.Errorf("could not write %s: %w", path, err)
+		}
+		fmt.Printf("Wrote %s (%s)\n", path, a.Describe())
+		extracted++
+	}
+	fmt.Printf("Extracted %d attachments to %s\n", extracted, dir)
+	return nil
+}
+
+// writeConversation writes the conversation record for --format, including
+// the diff annotation and timeline when requested. all holds the entries
+// before filtering, which the annotation and timeline are made from.
+func writeConversation(fullSHA string, stored *storage.StoredConversation, checksum string, all, entries []claude.TranscriptEntry, branches []claude.Branch, parentSHA string, isIncremental bool) error {
+	meta, err := commitMeta(fullSHA)
+	if err != nil {
+		return err
+	}
+
+	record := output.Conversation{
+		Commit:        commitRecord(meta, stored, checksum),
+		Incremental:   isIncremental,
+		Entries:       entries,
+		SourceCommits: stored.SourceCommits,
+		Branches:      branches,
+	}
+	if isIncremental {
+		record.ParentSHA = parentSHA
+	}
+	if record.Labels, err = storage.GetLabels(fullSHA); err != nil {
+		return fmt.Errorf("could not read labels: %w", err)
+	}
+	if showDiff {
+		if record.Diff, err = attribution.AnnotateCommitDiff(fullSHA, all); err != nil {
+			return fmt.Errorf("could not read diff for %s: %w", util.ShortSHA(fullSHA), err)
+		}
+	}
+	if showTimeline {
+		if record.Timeline, err = sessionTimeline(fullSHA, stored.SessionID, all); err != nil {
+			return err
+		}
+	}
+	return out.Write(record)
+}
+
+// renderAggregate renders an aggregated conversation one source commit at a
+// time, wrapped to width
+func renderAggregate(fullSHA string, stored *storage.StoredConversation, transcript *claude.Transcript, width int, images termimage.Protocol, filters []claude.EntryFilter) error {
+	message, date, _ := git.GetCommitInfo(fullSHA)
+	fmt.Printf("Conversation for %s (%s)\n", fullSHA[:7], date[:10])
+	fmt.Printf("Commit: %s\n", message)
+	fmt.Printf("Showing: %d entries aggregated from %d commits\n", len(transcript.Entries), len(stored.SourceCommits))
+
+	renderer := newShowRenderer(width, images)
+	annotation, err := annotateDiff(renderer, fullSHA, transcript.Entries)
+	if err != nil {
+		return err
+	}
+	for _, segment := range stored.Segments(transcript) {
+		fmt.Println(strings.Repeat("─", 60))
+		fmt.Printf("%s %s (session %s)\n", util.ShortSHA(segment.Commit.SHA), segment.Commit.Message, util.ShortSHA(segment.Commit.SessionID))
+		fmt.Println(strings.Repeat("─", 60))
+		fmt.Println()
+		if err := renderer.RenderEntries(claude.ApplyFilters(segment.Entries, filters...)); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	renderHumanHunks(renderer, annotation)
+	return nil
+}
+
+// annotateDiff matches the commit's diff against the entries' tool calls when
+// --diff is given, and hooks the renderer to print each call's hunks
+func annotateDiff(renderer *claude.Renderer, commitSHA string, entries []claude.TranscriptEntry) (*attribution.DiffAnnotation, error) {
+	if !showDiff {
+		return nil, nil
+	}
+	annotation, err := attribution.AnnotateCommitDiff(commitSHA, entries)
+	if err != nil {
+		return nil, fmt.Errorf("could not read diff for %s: %w", util.ShortSHA(commitSHA), err)
+	}
+
+	renderer.SetToolUseHook(func(block claude.ContentBlock) {
+		status := annotation.EditStatus(block.ID)
+		if status == nil {
+			return
+		}
+		if !status.InCommit {
+			renderer.RenderNotice("⚠ edit not in commit")
+			return
+		}
+		for _, hunk := range annotation.HunksFor(block.ID) {
+			renderer.RenderDiff(hunk.File+" "+hunk.Header, hunk.Lines)
+		}
+	})
+	return annotation, nil
+}
+
+// renderHumanHunks lists the changes in the commit that no tool call explains
+func renderHumanHunks(renderer *claude.Renderer, annotation *attribution.DiffAnnotation) {
+	if annotation == nil {
+		return
+	}
+	human := annotation.HumanHunks()
+	if len(human) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("Changes not made by any tool call, likely human edits (%d of %d hunks)\n", len(human), len(annotation.Hunks))
+	fmt.Println(strings.Repeat("─", 60))
+	for _, hunk := range human {
+		renderer.RenderDiff(hunk.File+" "+hunk.Header, hunk.Lines)
+	}
+}
+
+// sessionTimeline builds the timeline of the entries shown for a commit,
+// marking the session's commits made up to and including it
+func sessionTimeline(commitSHA, sessionID string, entries []claude.TranscriptEntry) (*claude.Timeline, error) {
+	timeline := claude.NewTimeline(entries, claude.DefaultIdleThreshold)
+	if timeline == nil {
+		return nil, fmt.Errorf("conversation has no timestamps")
+	}
+	commits, err := stats.SessionCommits(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list session commits: %w", err)
+	}
+	shown, err := commitMeta(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	for _, commit := range commits {
+		// Only commits made from the first entry shown up to this commit belong
+		// on its timeline
+		if !commit.Time.Before(timeline.Start) && !commit.Time.After(shown.Date) {
+			timeline.AddCommits(commit)
+		}
+	}
+	return timeline, nil
+}
+
+// renderTimeline prints when a session was active or idle and when its
+// commits were made
+func renderTimeline(commitSHA, sessionID string, entries []claude.TranscriptEntry) error {
+	timeline, err := sessionTimeline(commitSHA, sessionID, entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Timeline for session %s\n", sessionID)
+	fmt.Printf("  Started:  %s\n", timeline.Start.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Ended:    %s\n", timeline.End.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Duration: %s (active %s, idle %s)\n",
+		formatDuration(timeline.Duration()), formatDuration(timeline.ActiveTime()), formatDuration(timeline.IdleTime()))
+	if d, ok := timeline.TimeToFirstCommit(); ok {
+		fmt.Printf("  First commit after: %s\n", formatDuration(d))
+	}
+	if d, ok := timeline.MeanTimeBetweenCommits(); ok {
+		fmt.Printf("  Time between commits: %s on average\n", formatDuration(d))
+	}
+	fmt.Println()
+
+	type event struct {
+		at   time.Time
+		text string
+	}
+	var events []event
+	for _, span := range timeline.Active {
+		events = append(events, event{span.Start, "▶ active for " + formatDuration(span.Duration())})
+	}
+	for _, gap := range timeline.Gaps {
+		events = append(events, event{gap.Start, "┄ idle for " + formatDuration(gap.Duration())})
+	}
+	for _, commit := range timeline.Commits {
+		message, _, _ := git.GetCommitInfo(commit.SHA)
+		events = append(events, event{commit.Time, fmt.Sprintf("◆ commit %s %s", util.ShortSHA(commit.SHA), message)})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	for _, e := range events {
+		fmt.Printf("  %s  %s\n", e.at.Local().Format("15:04:05"), e.text)
+	}
+	return nil
+}
+
+// formatDuration formats a duration to the nearest second
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
 }
@@ -0,0 +1,26 @@
+package cmd
+
+import "testing"
+
+func TestValidateShowFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		tree, branches bool
+		wantErr        bool
+	}{
+		{name: "neither"},
+		{name: "tree", tree: true},
+		{name: "tree and branches", tree: true, branches: true},
+		{name: "branches without tree", branches: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			showTree, showBranches = tt.tree, tt.branches
+			t.Cleanup(func() { showTree, showBranches = false, false })
+
+			if err := validateShowFlags(); (err != nil) != tt.wantErr {
+				t.Errorf("validateShowFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
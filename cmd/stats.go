@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:         "stats",
+	Short:       "Show statistics about stored conversations",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Reports statistics computed from the conversations stored in the repository.
+
+Every report can be printed as a table (the default), CSV, or any of the
+global formats: json, jsonl, yaml or template.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Reports also accept table (the same as text) and csv
+		switch outputFormat {
+		case "table":
+			outputFormat = output.Text
+		case "csv":
+			statsCSV = true
+			outputFormat = output.Text
+		}
+		return setupOutput(cmd)
+	},
+}
+
+// statsCSV is set by --format csv
+var statsCSV bool
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+// statsTable is a report rendered as rows of cells for table and CSV output,
+// or as JSON straight from the underlying data
+type statsTable struct {
+	Headers []string
+	Rows    [][]string
+	// Footer is an optional last row, e.g. totals; omitted from CSV
+	Footer []string
+	// JSON is the value to encode for --format json, jsonl, yaml or template
+	JSON interface{}
+}
+
+// printStats writes a report in the format selected by --format
+func printStats(table statsTable) error {
+	switch {
+	case statsCSV:
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write(table.Headers); err != nil {
+			return err
+		}
+		if err := w.WriteAll(table.Rows); err != nil {
+			return err
+		}
+		return w.Error()
+	case out.IsText():
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(table.Headers, "\t"))
+		for _, row := range table.Rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		if table.Footer != nil {
+			fmt.Fprintln(w, strings.Join(table.Footer, "\t"))
+		}
+		return w.Flush()
+	}
+	return out.Write(table.JSON)
+}
+
+// statsTableOutput returns true if reports are printed as tables
+func statsTableOutput() bool {
+	return out.IsText() && !statsCSV
+}
+
+// formatPercent formats a fraction between 0 and 1 as a percentage
+func formatPercent(fraction float64) string {
+	return fmt.Sprintf("%.1f%%", fraction*100)
+}
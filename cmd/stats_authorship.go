@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var statsAuthorshipBy string
+
+var statsAuthorshipCmd = &cobra.Command{
+	Use:   "authorship [<range>...]",
+	Short: "Show how many lines were written by Claude vs. humans",
+	Long: `Compares each commit's diff with the Write, Edit, MultiEdit and NotebookEdit
+tool calls in its stored conversation. Added lines that a tool call wrote are
+attributed to Claude; all other added lines (including every line of commits
+without a conversation) are attributed to humans. Blank lines and merge
+commits are not counted.
+
+Commits are selected like 'git log' (HEAD by default); pass git log options
+such as --since after '--'. Use --by to report per commit, per file, or as a
+time series by day, week or month.
+
+Examples:
+  claudit stats authorship
+  claudit stats authorship main..feature --by file
+  claudit stats authorship --by month --format csv -- --since=3.months`,
+	RunE: runStatsAuthorship,
+}
+
+func init() {
+	statsAuthorshipCmd.Flags().StringVar(&statsAuthorshipBy, "by", "commit", "Group by: commit, file, day, week or month")
+	statsCmd.AddCommand(statsAuthorshipCmd)
+}
+
+func runStatsAuthorship(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	switch statsAuthorshipBy {
+	case "commit", "file", "day", "week", "month":
+	default:
+		return fmt.Errorf("unknown grouping %q (expected commit, file, day, week or month)", statsAuthorshipBy)
+	}
+
+	commits, err := git.LogCommits(append([]string{"--no-merges"}, args...)...)
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+
+	results, err := attribution.ForCommits(commits)
+	if err != nil {
+		return fmt.Errorf("could not compute authorship: %w", err)
+	}
+	total := attribution.TotalLines(results)
+
+	switch statsAuthorshipBy {
+	case "commit":
+		return printStats(authorshipByCommit(results, total))
+	case "file":
+		return printStats(authorshipByFile(attribution.ByFile(results), total))
+	default:
+		series, err := attribution.Series(results, statsAuthorshipBy)
+		if err != nil {
+			return err
+		}
+		return printStats(authorshipSeries(series, total))
+	}
+}
+
+// authorshipReport is the JSON form of an authorship report
+type authorshipReport struct {
+	Total   attribution.Lines              `json:"total"`
+	Commits []attribution.CommitAuthorship `json:"commits,omitempty"`
+	Files   []attribution.FileAuthorship   `json:"files,omitempty"`
+	Series  []attribution.PeriodAuthorship `json:"series,omitempty"`
+}
+
+func authorshipByCommit(results []attribution.CommitAuthorship, total attribution.Lines) statsTable {
+	table := statsTable{
+		Headers: []string{"COMMIT", "DATE", "AUTHOR", "AI", "HUMAN", "AI%", "SUBJECT"},
+		JSON:    authorshipReport{Total: total, Commits: results},
+	}
+	for _, r := range results {
+		table.Rows = append(table.Rows, []string{
+			util.ShortSHA(r.SHA), r.Date.Format("2006-01-02"), r.Author,
+			strconv.Itoa(r.AILines), strconv.Itoa(r.HumanLines), formatPercent(r.AIShare()), r.Subject,
+		})
+	}
+	table.Footer = []string{"TOTAL", "", "", strconv.Itoa(total.AILines), strconv.Itoa(total.HumanLines), formatPercent(total.AIShare()), ""}
+	return table
+}
+
+func authorshipByFile(files []attribution.FileAuthorship, total attribution.Lines) statsTable {
+	table := statsTable{
+		Headers: []string{"FILE", "AI", "HUMAN", "AI%"},
+		JSON:    authorshipReport{Total: total, Files: files},
+	}
+	for _, f := range files {
+		table.Rows = append(table.Rows, []string{
+			f.File, strconv.Itoa(f.AILines), strconv.Itoa(f.HumanLines), formatPercent(f.AIShare()),
+		})
+	}
+	table.Footer = []string{"TOTAL", strconv.Itoa(total.AILines), strconv.Itoa(total.HumanLines), formatPercent(total.AIShare())}
+	return table
+}
+
+func authorshipSeries(series []attribution.PeriodAuthorship, total attribution.Lines) statsTable {
+	table := statsTable{
+		Headers: []string{"PERIOD", "COMMITS", "AI", "HUMAN", "AI%"},
+		JSON:    authorshipReport{Total: total, Series: series},
+	}
+	for _, p := range series {
+		table.Rows = append(table.Rows, []string{
+			p.Period, strconv.Itoa(p.Commits), strconv.Itoa(p.AILines), strconv.Itoa(p.HumanLines), formatPercent(p.AIShare()),
+		})
+	}
+	table.Footer = []string{"TOTAL", "", strconv.Itoa(total.AILines), strconv.Itoa(total.HumanLines), formatPercent(total.AIShare())}
+	return table
+}
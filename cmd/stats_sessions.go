@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var statsSessionsIdle time.Duration
+
+var statsSessionsCmd = &cobra.Command{
+	Use:   "sessions [<range>...]",
+	Short: "Show how long sessions took and when they committed",
+	Long: `Groups the conversations of the selected commits by session and reports each
+session's wall-clock duration, how much of it was active or idle, how long it
+took to make its first commit and the average time between its commits.
+
+A gap of more than --idle between consecutive transcript entries counts as
+idle time. Commits are selected like 'git log' (HEAD by default); pass git
+log options such as --since after '--'.
+
+Examples:
+  claudit stats sessions
+  claudit stats sessions --idle 10m --format json -- --since=2.weeks`,
+	RunE: runStatsSessions,
+}
+
+func init() {
+	statsSessionsCmd.Flags().DurationVar(&statsSessionsIdle, "idle", claude.DefaultIdleThreshold, "Gap between entries that counts as idle time")
+	statsCmd.AddCommand(statsSessionsCmd)
+}
+
+func runStatsSessions(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	commits, err := git.LogCommits(args...)
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+
+	sessions, err := stats.Sessions(commits, statsSessionsIdle)
+	if err != nil {
+		return fmt.Errorf("could not read conversations: %w", err)
+	}
+	if sessions == nil {
+		sessions = []stats.SessionSummary{}
+	}
+
+	table := statsTable{
+		Headers: []string{"SESSION", "BRANCH", "START", "DURATION", "ACTIVE", "IDLE", "COMMITS", "FIRST COMMIT", "BETWEEN"},
+		JSON:    sessions,
+	}
+	for _, s := range sessions {
+		tl := s.Timeline
+		firstCommit, between := "-", "-"
+		if d, ok := tl.TimeToFirstCommit(); ok {
+			firstCommit = formatDuration(d)
+		}
+		if d, ok := tl.MeanTimeBetweenCommits(); ok {
+			between = formatDuration(d)
+		}
+		table.Rows = append(table.Rows, []string{
+			s.SessionID, s.Branch, tl.Start.Local().Format("2006-01-02 15:04"),
+			formatDuration(tl.Duration()), formatDuration(tl.ActiveTime()), formatDuration(tl.IdleTime()),
+			strconv.Itoa(len(tl.Commits)), firstCommit, between,
+		})
+	}
+	return printStats(table)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsToolsBy   string
+	statsToolsJSON bool
+)
+
+var statsToolsCmd = &cobra.Command{
+	Use:   "tools [<range>...]",
+	Short: "Show how Claude uses tools in the repository",
+	Long: `Pairs every tool call in the conversations of the selected commits with its
+result and reports:
+
+  - calls and error results per tool
+  - Bash calls per program (e.g. "go test", "git status") and the commands
+    that failed most often
+  - how many commits ran tests before committing, and tool calls per commit
+  - a trend of the above by day, week or month (see --by)
+
+Commits are selected like 'git log' (HEAD by default); pass git log options
+such as --since after '--'. CSV output lists the per-tool counts only; use
+--json for the full report.
+
+Examples:
+  claudit stats tools
+  claudit stats tools --by month --json -- --since=6.months`,
+	RunE: runStatsTools,
+}
+
+func init() {
+	statsToolsCmd.Flags().StringVar(&statsToolsBy, "by", "week", "Trend period: day, week or month")
+	statsToolsCmd.Flags().BoolVar(&statsToolsJSON, "json", false, "Output the full report as JSON (same as --format json)")
+	statsCmd.AddCommand(statsToolsCmd)
+}
+
+func runStatsTools(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+	if statsToolsJSON {
+		w, err := output.New(os.Stdout, output.JSON, "")
+		if err != nil {
+			return err
+		}
+		out = w
+		statsCSV = false
+	}
+
+	commits, err := git.LogCommits(args...)
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+
+	activity, err := stats.ToolActivity(commits)
+	if err != nil {
+		return fmt.Errorf("could not read conversations: %w", err)
+	}
+
+	report, err := stats.NewToolReport(activity, statsToolsBy)
+	if err != nil {
+		return err
+	}
+
+	tools := toolCountTable("TOOL", report.Tools)
+	tools.JSON = report
+	if !statsTableOutput() {
+		return printStats(tools)
+	}
+
+	fmt.Printf("Commits with conversations: %d\n", report.Commits)
+	fmt.Printf("Tool calls: %d (%.1f per commit, %d failed)\n", report.ToolCalls, report.CallsPerCommit, report.Errors)
+	fmt.Printf("Commits that ran tests: %d (%s)\n", report.TestedCommits, formatPercent(report.TestedShare))
+
+	sections := []statsTable{tools, toolCountTable("BASH COMMAND", report.BashCommands)}
+	if len(report.FailedCommands) > 0 {
+		sections = append(sections, toolCountTable("FAILED COMMAND", report.FailedCommands))
+	}
+	sections = append(sections, toolTrendTable(report.Trend))
+	for _, section := range sections {
+		fmt.Println()
+		if err := printStats(section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toolCountTable(label string, counts []stats.ToolCount) statsTable {
+	table := statsTable{Headers: []string{label, "CALLS", "ERRORS", "ERROR%"}}
+	for _, c := range counts {
+		table.Rows = append(table.Rows, []string{
+			c.Name, strconv.Itoa(c.Calls), strconv.Itoa(c.Errors), formatPercent(c.ErrorRate),
+		})
+	}
+	return table
+}
+
+func toolTrendTable(trend []stats.ToolPeriod) statsTable {
+	table := statsTable{Headers: []string{"PERIOD", "COMMITS", "CALLS", "CALLS/COMMIT", "ERRORS", "TESTED"}}
+	for _, p := range trend {
+		table.Rows = append(table.Rows, []string{
+			p.Period, strconv.Itoa(p.Commits), strconv.Itoa(p.ToolCalls),
+			fmt.Sprintf("%.1f", p.CallsPerCommit), strconv.Itoa(p.Errors), strconv.Itoa(p.TestedCommits),
+		})
+	}
+	return table
+}
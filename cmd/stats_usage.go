@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/config"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/stats"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var statsUsageBy string
+
+var statsUsageCmd = &cobra.Command{
+	Use:   "usage [<range>...]",
+	Short: "Show token usage and estimated cost",
+	Long: `Totals the input, output and cache tokens reported by each assistant response
+in the conversations of the selected commits, and estimates their cost.
+
+Commits are selected like 'git log' (HEAD by default); pass git log options
+such as --since after '--'. Each commit counts only the part of its session
+since the previous commit, so sessions spanning several commits are not
+counted twice.
+
+Costs use built-in list prices per model. Override or add prices (in US
+dollars per million tokens, keyed by model name or prefix) in .claudit/config:
+
+  {
+    "pricing": {
+      "claude-sonnet-4": {"input": 3, "output": 15, "cache_write": 3.75, "cache_read": 0.3}
+    }
+  }
+
+Examples:
+  claudit stats usage
+  claudit stats usage main..feature --by session
+  claudit stats usage --by day --format csv -- --since=1.month`,
+	RunE: runStatsUsage,
+}
+
+func init() {
+	statsUsageCmd.Flags().StringVar(&statsUsageBy, "by", "commit", "Group by: commit, session, branch, author, day or model")
+	statsCmd.AddCommand(statsUsageCmd)
+}
+
+func runStatsUsage(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	cfg, err := config.Read()
+	if err != nil {
+		return fmt.Errorf("could not read config: %w", err)
+	}
+	prices := claude.DefaultPrices().With(cfg.Pricing)
+
+	commits, err := git.LogCommits(args...)
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+
+	records, err := stats.UsageRecords(commits)
+	if err != nil {
+		return fmt.Errorf("could not read usage: %w", err)
+	}
+
+	groups, err := stats.GroupUsage(records, statsUsageBy, prices)
+	if err != nil {
+		return err
+	}
+	total := stats.TotalUsage(records, prices)
+
+	table := statsTable{
+		Headers: []string{strings.ToUpper(statsUsageBy), "RESPONSES", "INPUT", "OUTPUT", "CACHE WRITE", "CACHE READ", "COST"},
+		JSON:    usageReport{By: statsUsageBy, Total: total, Groups: groups},
+	}
+	for _, g := range groups {
+		key := g.Key
+		if statsUsageBy == "commit" || statsUsageBy == "session" {
+			key = util.ShortSHA(key)
+		}
+		table.Rows = append(table.Rows, usageRow(key, g))
+	}
+	table.Footer = usageRow("TOTAL", total)
+
+	if err := printStats(table); err != nil {
+		return err
+	}
+	if len(total.UnpricedModels) > 0 && statsTableOutput() {
+		fmt.Printf("\nno price for %s; add it to .claudit/config to include it in costs\n", strings.Join(total.UnpricedModels, ", "))
+	}
+	return nil
+}
+
+// usageReport is the JSON form of a usage report
+type usageReport struct {
+	By     string             `json:"by"`
+	Total  stats.UsageGroup   `json:"total"`
+	Groups []stats.UsageGroup `json:"groups"`
+}
+
+func usageRow(key string, g stats.UsageGroup) []string {
+	return []string{
+		key,
+		strconv.Itoa(g.Responses),
+		strconv.Itoa(g.InputTokens),
+		strconv.Itoa(g.OutputTokens),
+		strconv.Itoa(g.CacheCreationInputTokens),
+		strconv.Itoa(g.CacheReadInputTokens),
+		fmt.Sprintf("$%.2f", g.CostUSD),
+	}
+}
@@ -3,13 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/DanielJonesEB/claudit/internal/claude"
 	"github.com/DanielJonesEB/claudit/internal/cli"
+	"github.com/DanielJonesEB/claudit/internal/config"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/provider"
 	"github.com/DanielJonesEB/claudit/internal/session"
 	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/summary"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +26,12 @@ type PostToolUseHookInput struct {
 	} `json:"tool_input"`
 }
 
-var manualFlag bool
+var (
+	manualFlag      bool
+	storeTranscript string
+	storeProvider   string
+	storeSession    string
+)
 
 var storeCmd = &cobra.Command{
 	Use:     "store",
@@ -35,16 +43,47 @@ as a Git Note if a git commit was detected.
 This command is designed to be called by Claude Code's PostToolUse hook.
 
 With --manual flag, discovers the active session and stores its conversation
-for the most recent commit. Used by the post-commit git hook.`,
+for the most recent commit. Used by the post-commit git hook.
+
+With --transcript, stores the given transcript for the most recent commit.
+This records conversations with other assistants: Aider's
+.aider.chat.history.md, Codex CLI rollouts, chats exported from Cursor as
+Markdown and Continue session files. The format is detected unless given with
+--provider; the session ID defaults to the transcript's file name.
+
+Each conversation is stored with a one-line summary of its first prompt, the
+files edited, the tools used and the final reply, shown by 'claudit list'
+and the web UI. To write summaries another way, e.g. with a model, set a
+shell command in .claudit/config. It is given the transcript on stdin and
+$CLAUDIT_SESSION_ID and $CLAUDIT_PROVIDER, and prints the summary:
+
+  {
+    "summarizer": "llm -s 'Summarize this coding session in one line'"
+  }
+
+Examples:
+  claudit store --transcript .aider.chat.history.md
+  claudit store --transcript ~/.codex/sessions/2025/05/01/rollout-....jsonl
+  claudit store --transcript chat.md --provider cursor --session refactor`,
 	RunE: runStore,
 }
 
 func init() {
 	storeCmd.Flags().BoolVar(&manualFlag, "manual", false, "Manual mode: discover session from active session file or recent sessions")
+	storeCmd.Flags().StringVar(&storeTranscript, "transcript", "", "Store this transcript file for the most recent commit")
+	storeCmd.Flags().StringVar(&storeProvider, "provider", "", "Format of --transcript: "+strings.Join(provider.Names(), ", ")+" (default: detected)")
+	storeCmd.Flags().StringVar(&storeSession, "session", "", "Session ID of --transcript (default: its file name)")
+	storeCmd.MarkFlagsMutuallyExclusive("manual", "transcript")
 	rootCmd.AddCommand(storeCmd)
 }
 
 func runStore(cmd *cobra.Command, args []string) error {
+	if storeTranscript == "" && (storeProvider != "" || storeSession != "") {
+		return fmt.Errorf("--provider and --session require --transcript")
+	}
+	if storeTranscript != "" {
+		return runTranscriptStore()
+	}
 	if manualFlag {
 		return runManualStore()
 	}
@@ -63,7 +102,7 @@ func runHookStore() error {
 	cli.LogDebug("store: tool=%s command=%q session=%s", hook.ToolName, hook.ToolInput.Command, hook.SessionID)
 
 	// Check if this is a git commit command
-	if hook.ToolName != "Bash" || !isGitCommitCommand(hook.ToolInput.Command) {
+	if hook.ToolName != "Bash" || !git.IsCommitCommand(hook.ToolInput.Command) {
 		cli.LogDebug("store: not a git commit command, skipping")
 		return nil // Exit silently for non-commit commands
 	}
@@ -74,7 +113,7 @@ func runHookStore() error {
 		return nil
 	}
 
-	return storeConversation(hook.SessionID, hook.TranscriptPath)
+	return storeConversation(hook.SessionID, hook.TranscriptPath, provider.Claude)
 }
 
 // runManualStore handles the manual (post-commit hook) mode
@@ -105,11 +144,28 @@ func runManualStore() error {
 	}
 
 	cli.LogDebug("store: found session %s", activeSession.SessionID)
-	return storeConversation(activeSession.SessionID, activeSession.TranscriptPath)
+	return storeConversation(activeSession.SessionID, activeSession.TranscriptPath, provider.Claude)
 }
 
-// storeConversation stores a conversation for the HEAD commit with duplicate detection
-func storeConversation(sessionID, transcriptPath string) error {
+// runTranscriptStore stores a transcript named on the command line, which
+// may be from any provider
+func runTranscriptStore() error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	sessionID := storeSession
+	if sessionID == "" {
+		// e.g. .aider.chat.history.md becomes aider.chat.history
+		name := strings.TrimPrefix(filepath.Base(storeTranscript), ".")
+		sessionID = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return storeConversation(sessionID, storeTranscript, storeProvider)
+}
+
+// storeConversation stores a conversation for the HEAD commit with duplicate
+// detection. The transcript's provider is detected if providerName is empty.
+func storeConversation(sessionID, transcriptPath, providerName string) error {
 	// Get HEAD commit
 	headCommit, err := git.GetHeadCommit()
 	if err != nil {
@@ -148,7 +204,17 @@ func storeConversation(sessionID, transcriptPath string) error {
 
 	cli.LogDebug("store: transcript size is %d bytes", len(transcriptData))
 
-	transcript, err := claude.ParseTranscript(strings.NewReader(string(transcriptData)))
+	var p provider.Provider
+	if providerName == "" {
+		p, err = provider.Detect(transcriptData)
+	} else {
+		p, err = provider.Get(providerName)
+	}
+	if err != nil {
+		return err
+	}
+
+	transcript, err := p.Parse(transcriptData)
 	if err != nil {
 		return fmt.Errorf("failed to parse transcript: %w", err)
 	}
@@ -157,7 +223,7 @@ func storeConversation(sessionID, transcriptPath string) error {
 	projectPath, _ := git.GetRepoRoot()
 	branch, _ := git.GetCurrentBranch()
 
-	cli.LogDebug("store: project=%s branch=%s messages=%d", projectPath, branch, transcript.MessageCount())
+	cli.LogDebug("store: provider=%s project=%s branch=%s messages=%d", p.Name(), projectPath, branch, transcript.MessageCount())
 
 	// Create stored conversation
 	stored, err := storage.NewStoredConversation(
@@ -170,6 +236,8 @@ func storeConversation(sessionID, transcriptPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create stored conversation: %w", err)
 	}
+	stored.Provider = p.Name()
+	summarize(stored)
 
 	// Marshal and store as git note
 	noteContent, err := stored.Marshal()
@@ -187,10 +255,25 @@ func storeConversation(sessionID, transcriptPath string) error {
 	return nil
 }
 
-// isGitCommitCommand checks if a command is a git commit
-func isGitCommitCommand(command string) bool {
-	// Simple heuristic: check if command contains "git commit"
-	// This handles: git commit, git commit -m, git commit -am, etc.
-	return strings.Contains(command, "git commit") ||
-		strings.Contains(command, "git-commit")
+// summarize sets a conversation's summary, unless it has one, with the
+// summarizer configured in .claudit/config. If that fails, an offline
+// summary is used instead, so storing never fails for want of one.
+func summarize(stored *storage.StoredConversation) {
+	if stored.Summary != "" {
+		return
+	}
+	var command string
+	if cfg, err := config.Read(); err == nil {
+		command = cfg.Summarizer
+	}
+	s, err := summary.New(command).Summarize(stored)
+	if err != nil && command != "" {
+		cli.LogWarning("%v, using an offline summary", err)
+		s, err = summary.Offline{}.Summarize(stored)
+	}
+	if err != nil {
+		cli.LogDebug("store: could not summarize conversation: %v", err)
+		return
+	}
+	stored.Summary = s
 }
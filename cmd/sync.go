@@ -5,14 +5,23 @@ import (
 
 	"github.com/DanielJonesEB/claudit/internal/cli"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var syncCmd = &cobra.Command{
-	Use:     "sync",
-	Short:   "Sync conversation notes with remote",
-	GroupID: "hooks",
-	Long:    `Sync git notes containing conversations with the remote repository.`,
+	Use:         "sync",
+	Short:       "Sync conversation notes with remote",
+	GroupID:     "hooks",
+	Annotations: machineReadable,
+	Long: `Sync git notes containing conversations with the remote repository.
+
+Labels added with 'claudit tag' are kept in their own notes ref
+(refs/notes/claude-labels) and are synced alongside the conversations.
+Labels added on both sides are merged rather than overwritten.
+
+With --format json, jsonl or yaml the result is a record with the fields
+action (push or pull), remote, ok and error.`,
 }
 
 var syncPushCmd = &cobra.Command{
@@ -47,9 +56,17 @@ func runSyncPush(cmd *cobra.Command, args []string) error {
 	if err := git.PushNotes(syncRemote); err != nil {
 		// Don't fail if there are no notes to push or remote doesn't exist
 		cli.LogWarning("could not push notes: %v", err)
-		return nil
+		return writeSync("push", err)
+	}
+
+	if err := git.PushLabels(syncRemote); err != nil {
+		cli.LogWarning("could not push labels: %v", err)
+		return writeSync("push", err)
 	}
 
+	if !out.IsText() {
+		return writeSync("push", nil)
+	}
 	fmt.Printf("Pushed conversation notes to %s\n", syncRemote)
 	return nil
 }
@@ -64,9 +81,30 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 	if err := git.FetchNotes(syncRemote); err != nil {
 		// Don't fail if there are no notes to fetch or remote doesn't exist
 		cli.LogWarning("could not fetch notes: %v", err)
-		return nil
+		return writeSync("pull", err)
+	}
+
+	if err := git.FetchLabels(syncRemote); err != nil {
+		cli.LogWarning("could not fetch labels: %v", err)
+		return writeSync("pull", err)
 	}
 
+	if !out.IsText() {
+		return writeSync("pull", nil)
+	}
 	fmt.Printf("Fetched conversation notes from %s\n", syncRemote)
 	return nil
 }
+
+// writeSync writes the result of a sync for --format; text output is
+// printed by the caller
+func writeSync(action string, syncErr error) error {
+	if out.IsText() {
+		return nil
+	}
+	result := output.Sync{Action: action, Remote: syncRemote, OK: syncErr == nil}
+	if syncErr != nil {
+		result.Error = syncErr.Error()
+	}
+	return out.Write(result)
+}
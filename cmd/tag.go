@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/cli"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var untagAll bool
+
+var tagCmd = &cobra.Command{
+	Use:         "tag <ref> [<label>...]",
+	Short:       "Label a commit's conversation",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Adds labels to the conversation of a commit, e.g. to mark it as a good
+example, an incident or needing review, or to link it to a ticket. Without
+labels, lists the conversation's labels.
+
+Labels are stored in their own notes ref, ` + git.LabelsRef + `,
+so labelling never rewrites the conversation. 'claudit sync' pushes and
+fetches them along with the conversations, merging labels added on either
+side. Labels can hold spaces but not line breaks.
+
+Use 'claudit list --label' or the web UI to find conversations by label.
+
+With --format json, jsonl or yaml the result is a record with the fields sha
+and labels.
+
+Examples:
+  claudit tag HEAD "good example"
+  claudit tag abc1234 incident INC-1234
+  claudit tag HEAD       # List HEAD's labels`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTag,
+}
+
+var untagCmd = &cobra.Command{
+	Use:         "untag <ref> [<label>...]",
+	Short:       "Remove labels from a commit's conversation",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Removes labels given with 'claudit tag' from the conversation of a commit,
+or all of them with --all.
+
+Labels from different clones are merged by keeping every label either side
+has, so if another clone also changed the commit's labels before you sync,
+a label removed here comes back. Remove it again after 'claudit sync pull'.
+
+With --format json, jsonl or yaml the result is a record with the fields sha
+and labels (those left).
+
+Examples:
+  claudit untag HEAD "needs review"
+  claudit untag abc1234 --all`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runUntag,
+}
+
+func init() {
+	untagCmd.Flags().BoolVar(&untagAll, "all", false, "Remove every label")
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(untagCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	sha, err := resolveLabelled(args[0])
+	if err != nil {
+		return err
+	}
+
+	var labels []string
+	if len(args) == 1 {
+		labels, err = storage.GetLabels(sha)
+	} else {
+		labels, err = storage.AddLabels(sha, args[1:])
+	}
+	if err != nil {
+		return fmt.Errorf("could not label conversation: %w", err)
+	}
+	return writeLabels(sha, labels)
+}
+
+func runUntag(cmd *cobra.Command, args []string) error {
+	remove := args[1:]
+	if len(remove) == 0 && !untagAll {
+		return fmt.Errorf("no labels given (use --all to remove every label)")
+	}
+	if len(remove) > 0 && untagAll {
+		return fmt.Errorf("--all can't be combined with labels")
+	}
+
+	sha, err := resolveLabelled(args[0])
+	if err != nil {
+		return err
+	}
+	current, err := storage.GetLabels(sha)
+	if err != nil {
+		return fmt.Errorf("could not read labels: %w", err)
+	}
+	for _, label := range remove {
+		if !storage.HasLabel(current, label) {
+			cli.LogWarning("%s has no label %q", util.ShortSHA(sha), label)
+		}
+	}
+
+	labels, err := storage.RemoveLabels(sha, remove)
+	if err != nil {
+		return fmt.Errorf("could not remove labels: %w", err)
+	}
+	return writeLabels(sha, labels)
+}
+
+// resolveLabelled resolves the commit whose conversation is being labelled
+func resolveLabelled(ref string) (string, error) {
+	if err := git.RequireGitRepo(); err != nil {
+		return "", err
+	}
+	sha, err := git.ResolveRef(ref)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve reference '%s': not a valid commit", ref)
+	}
+	if !git.HasNote(sha) {
+		return "", fmt.Errorf("no conversation found for commit %s", util.ShortSHA(sha))
+	}
+	return sha, nil
+}
+
+// writeLabels prints a commit's labels, or writes them for --format
+func writeLabels(sha string, labels []string) error {
+	if !out.IsText() {
+		if labels == nil {
+			labels = []string{}
+		}
+		return out.Write(output.Labels{SHA: sha, Labels: labels})
+	}
+	if len(labels) == 0 {
+		fmt.Printf("%s has no labels\n", util.ShortSHA(sha))
+		return nil
+	}
+	fmt.Printf("%s: %s\n", util.ShortSHA(sha), strings.Join(labels, ", "))
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DanielJonesEB/claudit/internal/pager"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var textconvCmd = &cobra.Command{
+	Use:     "textconv",
+	Short:   "Shorten conversation notes in git log output",
+	GroupID: "hooks",
+	Long: `Reads the output of git log or git show from stdin and writes it with each
+conversation note shortened to its header: the session, its number of
+messages and the first prompt. Other notes are left alone.
+
+'claudit init --log-pager' sets it as git's pager for log and show
+(pager.log and pager.show), unless they already have one. The output is
+then paged the way git would have. To undo it:
+
+  git config --unset pager.log
+  git config --unset pager.show
+
+Examples:
+  git log --notes=claude-conversations | claudit textconv
+  git config pager.log 'claudit textconv'`,
+	Args: cobra.NoArgs,
+	RunE: runTextconv,
+}
+
+func init() {
+	rootCmd.AddCommand(textconvCmd)
+}
+
+func runTextconv(cmd *cobra.Command, args []string) error {
+	// Run as git's pager, stdout is the terminal git would have paged to
+	defer pager.Start()()
+	if err := storage.RewriteNotes(os.Stdin, os.Stdout); err != nil {
+		return fmt.Errorf("could not rewrite notes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/tui"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:     "tui",
+	Short:   "Browse conversations in a full-screen terminal UI",
+	GroupID: "human",
+	Long: `Opens a full-screen browser of the commits with stored conversations. The
+commit list is on the left and the selected commit's conversation on the
+right, in the colors of 'claudit show'. Thinking and tool blocks are shown
+as one line until expanded.
+
+Like 'claudit show', the conversation since the previous commit of the same
+session is shown; press i to switch to the full session.
+
+Keys:
+  j/k, ↑/↓        move through commits or the conversation
+  tab             switch between the commit list and the conversation
+  enter           expand or collapse the block under the cursor
+  e               expand or collapse all blocks
+  i               switch between the incremental and full conversation
+  /, n, N         search the conversation, next and previous match
+  d               show the commit's diff with 'git show'
+  r               resume the commit's session (see 'claudit resume')
+  q               quit`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	shas, err := git.ListCommitsWithNotes()
+	if err != nil {
+		return fmt.Errorf("could not list commits: %w", err)
+	}
+	if len(shas) == 0 {
+		return fmt.Errorf("no conversations found")
+	}
+	metas, err := git.LogCommitsOf(shas, "--no-walk=unsorted")
+	if err != nil {
+		return fmt.Errorf("could not read history: %w", err)
+	}
+
+	commits := make([]tui.Commit, 0, len(metas))
+	for _, meta := range metas {
+		commits = append(commits, tui.Commit{SHA: meta.SHA, Subject: meta.Subject, Date: meta.Date})
+	}
+
+	action, err := tui.Run(commits, loadTUIConversation, showCommitDiff)
+	if err != nil {
+		return err
+	}
+	if action.Resume != "" {
+		return runResume(cmd, []string{action.Resume})
+	}
+	return nil
+}
+
+// loadTUIConversation loads a commit's conversation for the TUI
+func loadTUIConversation(sha string, full bool) (*tui.Conversation, error) {
+	conv, err := attribution.LoadCommitConversation(sha)
+	if err != nil {
+		return nil, err
+	}
+	if conv == nil {
+		return nil, fmt.Errorf("no conversation found for commit %s", util.ShortSHA(sha))
+	}
+
+	result := &tui.Conversation{
+		SessionID: conv.Stored.SessionID,
+		Provider:  conv.Stored.ProviderName(),
+		Entries:   conv.Entries,
+		ParentSHA: conv.ParentSHA,
+	}
+	if full {
+		result.Entries = conv.Transcript.Entries
+		result.ParentSHA = ""
+	}
+	return result, nil
+}
+
+// showCommitDiff shows a commit with git show, through git's pager
+func showCommitDiff(sha string) error {
+	show := exec.Command("git", "show", sha)
+	show.Stdin = os.Stdin
+	show.Stdout = os.Stdout
+	show.Stderr = os.Stderr
+	if err := show.Run(); err != nil {
+		return fmt.Errorf("could not show commit %s: %w", util.ShortSHA(sha), err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/output"
+	"github.com/DanielJonesEB/claudit/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var whyCmd = &cobra.Command{
+	Use:         "why [<rev>] <file>:<line>",
+	Short:       "Show the conversation that produced a line of code",
+	GroupID:     "human",
+	Annotations: machineReadable,
+	Long: `Finds the last commit that changed a line (using 'git log -L'), locates the
+tool call in that commit's conversation that introduced it, and shows only
+the surrounding exchange: the user message that triggered the change through
+the result of the tool call.
+
+With --format json, jsonl or yaml the exchange is a record with the fields
+file, line, the commit fields of 'claudit list', tool, tool_use_id and
+entries.
+
+Examples:
+  claudit why main.go:42
+  claudit why HEAD~3 internal/server.go:17`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runWhy,
+}
+
+func init() {
+	rootCmd.AddCommand(whyCmd)
+}
+
+func runWhy(cmd *cobra.Command, args []string) error {
+	if err := git.RequireGitRepo(); err != nil {
+		return err
+	}
+
+	rev, location := "", args[0]
+	if len(args) == 2 {
+		rev, location = args[0], args[1]
+	}
+
+	file, line, err := parseFileLine(location)
+	if err != nil {
+		return err
+	}
+
+	origin, err := attribution.Why(rev, file, line)
+	if err != nil {
+		return fmt.Errorf("could not trace %s:%d: %w", file, line, err)
+	}
+
+	if !out.IsText() {
+		return writeOrigin(file, line, origin)
+	}
+
+	message, date, _ := git.GetCommitInfo(origin.CommitSHA)
+	fmt.Printf("%s:%d was last changed by %s (%s)\n", file, line, util.ShortSHA(origin.CommitSHA), date[:10])
+	fmt.Printf("Commit: %s\n", message)
+
+	if err := requireEdit(file, line, origin); err != nil {
+		return err
+	}
+
+	fmt.Printf("Session: %s (%s call)\n", origin.Conversation.Stored.SessionID, origin.Edit.Tool)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Println()
+
+	renderer := claude.NewRenderer(os.Stdout)
+	return renderer.RenderEntries(origin.Exchange)
+}
+
+// writeOrigin writes the origin record of a line for --format
+func writeOrigin(file string, line int, origin *attribution.Origin) error {
+	if err := requireEdit(file, line, origin); err != nil {
+		return err
+	}
+	meta, err := commitMeta(origin.CommitSHA)
+	if err != nil {
+		return err
+	}
+	stored := origin.Conversation.Stored
+	return out.Write(output.Origin{
+		File:      file,
+		Line:      line,
+		Commit:    commitRecord(meta, stored, checksumStatus(stored)),
+		Tool:      origin.Edit.Tool,
+		ToolUseID: origin.Edit.ToolUseID,
+		Entries:   origin.Exchange,
+	})
+}
+
+// requireEdit returns an error if no tool call in a conversation explains
+// the line
+func requireEdit(file string, line int, origin *attribution.Origin) error {
+	if origin.Conversation == nil {
+		return fmt.Errorf("no conversation found for commit %s", util.ShortSHA(origin.CommitSHA))
+	}
+	if origin.Edit == nil {
+		return fmt.Errorf("no tool call in session %s wrote %s:%d", util.ShortSHA(origin.Conversation.Stored.SessionID), file, line)
+	}
+	return nil
+}
+
+// parseFileLine splits a "<file>:<line>" argument
+func parseFileLine(location string) (string, int, error) {
+	i := strings.LastIndex(location, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("expected <file>:<line>, got %q", location)
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("invalid line number in %q", location)
+	}
+	return location[:i], line, nil
+}
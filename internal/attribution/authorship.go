@@ -0,0 +1,188 @@
+package attribution
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+// Lines counts added lines by who wrote them. Blank lines are not counted.
+type Lines struct {
+	AILines    int `json:"ai_lines"`
+	HumanLines int `json:"human_lines"`
+}
+
+// Total returns the number of lines counted
+func (l Lines) Total() int {
+	return l.AILines + l.HumanLines
+}
+
+// AIShare returns the fraction of lines written by tool calls, between 0 and 1
+func (l Lines) AIShare() float64 {
+	if l.Total() == 0 {
+		return 0
+	}
+	return float64(l.AILines) / float64(l.Total())
+}
+
+func (l *Lines) add(other Lines) {
+	l.AILines += other.AILines
+	l.HumanLines += other.HumanLines
+}
+
+// FileAuthorship is the authorship of the lines added to a file
+type FileAuthorship struct {
+	File string `json:"file"`
+	Lines
+}
+
+// CommitAuthorship is the authorship of the lines a commit added
+type CommitAuthorship struct {
+	SHA             string    `json:"sha"`
+	Author          string    `json:"author"`
+	Date            time.Time `json:"date"`
+	Subject         string    `json:"subject"`
+	HasConversation bool      `json:"has_conversation"`
+	Lines
+	Files []FileAuthorship `json:"files"`
+}
+
+// PeriodAuthorship is the authorship of the lines added in a period of time
+type PeriodAuthorship struct {
+	// Period is the first day of the period, formatted as YYYY-MM-DD
+	Period  string `json:"period"`
+	Commits int    `json:"commits"`
+	Lines
+}
+
+// forCommit works out which of the lines a commit added were written by the
+// tool calls of its conversation and which by humans. Commits without a
+// conversation, as noted says, are attributed entirely to humans.
+func forCommit(meta git.CommitMeta, noted bool) (*CommitAuthorship, error) {
+	hunks, err := git.CommitDiff(meta.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CommitAuthorship{
+		SHA:     meta.SHA,
+		Author:  meta.Author,
+		Date:    meta.Date,
+		Subject: meta.Subject,
+		Files:   []FileAuthorship{},
+	}
+
+	var entries []claude.TranscriptEntry
+	if noted {
+		conv, err := LoadCommitConversation(meta.SHA)
+		if err != nil {
+			return nil, err
+		}
+		if conv != nil {
+			result.HasConversation = true
+			entries = conv.Entries
+		}
+	}
+	annotation := AnnotateDiff(hunks, entries)
+
+	files := make(map[string]*Lines)
+	var order []string
+	for _, hunk := range annotation.Hunks {
+		written := 0
+		for _, line := range hunk.Added() {
+			if trimLine(line) != "" {
+				written++
+			}
+		}
+		lines := Lines{AILines: written - len(hunk.Unexplained), HumanLines: len(hunk.Unexplained)}
+
+		if files[hunk.File] == nil {
+			files[hunk.File] = &Lines{}
+			order = append(order, hunk.File)
+		}
+		files[hunk.File].add(lines)
+		result.add(lines)
+	}
+
+	for _, file := range order {
+		result.Files = append(result.Files, FileAuthorship{File: file, Lines: *files[file]})
+	}
+	return result, nil
+}
+
+// ForCommits computes the authorship of each commit
+func ForCommits(commits []git.CommitMeta) ([]CommitAuthorship, error) {
+	// List the noted commits once, rather than asking git about each commit
+	noted, err := git.CommitsWithNotesSet()
+	if err != nil {
+		return nil, fmt.Errorf("could not list conversations: %w", err)
+	}
+
+	results := make([]CommitAuthorship, 0, len(commits))
+	for _, meta := range commits {
+		result, err := forCommit(meta, noted[meta.SHA])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.SHA[:7], err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// TotalLines sums the authorship of the commits
+func TotalLines(commits []CommitAuthorship) Lines {
+	var total Lines
+	for _, commit := range commits {
+		total.add(commit.Lines)
+	}
+	return total
+}
+
+// ByFile sums the authorship of the commits per file, sorted by path
+func ByFile(commits []CommitAuthorship) []FileAuthorship {
+	files := make(map[string]*Lines)
+	for _, commit := range commits {
+		for _, file := range commit.Files {
+			if files[file.File] == nil {
+				files[file.File] = &Lines{}
+			}
+			files[file.File].add(file.Lines)
+		}
+	}
+
+	result := make([]FileAuthorship, 0, len(files))
+	for file, lines := range files {
+		result = append(result, FileAuthorship{File: file, Lines: *lines})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].File < result[j].File })
+	return result
+}
+
+// Series sums the authorship of the commits per day, week (starting Monday)
+// or month, oldest first
+func Series(commits []CommitAuthorship, interval string) ([]PeriodAuthorship, error) {
+	periods := make(map[string]*PeriodAuthorship)
+	for _, commit := range commits {
+		start, err := util.PeriodStart(commit.Date, interval)
+		if err != nil {
+			return nil, err
+		}
+		key := start.Format("2006-01-02")
+		if periods[key] == nil {
+			periods[key] = &PeriodAuthorship{Period: key}
+		}
+		periods[key].Commits++
+		periods[key].add(commit.Lines)
+	}
+
+	result := make([]PeriodAuthorship, 0, len(periods))
+	for _, period := range periods {
+		result = append(result, *period)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period < result[j].Period })
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package attribution
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeries(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	commits := []CommitAuthorship{
+		{Date: day("2024-03-06"), Lines: Lines{AILines: 10, HumanLines: 2}}, // Wednesday
+		{Date: day("2024-03-04"), Lines: Lines{AILines: 1, HumanLines: 1}},  // Monday
+		{Date: day("2024-03-03"), Lines: Lines{HumanLines: 5}},              // Sunday
+		{Date: day("2024-02-29"), Lines: Lines{AILines: 4}},
+	}
+
+	tests := []struct {
+		interval string
+		want     []PeriodAuthorship
+	}{
+		{"week", []PeriodAuthorship{
+			{Period: "2024-02-26", Commits: 2, Lines: Lines{AILines: 4, HumanLines: 5}},
+			{Period: "2024-03-04", Commits: 2, Lines: Lines{AILines: 11, HumanLines: 3}},
+		}},
+		{"month", []PeriodAuthorship{
+			{Period: "2024-02-01", Commits: 1, Lines: Lines{AILines: 4}},
+			{Period: "2024-03-01", Commits: 3, Lines: Lines{AILines: 11, HumanLines: 8}},
+		}},
+	}
+	for _, tc := range tests {
+		got, err := Series(commits, tc.interval)
+		if err != nil {
+			t.Fatalf("Series(%s) failed: %v", tc.interval, err)
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("Series(%s) = %+v, want %+v", tc.interval, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("Series(%s)[%d] = %+v, want %+v", tc.interval, i, got[i], tc.want[i])
+			}
+		}
+	}
+
+	if _, err := Series(commits, "fortnight"); err == nil {
+		t.Error("Series() should reject unknown intervals")
+	}
+}
+
+func TestByFile(t *testing.T) {
+	commits := []CommitAuthorship{
+		{Files: []FileAuthorship{{File: "b.go", Lines: Lines{AILines: 3}}, {File: "a.go", Lines: Lines{HumanLines: 1}}}},
+		{Files: []FileAuthorship{{File: "b.go", Lines: Lines{AILines: 1, HumanLines: 2}}}},
+	}
+
+	got := ByFile(commits)
+	want := []FileAuthorship{
+		{File: "a.go", Lines: Lines{HumanLines: 1}},
+		{File: "b.go", Lines: Lines{AILines: 4, HumanLines: 2}},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ByFile() = %+v, want %+v", got, want)
+	}
+	if share := got[1].AIShare(); share < 0.66 || share > 0.67 {
+		t.Errorf("AIShare() = %v, want 2/3", share)
+	}
+}
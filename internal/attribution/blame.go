@@ -0,0 +1,101 @@
+package attribution
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// BlameHunk is a run of consecutive lines last changed by the same commit
+type BlameHunk struct {
+	CommitSHA string `json:"commit_sha"`
+	Summary   string `json:"summary"`
+	Author    string `json:"author"`
+	Date      string `json:"date"`
+	// StartLine is the 1-based line number of the first line of the hunk
+	StartLine int      `json:"start_line"`
+	Lines     []string `json:"lines"`
+	// SessionID is set when the commit has a stored conversation
+	SessionID string       `json:"session_id,omitempty"`
+	Edit      *EditContext `json:"edit,omitempty"`
+}
+
+// uncommittedSHA is the commit git blame reports for lines not yet committed
+const uncommittedSHA = "0000000000000000000000000000000000000000"
+
+// Blame annotates a file like git blame, and for commits with a stored
+// conversation, finds the tool call that wrote each hunk along with the
+// user prompt and assistant explanation around it.
+func Blame(rev, file string) ([]BlameHunk, error) {
+	lines, err := git.Blame(rev, file)
+	if err != nil {
+		return nil, err
+	}
+
+	relPath := RepoRelativePath(file)
+	conversations := make(cache)
+
+	var hunks []BlameHunk
+	for _, line := range lines {
+		if n := len(hunks); n > 0 && hunks[n-1].CommitSHA == line.CommitSHA &&
+			hunks[n-1].StartLine+len(hunks[n-1].Lines) == line.LineNumber {
+			hunks[n-1].Lines = append(hunks[n-1].Lines, line.Text)
+			continue
+		}
+		hunks = append(hunks, BlameHunk{
+			CommitSHA: line.CommitSHA,
+			Summary:   line.Summary,
+			Author:    line.Author,
+			Date:      time.Unix(line.AuthorTime, 0).Format("2006-01-02"),
+			StartLine: line.LineNumber,
+			Lines:     []string{line.Text},
+		})
+	}
+
+	for i := range hunks {
+		if hunks[i].CommitSHA == uncommittedSHA {
+			continue
+		}
+		conv := conversations.get(hunks[i].CommitSHA)
+		if conv == nil {
+			continue
+		}
+		hunks[i].SessionID = conv.Stored.SessionID
+		hunks[i].Edit = conv.FindEdit(relPath, hunks[i].Lines)
+	}
+
+	return hunks, nil
+}
+
+// RepoRelativePath converts a path given on the command line (relative to
+// the working directory) into a path relative to the repository root.
+// Paths that can't be resolved are returned unchanged.
+func RepoRelativePath(file string) string {
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return file
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return file
+	}
+	// Resolve symlinks so /tmp and /private/tmp compare equal on macOS
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// trimLine normalizes a line for comparison between diffs and tool calls
+func trimLine(line string) string {
+	return strings.TrimSpace(line)
+}
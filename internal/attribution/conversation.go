@@ -0,0 +1,139 @@
+// Package attribution links lines of code to the conversations that produced them.
+package attribution
+
+import (
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+)
+
+// CommitConversation is a commit's stored conversation together with the
+// entries that belong to that commit alone
+type CommitConversation struct {
+	SHA        string
+	Stored     *storage.StoredConversation
+	Transcript *claude.Transcript
+	// Entries is the part of the transcript since the previous commit of the
+	// same session (the whole transcript for the first commit of a session)
+	Entries []claude.TranscriptEntry
+	// ParentSHA is the previous commit of the same session, if Entries only
+	// holds the entries since then
+	ParentSHA string
+}
+
+// LoadCommitConversation reads a commit's conversation and works out its
+// incremental entries. Returns nil, nil if the commit has no conversation.
+func LoadCommitConversation(commitSHA string) (*CommitConversation, error) {
+	stored, err := storage.GetStoredConversation(commitSHA)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+
+	transcript, err := stored.ParseTranscript()
+	if err != nil {
+		return nil, err
+	}
+
+	conv := &CommitConversation{
+		SHA:        commitSHA,
+		Stored:     stored,
+		Transcript: transcript,
+		Entries:    transcript.Entries,
+	}
+
+	// Every entry of an aggregate belongs to the commit it is attached to
+	if !stored.IsAggregate() {
+		if parentSHA, lastUUID := storage.FindParentConversationBoundary(commitSHA, stored.SessionID); lastUUID != "" {
+			conv.Entries = transcript.GetEntriesSince(lastUUID)
+			conv.ParentSHA = parentSHA
+		}
+	}
+
+	return conv, nil
+}
+
+// EditContext is a tool call that wrote some lines of a file, together with
+// the conversation around it
+type EditContext struct {
+	Tool        string `json:"tool"`
+	ToolUseID   string `json:"tool_use_id"`
+	FilePath    string `json:"file_path"`
+	EntryUUID   string `json:"entry_uuid"`
+	Prompt      string `json:"prompt,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// FindEdit finds the tool call in the conversation that most likely wrote
+// the given lines of a file: the one whose new text contains the most of
+// them, preferring later calls on ties. The commit's own entries are
+// searched before the rest of the session. Returns nil if nothing matches.
+func (c *CommitConversation) FindEdit(relPath string, lines []string) *EditContext {
+	wanted := make(map[string]bool)
+	for _, line := range lines {
+		if trimmed := trimLine(line); trimmed != "" {
+			wanted[trimmed] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	searches := [][]claude.TranscriptEntry{c.Entries}
+	if len(c.Entries) != len(c.Transcript.Entries) {
+		searches = append(searches, c.Transcript.Entries)
+	}
+
+	for _, entries := range searches {
+		edit := bestEdit(entries, relPath, wanted)
+		if edit == nil {
+			continue
+		}
+		return &EditContext{
+			Tool:        edit.Tool,
+			ToolUseID:   edit.ToolUseID,
+			FilePath:    edit.FilePath,
+			EntryUUID:   edit.EntryUUID,
+			Prompt:      claude.PromptFor(entries, edit.EntryIndex),
+			Explanation: claude.ExplanationFor(entries, edit.EntryIndex),
+		}
+	}
+	return nil
+}
+
+// bestEdit returns the edit to relPath covering the most wanted lines
+func bestEdit(entries []claude.TranscriptEntry, relPath string, wanted map[string]bool) *claude.FileEdit {
+	var best *claude.FileEdit
+	bestScore := 0
+	edits := claude.ExtractFileEdits(entries)
+	for i := range edits {
+		if !edits[i].MatchesPath(relPath) {
+			continue
+		}
+		score := 0
+		for _, line := range edits[i].NewLines() {
+			if wanted[line] {
+				score++
+			}
+		}
+		if score > 0 && score >= bestScore {
+			best = &edits[i]
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// cache memoizes commit conversations while walking history
+type cache map[string]*CommitConversation
+
+// get returns the commit's conversation, or nil if it has none or it can't be read
+func (c cache) get(commitSHA string) *CommitConversation {
+	if conv, ok := c[commitSHA]; ok {
+		return conv
+	}
+	conv, err := LoadCommitConversation(commitSHA)
+	if err != nil {
+		conv = nil
+	}
+	c[commitSHA] = conv
+	return conv
+}
@@ -0,0 +1,173 @@
+package attribution
+
+import (
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// AnnotatedHunk is a hunk of a commit's diff together with the tool calls
+// that wrote it
+type AnnotatedHunk struct {
+	git.DiffHunk
+	// ToolUseIDs are the tool calls whose edits appear in the hunk
+	ToolUseIDs []string `json:"tool_use_ids,omitempty"`
+	// Unexplained holds added lines that no tool call wrote
+	Unexplained []string `json:"unexplained,omitempty"`
+	// Human is true when no tool call explains any part of the hunk
+	Human bool `json:"human"`
+}
+
+// EditStatus records whether a tool call's edit made it into the commit
+type EditStatus struct {
+	Tool      string `json:"tool"`
+	ToolUseID string `json:"tool_use_id"`
+	FilePath  string `json:"file_path"`
+	EntryUUID string `json:"entry_uuid"`
+	InCommit  bool   `json:"in_commit"`
+}
+
+// DiffAnnotation matches a commit's diff against the tool calls of its conversation
+type DiffAnnotation struct {
+	Hunks []AnnotatedHunk `json:"hunks"`
+	Edits []EditStatus    `json:"edits"`
+}
+
+// AnnotateCommitDiff matches the diff of a commit against the file edits in entries
+func AnnotateCommitDiff(commitSHA string, entries []claude.TranscriptEntry) (*DiffAnnotation, error) {
+	hunks, err := git.CommitDiff(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	return AnnotateDiff(hunks, entries), nil
+}
+
+// AnnotateDiff matches diff hunks against the file edits in entries. An edit
+// explains a hunk when it targets the hunk's file and wrote at least one of
+// the lines the hunk adds; edits that only delete text explain hunks that
+// remove the text they replaced.
+func AnnotateDiff(hunks []git.DiffHunk, entries []claude.TranscriptEntry) *DiffAnnotation {
+	edits := claude.ExtractFileEdits(entries)
+	annotation := &DiffAnnotation{Hunks: []AnnotatedHunk{}, Edits: []EditStatus{}}
+
+	inCommit := make(map[int]bool)
+	for _, hunk := range hunks {
+		annotated := AnnotatedHunk{DiffHunk: hunk}
+		written := make(map[string]bool)
+		added := lineSet(hunk.Added())
+		removed := lineSet(hunk.Removed())
+
+		for i, edit := range edits {
+			if !edit.MatchesPath(hunk.File) || !explains(edit, added, removed) {
+				continue
+			}
+			inCommit[i] = true
+			annotated.ToolUseIDs = appendUnique(annotated.ToolUseIDs, edit.ToolUseID)
+			for _, line := range edit.NewLines() {
+				written[line] = true
+			}
+		}
+
+		for _, line := range hunk.Added() {
+			if trimmed := trimLine(line); trimmed != "" && !written[trimmed] {
+				annotated.Unexplained = append(annotated.Unexplained, line)
+			}
+		}
+		annotated.Human = len(annotated.ToolUseIDs) == 0
+		annotation.Hunks = append(annotation.Hunks, annotated)
+	}
+
+	for i, edit := range edits {
+		annotation.Edits = append(annotation.Edits, EditStatus{
+			Tool:      edit.Tool,
+			ToolUseID: edit.ToolUseID,
+			FilePath:  edit.FilePath,
+			EntryUUID: edit.EntryUUID,
+			InCommit:  inCommit[i],
+		})
+	}
+
+	return annotation
+}
+
+// HunksFor returns the hunks a tool call contributed to
+func (a *DiffAnnotation) HunksFor(toolUseID string) []AnnotatedHunk {
+	var hunks []AnnotatedHunk
+	for _, hunk := range a.Hunks {
+		for _, id := range hunk.ToolUseIDs {
+			if id == toolUseID {
+				hunks = append(hunks, hunk)
+				break
+			}
+		}
+	}
+	return hunks
+}
+
+// EditStatus returns the status of a tool call, or nil if it edited no file
+func (a *DiffAnnotation) EditStatus(toolUseID string) *EditStatus {
+	for i := range a.Edits {
+		if a.Edits[i].ToolUseID == toolUseID {
+			// A MultiEdit call is in the commit if any of its edits is
+			status := a.Edits[i]
+			for _, other := range a.Edits[i+1:] {
+				if other.ToolUseID == toolUseID && other.InCommit {
+					status.InCommit = true
+				}
+			}
+			return &status
+		}
+	}
+	return nil
+}
+
+// HumanHunks returns the hunks no tool call explains
+func (a *DiffAnnotation) HumanHunks() []AnnotatedHunk {
+	var hunks []AnnotatedHunk
+	for _, hunk := range a.Hunks {
+		if hunk.Human {
+			hunks = append(hunks, hunk)
+		}
+	}
+	return hunks
+}
+
+// explains returns true if the edit accounts for part of a hunk
+func explains(edit claude.FileEdit, added, removed map[string]bool) bool {
+	newLines := edit.NewLines()
+	for _, line := range newLines {
+		if added[line] {
+			return true
+		}
+	}
+	if len(newLines) > 0 {
+		return false
+	}
+	// Pure deletion: match the text that was removed
+	old := claude.FileEdit{NewString: edit.OldString}
+	for _, line := range old.NewLines() {
+		if removed[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// lineSet returns the trimmed, non-blank lines as a set
+func lineSet(lines []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, line := range lines {
+		if trimmed := trimLine(line); trimmed != "" {
+			set[trimmed] = true
+		}
+	}
+	return set
+}
+
+func appendUnique(ids []string, id string) []string {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,52 @@
+package attribution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+const diffTranscript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Add greetings"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/work/repo/main.go","old_string":"func main() {}","new_string":"func main() {\n\thello()\n}"}}]}}
+{"uuid":"a2","parentUuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Write","input":{"file_path":"/work/repo/scratch.txt","content":"notes"}}]}}
+{"uuid":"a3","parentUuid":"a2","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Edit","input":{"file_path":"/work/repo/util.go","old_string":"// obsolete\n","new_string":""}}]}}`
+
+func TestAnnotateDiff(t *testing.T) {
+	transcript, err := claude.ParseTranscript(strings.NewReader(diffTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+
+	hunks := []git.DiffHunk{
+		{File: "main.go", Header: "@@ -3 +3,3 @@", Lines: []string{"-func main() {}", "+func main() {", "+\thello()", "+}"}},
+		{File: "main.go", Header: "@@ -10 +12 @@", Lines: []string{"+// added by hand"}},
+		{File: "util.go", Header: "@@ -1 +0,0 @@", Lines: []string{"-// obsolete"}},
+	}
+
+	annotation := AnnotateDiff(hunks, transcript.Entries)
+
+	if got := annotation.Hunks[0].ToolUseIDs; len(got) != 1 || got[0] != "t1" {
+		t.Errorf("hunk 0 ToolUseIDs = %v, want [t1]", got)
+	}
+	if annotation.Hunks[0].Human || len(annotation.Hunks[0].Unexplained) != 0 {
+		t.Errorf("hunk 0 should be fully explained, got %+v", annotation.Hunks[0])
+	}
+	if !annotation.Hunks[1].Human {
+		t.Error("hunk 1 should be flagged as a human edit")
+	}
+	if got := annotation.Hunks[2].ToolUseIDs; len(got) != 1 || got[0] != "t3" {
+		t.Errorf("deletion hunk ToolUseIDs = %v, want [t3]", got)
+	}
+
+	if status := annotation.EditStatus("t1"); status == nil || !status.InCommit {
+		t.Errorf("EditStatus(t1) = %+v, want in commit", status)
+	}
+	if status := annotation.EditStatus("t2"); status == nil || status.InCommit {
+		t.Errorf("EditStatus(t2) = %+v, want not in commit", status)
+	}
+	if human := annotation.HumanHunks(); len(human) != 1 || human[0].Header != "@@ -10 +12 @@" {
+		t.Errorf("HumanHunks() = %+v, want the hand-written hunk", human)
+	}
+}
@@ -0,0 +1,40 @@
+package attribution
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// PreviousContent returns a lookup of files' content at a commit's first
+// parent, for showing what the commit's Write calls replaced. Tool calls
+// record absolute paths, which are resolved against projectPath, the root of
+// the checkout the conversation was stored from. Files outside it, or that
+// the parent doesn't have, are not found.
+func PreviousContent(commitSHA, projectPath string) func(path string) (string, bool) {
+	parent := commitSHA + "^"
+
+	return func(path string) (string, bool) {
+		rel := path
+		if filepath.IsAbs(path) {
+			if projectPath == "" {
+				return "", false
+			}
+			var err error
+			if rel, err = filepath.Rel(projectPath, path); err != nil {
+				return "", false
+			}
+		}
+		rel = filepath.ToSlash(filepath.Clean(rel))
+		if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+			return "", false
+		}
+
+		content, err := git.ReadFile(parent, rel)
+		if err != nil {
+			return "", false
+		}
+		return content, true
+	}
+}
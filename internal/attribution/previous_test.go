@@ -0,0 +1,54 @@
+package attribution
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreviousContent(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(content string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "README.md")
+		run("-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", content)
+		return run("rev-parse", "HEAD")
+	}
+	run("init", "-q")
+	commit("old\n")
+	sha := commit("new\n")
+
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(orig) })
+
+	previous := PreviousContent(sha, "/work/repo")
+	if content, ok := previous("/work/repo/README.md"); !ok || content != "old\n" {
+		t.Errorf("previous(README.md) = %q, %v; want the parent's content", content, ok)
+	}
+	for _, path := range []string{"/elsewhere/README.md", "/work/repo/../README.md", "/work/repo/missing.txt"} {
+		if _, ok := previous(path); ok {
+			t.Errorf("previous(%s) should not be found", path)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package attribution
+
+import (
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// Origin explains where a single line of a file came from
+type Origin struct {
+	CommitSHA string
+	// Conversation is nil if the commit has no stored conversation
+	Conversation *CommitConversation
+	// Edit is nil if no tool call in the conversation wrote the line
+	Edit *EditContext
+	// Exchange runs from the user prompt that triggered the edit through
+	// the tool result for it
+	Exchange []claude.TranscriptEntry
+}
+
+// Why finds the last commit to change a line of a file and the tool call in
+// that commit's conversation that introduced it.
+func Why(rev, file string, line int) (*Origin, error) {
+	change, err := git.LastLineChange(rev, file, line)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := &Origin{CommitSHA: change.CommitSHA}
+	conv, err := LoadCommitConversation(change.CommitSHA)
+	if err != nil || conv == nil {
+		return origin, err
+	}
+	origin.Conversation = conv
+
+	origin.Edit = conv.FindEdit(RepoRelativePath(file), change.Added)
+	if origin.Edit != nil {
+		origin.Exchange = conv.Exchange(origin.Edit)
+	}
+	return origin, nil
+}
+
+// Exchange returns the entries from the user prompt that led to an edit
+// through the tool result answering it. If the result can't be found, the
+// exchange ends at the tool call itself.
+func (c *CommitConversation) Exchange(edit *EditContext) []claude.TranscriptEntry {
+	entries := c.Transcript.Entries
+
+	index := -1
+	for i := range entries {
+		if entries[i].UUID == edit.EntryUUID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil
+	}
+
+	start := claude.PromptIndex(entries, index)
+	if start == -1 {
+		start = index
+	}
+
+	end := index
+	for i := index + 1; i < len(entries); i++ {
+		if hasToolResult(&entries[i], edit.ToolUseID) {
+			end = i
+			break
+		}
+	}
+	return entries[start : end+1]
+}
+
+// hasToolResult returns true if the entry carries the result of the given tool call
+func hasToolResult(entry *claude.TranscriptEntry, toolUseID string) bool {
+	if entry.Message == nil {
+		return false
+	}
+	for _, block := range entry.Message.Content {
+		if block.Type == "tool_result" && block.ToolUseID == toolUseID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image sizes
+	_ "image/jpeg" // register JPEG for image sizes
+	_ "image/png"  // register PNG for image sizes
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Attachment is an image or document pasted into a conversation or returned
+// by a tool
+type Attachment struct {
+	// ID identifies the attachment within its transcript: the entry's UUID
+	// and the block's index, then the index within a tool result for blocks
+	// nested in one, joined by "."
+	ID        string
+	Type      string
+	MediaType string
+	Title     string
+	// Data is empty for attachments given by URL
+	Data []byte
+	URL  string
+	// Width and Height are set for images in a format that can be decoded
+	Width, Height int
+}
+
+// IsAttachment returns true for image and document blocks
+func IsAttachment(block ContentBlock) bool {
+	return block.Type == "image" || block.Type == "document"
+}
+
+// NewAttachment decodes an image or document block. Returns false for
+// other blocks or if the block's data can't be decoded.
+func NewAttachment(id string, block ContentBlock) (*Attachment, bool) {
+	if !IsAttachment(block) || block.Source == nil {
+		return nil, false
+	}
+	a := &Attachment{ID: id, Type: block.Type, MediaType: block.Source.MediaType, Title: block.Title, URL: block.Source.URL}
+	switch block.Source.Type {
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(block.Source.Data)
+		if err != nil {
+			return nil, false
+		}
+		a.Data = data
+	case "text":
+		a.Data = []byte(block.Source.Data)
+		if a.MediaType == "" {
+			a.MediaType = "text/plain"
+		}
+	case "url":
+	default:
+		return nil, false
+	}
+	if a.Type == "image" && len(a.Data) > 0 {
+		if config, _, err := image.DecodeConfig(bytes.NewReader(a.Data)); err == nil {
+			a.Width, a.Height = config.Width, config.Height
+		}
+	}
+	return a, true
+}
+
+// Attachments returns the images and documents in entries, including those
+// in tool results, in order
+func Attachments(entries []TranscriptEntry) []Attachment {
+	var attachments []Attachment
+	for _, entry := range entries {
+		if entry.Message == nil {
+			continue
+		}
+		for i, block := range entry.Message.Content {
+			id := entry.UUID + "." + strconv.Itoa(i)
+			if a, ok := NewAttachment(id, block); ok {
+				attachments = append(attachments, *a)
+			}
+			if block.Type != "tool_result" {
+				continue
+			}
+			var nested []ContentBlock
+			if err := json.Unmarshal(block.Content, &nested); err != nil {
+				continue
+			}
+			for j, b := range nested {
+				if a, ok := NewAttachment(id+"."+strconv.Itoa(j), b); ok {
+					attachments = append(attachments, *a)
+				}
+			}
+		}
+	}
+	return attachments
+}
+
+// FindAttachment returns the attachment in entries with the given ID
+func FindAttachment(entries []TranscriptEntry, id string) (*Attachment, bool) {
+	for _, a := range Attachments(entries) {
+		if a.ID == id {
+			return &a, true
+		}
+	}
+	return nil, false
+}
+
+// attachmentExtensions maps media types to file extensions
+var attachmentExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+}
+
+// unsafeFileNameChars are the characters not kept from IDs in file names
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// FileName returns a name to save the attachment under, from its ID and
+// media type. IDs come from notes, which may have been fetched from anyone,
+// so path separators and other unusual characters are replaced.
+func (a Attachment) FileName() string {
+	ext, ok := attachmentExtensions[a.MediaType]
+	if !ok {
+		ext = ".bin"
+	}
+	return unsafeFileNameChars.ReplaceAllString(a.ID, "_") + ext
+}
+
+// Describe summarizes the attachment, e.g. "image/png 800×600, 12 KB"
+func (a Attachment) Describe() string {
+	var parts []string
+	if a.Title != "" {
+		parts = append(parts, strconv.Quote(a.Title))
+	}
+	if a.MediaType != "" {
+		parts = append(parts, a.MediaType)
+	}
+	if a.Width > 0 {
+		parts = append(parts, fmt.Sprintf("%d×%d", a.Width, a.Height))
+	}
+	description := strings.Join(parts, " ")
+	switch {
+	case len(a.Data) > 0:
+		description += ", " + formatBytes(len(a.Data))
+	case a.URL != "":
+		description += ", " + a.URL
+	}
+	return strings.TrimPrefix(description, ", ")
+}
+
+// formatBytes formats a size in B, KB or MB
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,73 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testPNG returns a PNG of the given size
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAttachments(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString(testPNG(t, 4, 3))
+	transcript, err := ParseTranscript(strings.NewReader(`{"uuid":"u1","type":"user","message":{"role":"user","content":[{"type":"text","text":"Look"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"` + data + `"}}]}}
+{"uuid":"u2","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"Screenshot"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"` + data + `"}}]}]}}
+{"uuid":"u3","type":"user","message":{"role":"user","content":[{"type":"document","title":"notes","source":{"type":"text","data":"hello"}},{"type":"image","source":{"type":"url","url":"https://example.com/a.png"}}]}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attachments := Attachments(transcript.Entries)
+	var ids []string
+	for _, a := range attachments {
+		ids = append(ids, a.ID)
+	}
+	if got := strings.Join(ids, " "); got != "u1.1 u2.0.1 u3.0 u3.1" {
+		t.Fatalf("IDs = %s", got)
+	}
+
+	image := attachments[0]
+	if image.Width != 4 || image.Height != 3 || image.FileName() != "u1.1.png" {
+		t.Errorf("image = %+v", image)
+	}
+	if got := image.Describe(); !strings.HasPrefix(got, "image/png 4×3, ") {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	doc := attachments[2]
+	if string(doc.Data) != "hello" || doc.FileName() != "u3.0.txt" || doc.Describe() != `"notes" text/plain, 5 B` {
+		t.Errorf("document = %+v, %q", doc, doc.Describe())
+	}
+	if got := attachments[3].Describe(); got != "https://example.com/a.png" {
+		t.Errorf("URL image Describe() = %q", got)
+	}
+
+	if a, ok := FindAttachment(transcript.Entries, "u2.0.1"); !ok || a.Width != 4 {
+		t.Errorf("FindAttachment = %+v, %v", a, ok)
+	}
+	if _, ok := FindAttachment(transcript.Entries, "u2.0.0"); ok {
+		t.Error("FindAttachment found a text block")
+	}
+}
+
+func TestAttachmentFileNameStaysInDirectory(t *testing.T) {
+	for _, id := range []string{"../../.config/x.0", `..\..\x.0`, "/etc/passwd.0"} {
+		name := Attachment{ID: id, MediaType: "image/png"}.FileName()
+		if strings.ContainsAny(name, `/\`) || filepath.Base(name) != name {
+			t.Errorf("FileName() for %q = %q, want a plain file name", id, name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package claude
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// columnGap separates the columns of SideBySide
+const columnGap = " │ "
+
+// ColumnWidth returns the width of each column of SideBySide
+func ColumnWidth(width int) int {
+	return max((width-utf8.RuneCountInString(columnGap))/2, 1)
+}
+
+// SideBySide lays out two blocks of rendered text as columns filling width,
+// line by line. Lines too long for their column are cut; color codes don't
+// count towards a line's width.
+func SideBySide(left, right string, width int) string {
+	column := ColumnWidth(width)
+	leftLines := strings.Split(strings.TrimSuffix(left, "\n"), "\n")
+	rightLines := strings.Split(strings.TrimSuffix(right, "\n"), "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(leftLines) || i < len(rightLines); i++ {
+		l, r := "", ""
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = rightLines[i]
+		}
+		b.WriteString(strings.TrimRight(fitColumn(l, column)+columnGap+fitColumn(r, column), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// fitColumn cuts or pads a line to width visible characters, skipping over
+// ANSI escape sequences and resetting colors after a cut. Tabs are expanded
+// so they take the width they're counted as.
+func fitColumn(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	var b strings.Builder
+	visible := 0
+	escaped := false
+	for i := 0; i < len(line); {
+		if line[i] == '\033' {
+			end := i + 1
+			if end < len(line) && line[end] == '[' {
+				end++
+				for end < len(line) && (line[end] < 0x40 || line[end] > 0x7e) {
+					end++
+				}
+				end++
+			}
+			end = min(end, len(line))
+			b.WriteString(line[i:end])
+			escaped = true
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if visible == width {
+			if escaped {
+				b.WriteString(colorReset)
+			}
+			return b.String()
+		}
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+	return b.String() + strings.Repeat(" ", width-visible)
+}
@@ -0,0 +1,16 @@
+package claude
+
+import "testing"
+
+func TestSideBySide(t *testing.T) {
+	left := colorRed + "removed line" + colorReset + "\nsecond\n"
+	right := "kept\n"
+
+	// Columns of 7 cut the first line and pad the second
+	got := SideBySide(left, right, 17)
+	want := colorRed + "removed" + colorReset + " │ kept\n" +
+		"second  │\n"
+	if got != want {
+		t.Errorf("SideBySide = %q, want %q", got, want)
+	}
+}
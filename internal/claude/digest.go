@@ -0,0 +1,80 @@
+package claude
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Digest is a condensed view of part of a conversation: what was asked, how
+// the assistant summed up, and what it did
+type Digest struct {
+	// Prompts are the messages typed by the user, in order
+	Prompts []string `json:"prompts"`
+	// Summary is the assistant's last text response
+	Summary string `json:"summary"`
+	// Tools counts calls per tool, most used first
+	Tools []ToolTally `json:"tools"`
+	// Files are the files written by edit tools, in the order first touched
+	Files []string `json:"files"`
+}
+
+// ToolTally is the number of calls of a tool
+type ToolTally struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NewDigest condenses the entries into a digest
+func NewDigest(entries []TranscriptEntry) Digest {
+	digest := Digest{Prompts: []string{}, Tools: []ToolTally{}, Files: []string{}}
+	counts := make(map[string]int)
+
+	for i := range entries {
+		entry := &entries[i]
+		if IsPrompt(entry) {
+			digest.Prompts = append(digest.Prompts, EntryText(entry))
+		}
+		if entry.Type == MessageTypeAssistant {
+			if text := EntryText(entry); text != "" {
+				digest.Summary = text
+			}
+		}
+	}
+	for _, call := range ToolCalls(entries) {
+		counts[call.Name]++
+	}
+
+	seen := make(map[string]bool)
+	for _, edit := range ExtractFileEdits(entries) {
+		if !seen[edit.FilePath] {
+			seen[edit.FilePath] = true
+			digest.Files = append(digest.Files, edit.FilePath)
+		}
+	}
+
+	for name, count := range counts {
+		digest.Tools = append(digest.Tools, ToolTally{Name: name, Count: count})
+	}
+	sort.Slice(digest.Tools, func(i, j int) bool {
+		if digest.Tools[i].Count != digest.Tools[j].Count {
+			return digest.Tools[i].Count > digest.Tools[j].Count
+		}
+		return digest.Tools[i].Name < digest.Tools[j].Name
+	})
+	return digest
+}
+
+// RelativizeFiles rewrites file paths inside root relative to it
+func (d *Digest) RelativizeFiles(root string) {
+	for i, file := range d.Files {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			d.Files[i] = filepath.ToSlash(rel)
+		}
+	}
+}
+
+// IsEmpty returns true if the digest has nothing to show
+func (d Digest) IsEmpty() bool {
+	return len(d.Prompts) == 0 && d.Summary == "" && len(d.Tools) == 0
+}
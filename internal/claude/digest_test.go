@@ -0,0 +1,47 @@
+package claude
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const digestTranscript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Add a greeting"}}
+{"uuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"I'll add it."},{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":"/repo/hello.go","content":"package main"}}]}}
+{"uuid":"r1","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"uuid":"a2","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/repo/hello.go","old_string":"main","new_string":"hello"}},{"type":"tool_use","id":"t3","name":"Edit","input":{"file_path":"/elsewhere/notes.md","old_string":"a","new_string":"b"}}]}}
+{"uuid":"u2","type":"user","message":{"role":"user","content":"Now run the tests"}}
+{"uuid":"a3","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t4","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"uuid":"a4","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Added the greeting; tests pass."}]}}`
+
+func TestNewDigest(t *testing.T) {
+	transcript, err := ParseTranscript(strings.NewReader(digestTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+
+	digest := NewDigest(transcript.Entries)
+	digest.RelativizeFiles("/repo")
+
+	if want := []string{"Add a greeting", "Now run the tests"}; !reflect.DeepEqual(digest.Prompts, want) {
+		t.Errorf("Prompts = %q, want %q", digest.Prompts, want)
+	}
+	if digest.Summary != "Added the greeting; tests pass." {
+		t.Errorf("Summary = %q", digest.Summary)
+	}
+	wantTools := []ToolTally{{"Edit", 2}, {"Bash", 1}, {"Write", 1}}
+	if !reflect.DeepEqual(digest.Tools, wantTools) {
+		t.Errorf("Tools = %+v, want %+v", digest.Tools, wantTools)
+	}
+	// Files outside the root keep their absolute path
+	if want := []string{"hello.go", "/elsewhere/notes.md"}; !reflect.DeepEqual(digest.Files, want) {
+		t.Errorf("Files = %q, want %q", digest.Files, want)
+	}
+}
+
+func TestNewDigestEmpty(t *testing.T) {
+	digest := NewDigest(nil)
+	if !digest.IsEmpty() {
+		t.Errorf("digest of no entries = %+v, want empty", digest)
+	}
+}
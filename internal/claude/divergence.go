@@ -0,0 +1,56 @@
+package claude
+
+import "bytes"
+
+// Divergence compares two transcripts that share a history, such as a
+// session and one resumed from an older commit: the entries they have in
+// common, and the tails where each went its own way
+type Divergence struct {
+	Common []TranscriptEntry `json:"common"`
+	// ForkUUID is the UUID of the last common entry in A, which the tails
+	// continue from, or "" if there are no common entries
+	ForkUUID string `json:"fork_uuid,omitempty"`
+	// A and B are the entries of each transcript after the common ones
+	A []TranscriptEntry `json:"a"`
+	B []TranscriptEntry `json:"b"`
+}
+
+// Diverge aligns the active paths of two transcripts, following their
+// entries' parents, so that branches abandoned by rewinding or editing a
+// message are left out. Entries are common while both paths have the same
+// entry, or, for resumed sessions that copied the history under new UUIDs,
+// entries of the same type and content whose parents are common. Entries
+// without a UUID, such as summaries, are left out.
+func Diverge(a, b []TranscriptEntry) Divergence {
+	a, b = NewTree(a).ActivePath(), NewTree(b).ActivePath()
+
+	// matched maps the UUIDs of common entries in a to those in b
+	matched := make(map[string]string)
+	var d Divergence
+	i := 0
+	for ; i < len(a) && i < len(b); i++ {
+		if !sameEntry(a[i], b[i], matched) {
+			break
+		}
+		matched[a[i].UUID] = b[i].UUID
+		d.Common = append(d.Common, a[i])
+		d.ForkUUID = a[i].UUID
+	}
+	d.A, d.B = a[i:], b[i:]
+	return d
+}
+
+// sameEntry returns true if x in one transcript and y in the other are the
+// same entry, given the common entries so far
+func sameEntry(x, y TranscriptEntry, matched map[string]string) bool {
+	if x.UUID == y.UUID {
+		return true
+	}
+	if x.Type != y.Type || matched[x.ParentUUID] != y.ParentUUID {
+		return false
+	}
+	if x.Message == nil || y.Message == nil {
+		return x.Message == y.Message
+	}
+	return bytes.Equal(x.Message.RawContent, y.Message.RawContent)
+}
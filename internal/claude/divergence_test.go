@@ -0,0 +1,81 @@
+package claude
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseEntries(t *testing.T, jsonl string) []TranscriptEntry {
+	t.Helper()
+	transcript, err := ParseTranscript(strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transcript.Entries
+}
+
+func TestDiverge(t *testing.T) {
+	base := `{"uuid":"u1","type":"user","message":{"role":"user","content":"Fix the test"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":"Looking"}}
+`
+	tests := []struct {
+		name         string
+		a, b         string
+		common, inA  []string
+		inB          []string
+		wantForkUUID string
+	}{
+		{
+			name:   "later revision of one session",
+			a:      base,
+			b:      base + `{"uuid":"u2","parentUuid":"a1","type":"user","message":{"role":"user","content":"Thanks"}}`,
+			common: []string{"u1", "a1"}, inB: []string{"u2"}, wantForkUUID: "a1",
+		},
+		{
+			name: "resumed under new UUIDs",
+			a: base + `{"uuid":"u2","parentUuid":"a1","type":"user","message":{"role":"user","content":"Retry"}}
+{"type":"summary","summary":"Fixing a test"}`,
+			b: `{"uuid":"x1","type":"user","message":{"role":"user","content":"Fix the test"}}
+{"uuid":"x2","parentUuid":"x1","type":"assistant","message":{"role":"assistant","content":"Looking"}}
+{"uuid":"x3","parentUuid":"x2","type":"user","message":{"role":"user","content":"Mock the clock"}}`,
+			common: []string{"u1", "a1"}, inA: []string{"u2"}, inB: []string{"x3"}, wantForkUUID: "a1",
+		},
+		{
+			name: "abandoned branch in the middle of one transcript",
+			a: base + `{"uuid":"u2","parentUuid":"a1","type":"user","message":{"role":"user","content":"Try the mock"}}
+{"uuid":"a2","parentUuid":"u2","type":"assistant","message":{"role":"assistant","content":"Mocked"}}
+{"uuid":"u3","parentUuid":"a1","type":"user","message":{"role":"user","content":"Try the clock"}}
+{"uuid":"a3","parentUuid":"u3","type":"assistant","message":{"role":"assistant","content":"Fixed"}}`,
+			b: base + `{"uuid":"u3","parentUuid":"a1","type":"user","message":{"role":"user","content":"Try the clock"}}
+{"uuid":"a3","parentUuid":"u3","type":"assistant","message":{"role":"assistant","content":"Fixed"}}
+{"uuid":"u4","parentUuid":"a3","type":"user","message":{"role":"user","content":"Thanks"}}`,
+			common: []string{"u1", "a1", "u3", "a3"}, inB: []string{"u4"}, wantForkUUID: "a3",
+		},
+		{
+			name:   "same content under an unrelated parent",
+			a:      `{"uuid":"u1","type":"user","message":{"role":"user","content":"Hi"}}`,
+			b:      `{"uuid":"x1","parentUuid":"other","type":"user","message":{"role":"user","content":"Hi"}}`,
+			inA:    []string{"u1"},
+			inB:    []string{"x1"},
+			common: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Diverge(parseEntries(t, tt.a), parseEntries(t, tt.b))
+			if got := uuids(d.Common); !reflect.DeepEqual(got, tt.common) {
+				t.Errorf("Common = %v, want %v", got, tt.common)
+			}
+			if got := uuids(d.A); !reflect.DeepEqual(got, tt.inA) {
+				t.Errorf("A = %v, want %v", got, tt.inA)
+			}
+			if got := uuids(d.B); !reflect.DeepEqual(got, tt.inB) {
+				t.Errorf("B = %v, want %v", got, tt.inB)
+			}
+			if d.ForkUUID != tt.wantForkUUID {
+				t.Errorf("ForkUUID = %q, want %q", d.ForkUUID, tt.wantForkUUID)
+			}
+		})
+	}
+}
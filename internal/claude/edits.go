@@ -0,0 +1,196 @@
+package claude
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// FileEdit is a change made to a file by a Write, Edit, MultiEdit or
+// NotebookEdit tool call. A MultiEdit call yields one FileEdit per edit.
+type FileEdit struct {
+	Tool      string
+	ToolUseID string
+	FilePath  string
+	// OldString is the text that was replaced; empty for Write and NotebookEdit
+	OldString string
+	// NewString is the text that was written; the whole file for Write
+	NewString string
+	// ReplaceAll is set for Edit calls that replaced every occurrence
+	ReplaceAll bool
+	// EntryIndex is the index of the assistant entry containing the tool call
+	EntryIndex int
+	EntryUUID  string
+}
+
+// fileEditInput covers the inputs of all file-modifying tools
+type fileEditInput struct {
+	FilePath     string `json:"file_path"`
+	NotebookPath string `json:"notebook_path"`
+	Content      string `json:"content"`
+	OldString    string `json:"old_string"`
+	NewString    string `json:"new_string"`
+	NewSource    string `json:"new_source"`
+	ReplaceAll   bool   `json:"replace_all"`
+	Edits        []struct {
+		OldString  string `json:"old_string"`
+		NewString  string `json:"new_string"`
+		ReplaceAll bool   `json:"replace_all"`
+	} `json:"edits"`
+}
+
+// ExtractFileEdits returns the file-modifying tool calls in entries, in order
+func ExtractFileEdits(entries []TranscriptEntry) []FileEdit {
+	var edits []FileEdit
+	for i, entry := range entries {
+		if entry.Type != MessageTypeAssistant || entry.Message == nil {
+			continue
+		}
+		for _, block := range entry.Message.Content {
+			if block.Type != "tool_use" {
+				continue
+			}
+			edits = append(edits, parseFileEdits(block, i, entry.UUID)...)
+		}
+	}
+	return edits
+}
+
+// parseFileEdits converts a single tool_use block into file edits
+func parseFileEdits(block ContentBlock, index int, uuid string) []FileEdit {
+	var input fileEditInput
+	if len(block.Input) == 0 || json.Unmarshal(block.Input, &input) != nil {
+		return nil
+	}
+
+	edit := FileEdit{
+		Tool:       block.Name,
+		ToolUseID:  block.ID,
+		FilePath:   input.FilePath,
+		EntryIndex: index,
+		EntryUUID:  uuid,
+	}
+
+	switch block.Name {
+	case "Write":
+		edit.NewString = input.Content
+		return []FileEdit{edit}
+	case "Edit":
+		edit.OldString = input.OldString
+		edit.NewString = input.NewString
+		edit.ReplaceAll = input.ReplaceAll
+		return []FileEdit{edit}
+	case "MultiEdit":
+		var edits []FileEdit
+		for _, e := range input.Edits {
+			sub := edit
+			sub.OldString = e.OldString
+			sub.NewString = e.NewString
+			sub.ReplaceAll = e.ReplaceAll
+			edits = append(edits, sub)
+		}
+		return edits
+	case "NotebookEdit":
+		edit.FilePath = input.NotebookPath
+		edit.NewString = input.NewSource
+		return []FileEdit{edit}
+	}
+	return nil
+}
+
+// MatchesPath returns true if the edit targets the given repository-relative path.
+// Tool calls record absolute paths, possibly from a different checkout, so
+// the comparison is done on path suffixes.
+func (e FileEdit) MatchesPath(relPath string) bool {
+	editPath := filepath.ToSlash(e.FilePath)
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+	return editPath == relPath || strings.HasSuffix(editPath, "/"+relPath)
+}
+
+// NewLines returns the non-blank lines written by the edit, trimmed of
+// surrounding whitespace
+func (e FileEdit) NewLines() []string {
+	var lines []string
+	for _, line := range strings.Split(e.NewString, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+	return lines
+}
+
+// IsPrompt returns true if the entry is a message typed by the user, as
+// opposed to a tool result delivered in a user entry
+func IsPrompt(entry *TranscriptEntry) bool {
+	if entry.Type != MessageTypeUser || entry.Message == nil {
+		return false
+	}
+	hasText := false
+	for _, block := range entry.Message.Content {
+		if block.Type == "tool_result" {
+			return false
+		}
+		if block.Type == "text" && strings.TrimSpace(block.Text) != "" {
+			hasText = true
+		}
+	}
+	return hasText
+}
+
+// EntryText returns the concatenated text blocks of an entry
+func EntryText(entry *TranscriptEntry) string {
+	if entry.Message == nil {
+		return ""
+	}
+	var parts []string
+	for _, block := range entry.Message.Content {
+		if block.Type == "text" && strings.TrimSpace(block.Text) != "" {
+			parts = append(parts, strings.TrimSpace(block.Text))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// PromptIndex returns the index of the user prompt that led to the entry at
+// index, or -1 if there is none
+func PromptIndex(entries []TranscriptEntry, index int) int {
+	for i := index; i >= 0; i-- {
+		if IsPrompt(&entries[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PromptFor returns the text of the user prompt that led to the entry at index
+func PromptFor(entries []TranscriptEntry, index int) string {
+	if i := PromptIndex(entries, index); i != -1 {
+		return EntryText(&entries[i])
+	}
+	return ""
+}
+
+// ExplanationFor returns the assistant's explanation for the tool call in the
+// entry at index: the closest assistant text since the prompt, or failing
+// that the first assistant text after the tool call.
+func ExplanationFor(entries []TranscriptEntry, index int) string {
+	start := PromptIndex(entries, index)
+	for i := index; i > start && i >= 0; i-- {
+		if entries[i].Type == MessageTypeAssistant {
+			if text := EntryText(&entries[i]); text != "" {
+				return text
+			}
+		}
+	}
+	for i := index + 1; i < len(entries); i++ {
+		if IsPrompt(&entries[i]) {
+			break
+		}
+		if entries[i].Type == MessageTypeAssistant {
+			if text := EntryText(&entries[i]); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
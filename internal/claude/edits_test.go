@@ -0,0 +1,75 @@
+package claude
+
+import (
+	"strings"
+	"testing"
+)
+
+const editsTranscript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Add a greeting"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"I'll add a hello function."}]}}
+{"uuid":"a2","parentUuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/work/repo/main.go","old_string":"package main","new_string":"package main\n\nfunc hello() {}"}}]}}
+{"uuid":"r1","parentUuid":"a2","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"uuid":"a3","parentUuid":"r1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"MultiEdit","input":{"file_path":"/work/repo/util.go","edits":[{"old_string":"a","new_string":"b"},{"old_string":"c","new_string":"d"}]}}]}}
+{"uuid":"a4","parentUuid":"a3","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Write","input":{"file_path":"/work/repo/new.txt","content":"new file"}},{"type":"tool_use","id":"t4","name":"Bash","input":{"command":"ls"}}]}}`
+
+func parseEditsTranscript(t *testing.T) *Transcript {
+	t.Helper()
+	transcript, err := ParseTranscript(strings.NewReader(editsTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	return transcript
+}
+
+func TestExtractFileEdits(t *testing.T) {
+	edits := ExtractFileEdits(parseEditsTranscript(t).Entries)
+
+	if len(edits) != 4 {
+		t.Fatalf("ExtractFileEdits() returned %d edits, want 4", len(edits))
+	}
+
+	if edits[0].Tool != "Edit" || edits[0].ToolUseID != "t1" || edits[0].EntryIndex != 2 {
+		t.Errorf("edit 0 = %+v, want Edit t1 at index 2", edits[0])
+	}
+	if edits[1].Tool != "MultiEdit" || edits[1].NewString != "b" || edits[2].NewString != "d" {
+		t.Errorf("MultiEdit should yield one edit per sub-edit, got %+v %+v", edits[1], edits[2])
+	}
+	if edits[3].Tool != "Write" || edits[3].NewString != "new file" {
+		t.Errorf("edit 3 = %+v, want Write of new.txt", edits[3])
+	}
+}
+
+func TestFileEditMatchesPath(t *testing.T) {
+	edit := FileEdit{FilePath: "/home/dev/project/internal/server.go"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"internal/server.go", true},
+		{"./internal/server.go", true},
+		{"server.go", true},
+		{"rver.go", false},
+		{"internal/client.go", false},
+	}
+	for _, tc := range tests {
+		if got := edit.MatchesPath(tc.path); got != tc.want {
+			t.Errorf("MatchesPath(%q) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestPromptAndExplanationFor(t *testing.T) {
+	entries := parseEditsTranscript(t).Entries
+
+	if got := PromptFor(entries, 2); got != "Add a greeting" {
+		t.Errorf("PromptFor() = %q, want %q", got, "Add a greeting")
+	}
+	if got := ExplanationFor(entries, 2); got != "I'll add a hello function." {
+		t.Errorf("ExplanationFor() = %q, want %q", got, "I'll add a hello function.")
+	}
+	// Tool results are not prompts
+	if IsPrompt(&entries[3]) {
+		t.Error("IsPrompt() should be false for tool result entries")
+	}
+}
@@ -0,0 +1,229 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+// EntryFilter narrows down the entries of a transcript, dropping entries or
+// the content blocks of their messages. Filters don't modify their input.
+type EntryFilter func(entries []TranscriptEntry) []TranscriptEntry
+
+// Thinking modes for the Thinking filter
+const (
+	ThinkingFull    = "full"
+	ThinkingSummary = "summary"
+	ThinkingHide    = "hide"
+)
+
+// FilterOptions describes the filters to apply to the entries shown
+type FilterOptions struct {
+	// Only keeps entries of these types
+	Only []MessageType
+	// NoToolResults drops tool results
+	NoToolResults bool
+	// Tools keeps only the calls to these tools, and their results
+	Tools []string
+	// Thinking is ThinkingFull, ThinkingSummary, ThinkingHide or "" to leave
+	// thinking alone
+	Thinking string
+	// MaxResultLines truncates tool results to this many lines, if positive
+	MaxResultLines int
+	// Grep keeps only entries matching this regular expression
+	Grep string
+}
+
+// Filters returns the filters the options describe, in the order they apply
+func (o FilterOptions) Filters() ([]EntryFilter, error) {
+	var filters []EntryFilter
+	if len(o.Only) > 0 {
+		for _, t := range o.Only {
+			if _, ok := messageStyles[t]; !ok {
+				return nil, fmt.Errorf("unknown entry type %q (want user, assistant or system)", t)
+			}
+		}
+		filters = append(filters, OnlyTypes(o.Only...))
+	}
+	if len(o.Tools) > 0 {
+		filters = append(filters, OnlyTools(o.Tools...))
+	}
+	if o.NoToolResults {
+		filters = append(filters, NoToolResults())
+	}
+	switch o.Thinking {
+	case "", ThinkingFull:
+	case ThinkingSummary, ThinkingHide:
+		filters = append(filters, Thinking(o.Thinking))
+	default:
+		return nil, fmt.Errorf("unknown thinking mode %q (want full, summary or hide)", o.Thinking)
+	}
+	if o.MaxResultLines > 0 {
+		filters = append(filters, MaxResultLines(o.MaxResultLines))
+	}
+	if o.Grep != "" {
+		pattern, err := regexp.Compile(o.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		filters = append(filters, Grep(pattern))
+	}
+	return filters, nil
+}
+
+// ApplyFilters runs entries through each filter in turn
+func ApplyFilters(entries []TranscriptEntry, filters ...EntryFilter) []TranscriptEntry {
+	for _, filter := range filters {
+		entries = filter(entries)
+	}
+	return entries
+}
+
+// OnlyTypes keeps the entries of the given types
+func OnlyTypes(types ...MessageType) EntryFilter {
+	return func(entries []TranscriptEntry) []TranscriptEntry {
+		var kept []TranscriptEntry
+		for _, entry := range entries {
+			for _, t := range types {
+				if entry.Type == t {
+					kept = append(kept, entry)
+					break
+				}
+			}
+		}
+		return kept
+	}
+}
+
+// NoToolResults drops tool results
+func NoToolResults() EntryFilter {
+	return mapBlocks(func(block ContentBlock) (ContentBlock, bool) {
+		return block, block.Type != "tool_result"
+	})
+}
+
+// OnlyTools keeps the calls to the named tools and their results, dropping
+// those of other tools
+func OnlyTools(names ...string) EntryFilter {
+	return func(entries []TranscriptEntry) []TranscriptEntry {
+		kept := make(map[string]bool)
+		for _, call := range ToolCalls(entries) {
+			for _, name := range names {
+				if strings.EqualFold(call.Name, name) {
+					kept[call.ID] = true
+					break
+				}
+			}
+		}
+		return mapBlocks(func(block ContentBlock) (ContentBlock, bool) {
+			switch block.Type {
+			case "tool_use":
+				return block, kept[block.ID]
+			case "tool_result":
+				return block, kept[block.ToolUseID]
+			}
+			return block, true
+		})(entries)
+	}
+}
+
+// Thinking shortens thinking to its first line for ThinkingSummary, or drops
+// it for ThinkingHide
+func Thinking(mode string) EntryFilter {
+	return mapBlocks(func(block ContentBlock) (ContentBlock, bool) {
+		if block.Type != "thinking" || mode == ThinkingFull {
+			return block, true
+		}
+		if mode == ThinkingHide {
+			return block, false
+		}
+		block.Thinking = util.OneLine(block.Thinking, digestLineWidth)
+		return block, block.Thinking != ""
+	})
+}
+
+// MaxResultLines truncates tool results to n lines, noting how many were cut
+func MaxResultLines(n int) EntryFilter {
+	return mapBlocks(func(block ContentBlock) (ContentBlock, bool) {
+		if block.Type != "tool_result" {
+			return block, true
+		}
+		lines := strings.Split(ResultText(block), "\n")
+		if len(lines) <= n {
+			return block, true
+		}
+		text := strings.Join(lines[:n], "\n") + fmt.Sprintf("\n... (%d more lines)", len(lines)-n)
+		content, err := json.Marshal(text)
+		if err != nil {
+			return block, true
+		}
+		block.Content = content
+		return block, true
+	})
+}
+
+// Grep keeps the entries whose text, thinking, tool inputs or tool results
+// match pattern
+func Grep(pattern *regexp.Regexp) EntryFilter {
+	return func(entries []TranscriptEntry) []TranscriptEntry {
+		var kept []TranscriptEntry
+		for _, entry := range entries {
+			if entry.Message == nil {
+				continue
+			}
+			for _, block := range entry.Message.Content {
+				if pattern.MatchString(block.Text) || pattern.MatchString(block.Thinking) ||
+					pattern.MatchString(block.Name) || pattern.Match(block.Input) ||
+					(block.Type == "tool_result" && pattern.MatchString(ResultText(block))) {
+					kept = append(kept, entry)
+					break
+				}
+			}
+		}
+		return kept
+	}
+}
+
+// mapBlocks returns a filter that rewrites or drops each content block.
+// Entries left without content are dropped; their raw content is updated so
+// they serialize as filtered.
+func mapBlocks(f func(block ContentBlock) (ContentBlock, bool)) EntryFilter {
+	return func(entries []TranscriptEntry) []TranscriptEntry {
+		var kept []TranscriptEntry
+		for _, entry := range entries {
+			if entry.Message == nil || len(entry.Message.Content) == 0 {
+				kept = append(kept, entry)
+				continue
+			}
+			var blocks []ContentBlock
+			changed := false
+			for _, block := range entry.Message.Content {
+				mapped, keep := f(block)
+				if !keep {
+					changed = true
+					continue
+				}
+				if mapped.Thinking != block.Thinking || string(mapped.Content) != string(block.Content) {
+					changed = true
+				}
+				blocks = append(blocks, mapped)
+			}
+			if len(blocks) == 0 {
+				continue
+			}
+			if changed {
+				message := *entry.Message
+				message.Content = blocks
+				if raw, err := json.Marshal(blocks); err == nil {
+					message.RawContent = raw
+				}
+				entry.Message = &message
+			}
+			kept = append(kept, entry)
+		}
+		return kept
+	}
+}
@@ -0,0 +1,121 @@
+package claude
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func filterTestEntries(t *testing.T) []TranscriptEntry {
+	t.Helper()
+	transcript, err := ParseTranscript(strings.NewReader(`{"uuid":"u1","type":"user","message":{"role":"user","content":"Fix the build"}}
+{"uuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"thinking","thinking":"First look\nthen fix"},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"main.go"}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go build"}}]}}
+{"uuid":"u2","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"package main\n\nfunc main() {}\n"},{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}
+{"uuid":"s1","type":"system","content":"Compacted"}
+{"uuid":"a2","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Fixed it"}]}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transcript.Entries
+}
+
+func uuids(entries []TranscriptEntry) []string {
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.UUID)
+	}
+	return ids
+}
+
+func blockTypes(entry TranscriptEntry) []string {
+	var types []string
+	for _, block := range entry.Message.Content {
+		types = append(types, block.Type)
+	}
+	return types
+}
+
+func TestOnlyTypes(t *testing.T) {
+	got := uuids(ApplyFilters(filterTestEntries(t), OnlyTypes(MessageTypeAssistant, MessageTypeSystem)))
+	if want := []string{"a1", "s1", "a2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNoToolResults(t *testing.T) {
+	entries := filterTestEntries(t)
+	got := ApplyFilters(entries, NoToolResults())
+	if want := []string{"u1", "a1", "s1", "a2"}; !reflect.DeepEqual(uuids(got), want) {
+		t.Errorf("entries left with only tool results should be dropped, got %v", uuids(got))
+	}
+	if len(entries[2].Message.Content) != 2 {
+		t.Error("filters should not modify their input")
+	}
+}
+
+func TestOnlyTools(t *testing.T) {
+	got := ApplyFilters(filterTestEntries(t), OnlyTools("bash"))
+	if types := blockTypes(got[1]); !reflect.DeepEqual(types, []string{"thinking", "tool_use"}) || got[1].Message.Content[1].ID != "t2" {
+		t.Errorf("want only the Bash call kept, got %+v", got[1].Message.Content)
+	}
+	if blocks := got[2].Message.Content; len(blocks) != 1 || blocks[0].ToolUseID != "t2" {
+		t.Errorf("want only the Bash result kept, got %+v", blocks)
+	}
+
+	// The raw content is updated so the entry serializes as filtered
+	var raw []ContentBlock
+	if err := json.Unmarshal(got[2].Message.RawContent, &raw); err != nil || len(raw) != 1 {
+		t.Errorf("RawContent not updated: %s", got[2].Message.RawContent)
+	}
+}
+
+func TestThinking(t *testing.T) {
+	got := ApplyFilters(filterTestEntries(t), Thinking(ThinkingSummary))
+	if thinking := got[1].Message.Content[0].Thinking; thinking != "First look" {
+		t.Errorf("summary: want the first line, got %q", thinking)
+	}
+	got = ApplyFilters(filterTestEntries(t), Thinking(ThinkingHide))
+	if types := blockTypes(got[1]); types[0] == "thinking" {
+		t.Errorf("hide: thinking not dropped, got %v", types)
+	}
+}
+
+func TestMaxResultLines(t *testing.T) {
+	got := ApplyFilters(filterTestEntries(t), MaxResultLines(1))
+	if text := ResultText(got[2].Message.Content[0]); text != "package main\n... (3 more lines)" {
+		t.Errorf("got %q", text)
+	}
+	if text := ResultText(got[2].Message.Content[1]); text != "ok" {
+		t.Errorf("short results should be left alone, got %q", text)
+	}
+}
+
+func TestGrep(t *testing.T) {
+	got := uuids(ApplyFilters(filterTestEntries(t), Grep(regexp.MustCompile(`go build|func main`))))
+	if want := []string{"a1", "u2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFilterOptions(t *testing.T) {
+	filters, err := FilterOptions{Only: []MessageType{MessageTypeUser}, NoToolResults: true}.Filters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uuids(ApplyFilters(filterTestEntries(t), filters...)); !reflect.DeepEqual(got, []string{"u1"}) {
+		t.Errorf("got %v, want [u1]", got)
+	}
+
+	for _, options := range []FilterOptions{
+		{Only: []MessageType{"tool"}},
+		{Thinking: "some"},
+		{Grep: "("},
+	} {
+		if _, err := options.Filters(); err == nil {
+			t.Errorf("want an error for %+v", options)
+		}
+	}
+}
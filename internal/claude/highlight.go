@@ -0,0 +1,259 @@
+package claude
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// syntax is what the highlighter knows about a language
+type syntax struct {
+	keywords     []string
+	lineComments []string
+	// blockComment is the start and end of block comments, if any
+	blockComment [2]string
+	quotes       string
+	// ignoreCase matches keywords in any case
+	ignoreCase bool
+}
+
+var (
+	cKeywords  = []string{"break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum", "extern", "float", "for", "goto", "if", "int", "long", "return", "short", "signed", "sizeof", "static", "struct", "switch", "typedef", "union", "unsigned", "void", "volatile", "while", "NULL", "true", "false", "bool"}
+	jsKeywords = []string{"async", "await", "break", "case", "catch", "class", "const", "continue", "default", "delete", "do", "else", "export", "extends", "false", "finally", "for", "from", "function", "if", "import", "in", "instanceof", "let", "new", "null", "of", "return", "static", "super", "switch", "this", "throw", "true", "try", "typeof", "undefined", "var", "void", "while", "yield"}
+)
+
+// syntaxes maps languages to their syntax
+var syntaxes = map[string]syntax{
+	"go": {
+		keywords:     []string{"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type", "var", "nil", "true", "false", "iota"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	},
+	"python": {
+		keywords:     []string{"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield", "None", "True", "False", "self"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+	"javascript": {
+		keywords:     jsKeywords,
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	},
+	"typescript": {
+		keywords:     append([]string{"any", "as", "boolean", "declare", "enum", "implements", "interface", "keyof", "namespace", "number", "private", "protected", "public", "readonly", "string", "type"}, jsKeywords...),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	},
+	"rust": {
+		keywords:     []string{"as", "async", "await", "break", "const", "continue", "crate", "else", "enum", "extern", "false", "fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self", "Self", "static", "struct", "super", "trait", "true", "type", "unsafe", "use", "where", "while"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"",
+	},
+	"java": {
+		keywords:     []string{"abstract", "boolean", "break", "case", "catch", "class", "continue", "default", "do", "else", "enum", "extends", "false", "final", "finally", "for", "if", "implements", "import", "instanceof", "int", "interface", "new", "null", "package", "private", "protected", "public", "return", "static", "super", "switch", "this", "throw", "throws", "true", "try", "void", "while"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'",
+	},
+	"c": {
+		keywords:     cKeywords,
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'",
+	},
+	"cpp": {
+		keywords:     append([]string{"auto", "class", "delete", "namespace", "new", "nullptr", "private", "protected", "public", "template", "this", "throw", "try", "catch", "using", "virtual"}, cKeywords...),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'",
+	},
+	"ruby": {
+		keywords:     []string{"begin", "break", "case", "class", "def", "do", "else", "elsif", "end", "ensure", "false", "for", "if", "in", "module", "next", "nil", "require", "rescue", "return", "self", "then", "true", "unless", "until", "when", "while", "yield"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+	"shell": {
+		keywords:     []string{"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if", "in", "local", "return", "then", "until", "while"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+	"json": {
+		keywords: []string{"true", "false", "null"},
+		quotes:   "\"",
+	},
+	"yaml": {
+		keywords:     []string{"true", "false", "null", "yes", "no"},
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	},
+	"sql": {
+		keywords:     []string{"select", "from", "where", "insert", "into", "values", "update", "set", "delete", "create", "table", "drop", "alter", "join", "left", "inner", "on", "and", "or", "not", "null", "as", "order", "group", "by", "limit", "primary", "key", "index"},
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "'\"",
+		ignoreCase:   true,
+	},
+}
+
+// languageNames maps file extensions and code fence tags to languages
+var languageNames = map[string]string{
+	"go": "go", "golang": "go",
+	"py": "python", "python": "python",
+	"js": "javascript", "jsx": "javascript", "mjs": "javascript", "cjs": "javascript", "javascript": "javascript",
+	"ts": "typescript", "tsx": "typescript", "typescript": "typescript",
+	"rs": "rust", "rust": "rust",
+	"java": "java", "c": "c", "h": "c",
+	"cc": "cpp", "cpp": "cpp", "cxx": "cpp", "hpp": "cpp", "c++": "cpp",
+	"rb": "ruby", "ruby": "ruby",
+	"sh": "shell", "bash": "shell", "zsh": "shell", "shell": "shell", "console": "shell",
+	"json": "json",
+	"yaml": "yaml", "yml": "yaml",
+	"sql": "sql",
+}
+
+// DetectLanguage returns the language of a file from its name, or from the
+// interpreter on its #! line. Returns "" if it isn't one that is highlighted.
+func DetectLanguage(path, content string) string {
+	if lang, ok := languageNames[strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))]; ok {
+		return lang
+	}
+	if first, _, _ := strings.Cut(content, "\n"); strings.HasPrefix(first, "#!") {
+		fields := strings.Fields(strings.TrimPrefix(first, "#!"))
+		if len(fields) > 0 && filepath.Base(fields[0]) == "env" && len(fields) > 1 {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 {
+			interpreter := strings.TrimRight(filepath.Base(fields[0]), "0123456789.")
+			if lang, ok := languageNames[interpreter]; ok {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// highlighter splits the lines of some code into colored spans, carrying
+// block comments from one line to the next
+type highlighter struct {
+	syntax   syntax
+	keywords map[string]bool
+	// inComment is set while inside a block comment
+	inComment bool
+}
+
+// newHighlighter returns a highlighter for a language, or nil if the language
+// isn't known or colors are off
+func (r *Renderer) newHighlighter(lang string) *highlighter {
+	s, ok := syntaxes[languageNames[strings.ToLower(lang)]]
+	if !ok || !r.useColor {
+		return nil
+	}
+	keywords := make(map[string]bool, len(s.keywords))
+	for _, k := range s.keywords {
+		keywords[k] = true
+	}
+	return &highlighter{syntax: s, keywords: keywords}
+}
+
+// spans splits a line of code into keywords, strings, numbers, comments
+// and the rest, which is in the base style
+func (h *highlighter) spans(text, base string) []span {
+	var spans []span
+	add := func(style, s string) {
+		spans = append(spans, span{style: style, text: s})
+	}
+	blockStart, blockEnd := h.syntax.blockComment[0], h.syntax.blockComment[1]
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		if h.inComment {
+			end := strings.Index(rest, blockEnd)
+			if end < 0 {
+				add(colorDim, rest)
+				break
+			}
+			add(colorDim, rest[:end+len(blockEnd)])
+			h.inComment = false
+			i += end + len(blockEnd)
+			continue
+		}
+		if blockStart != "" && strings.HasPrefix(rest, blockStart) {
+			h.inComment = true
+			add(colorDim, blockStart)
+			i += len(blockStart)
+			continue
+		}
+		if h.isLineComment(text, i) {
+			add(colorDim, rest)
+			break
+		}
+
+		c := text[i]
+		switch {
+		case strings.IndexByte(h.syntax.quotes, c) >= 0:
+			end := i + 1
+			for end < len(text) && text[end] != c {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(text) {
+				end++
+			}
+			if end > len(text) {
+				end = len(text)
+			}
+			add(colorGreen, text[i:end])
+			i = end
+		case isWordByte(c):
+			end := i
+			for end < len(text) && isWordByte(text[end]) {
+				end++
+			}
+			word := text[i:end]
+			switch {
+			case h.isKeyword(word):
+				add(colorMagenta, word)
+			case unicode.IsDigit(rune(c)):
+				add(colorYellow, word)
+			default:
+				add(base, word)
+			}
+			i = end
+		default:
+			add(base, text[i:i+1])
+			i++
+		}
+	}
+	return spans
+}
+
+func (h *highlighter) isKeyword(word string) bool {
+	if h.syntax.ignoreCase {
+		word = strings.ToLower(word)
+	}
+	return h.keywords[word]
+}
+
+// isLineComment returns true if a line comment starts at i. Shell-like
+// comments only start a word, so that e.g. $# and URLs with # are left alone.
+func (h *highlighter) isLineComment(text string, i int) bool {
+	for _, prefix := range h.syntax.lineComments {
+		if !strings.HasPrefix(text[i:], prefix) {
+			continue
+		}
+		if prefix != "#" || i == 0 || text[i-1] == ' ' || text[i-1] == '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
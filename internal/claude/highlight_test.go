@@ -0,0 +1,70 @@
+package claude
+
+import (
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		path, content, want string
+	}{
+		{"/src/main.go", "", "go"},
+		{"app.TSX", "", "typescript"},
+		{"script", "#!/usr/bin/env python3\nprint(1)", "python"},
+		{"run", "#!/bin/bash\necho hi", "shell"},
+		{"notes.txt", "hello", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.path, tt.content); got != tt.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestHighlighterSpans(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	h := NewRenderer(nil).newHighlighter("sh")
+
+	got := h.spans(`if [ "$#" -gt 1 ]; then echo $# # args`, "")
+	var keywords, strs, comments []string
+	for _, s := range got {
+		switch s.style {
+		case colorMagenta:
+			keywords = append(keywords, s.text)
+		case colorGreen:
+			strs = append(strs, s.text)
+		case colorDim:
+			comments = append(comments, s.text)
+		}
+	}
+	if len(keywords) != 2 || keywords[0] != "if" || keywords[1] != "then" {
+		t.Errorf("keywords = %q", keywords)
+	}
+	if len(strs) != 1 || strs[0] != `"$#"` {
+		t.Errorf("strings = %q", strs)
+	}
+	if len(comments) != 1 || comments[0] != "# args" {
+		t.Errorf("comments = %q", comments)
+	}
+}
+
+func TestHighlighterBlockComment(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	h := NewRenderer(nil).newHighlighter("go")
+
+	h.spans("x := 1 /* start", "")
+	got := h.spans("end */ return", "")
+	if got[0].style != colorDim || got[0].text != "end */" {
+		t.Errorf("block comment should carry over lines, got %+v", got[0])
+	}
+	if last := got[len(got)-1]; last.style != colorMagenta || last.text != "return" {
+		t.Errorf("code after the comment should be highlighted, got %+v", last)
+	}
+}
+
+func TestHighlighterWithoutColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if h := NewRenderer(nil).newHighlighter("go"); h != nil {
+		t.Errorf("there should be no highlighting without colors")
+	}
+}
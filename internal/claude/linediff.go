@@ -0,0 +1,281 @@
+package claude
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// EditHunk is a hunk of the unified diff of a tool call's change
+type EditHunk struct {
+	// Header is the hunk's "@@ -a,b +c,d @@" line
+	Header string `json:"header"`
+	// Lines are the hunk's lines, each prefixed with ' ', '+' or '-'
+	Lines []string `json:"lines"`
+}
+
+// ToolDiff is the change a Write, Edit, MultiEdit or NotebookEdit call made
+// to a file, as a unified diff. Edit hunks are numbered from the start of the
+// replaced text, since where it was in the file isn't recorded.
+type ToolDiff struct {
+	ToolUseID string     `json:"tool_use_id"`
+	Tool      string     `json:"tool"`
+	File      string     `json:"file"`
+	Hunks     []EditHunk `json:"hunks"`
+	// NewFile is set for Write calls whose file's previous content isn't known
+	NewFile bool `json:"new_file,omitempty"`
+}
+
+// diffContext is the number of unchanged lines shown around changes
+const diffContext = 3
+
+// maxDiffCells bounds the work of diffing; larger changes are shown as the
+// old lines removed and the new ones added
+const maxDiffCells = 4_000_000
+
+// NewToolDiff returns the diff of a file-modifying tool call, or nil for
+// other tools. previous looks up the content a Write call replaced, if known.
+func NewToolDiff(block ContentBlock, previous func(path string) (string, bool)) *ToolDiff {
+	if block.Type != "tool_use" {
+		return nil
+	}
+	edits := parseFileEdits(block, 0, "")
+	if len(edits) == 0 {
+		return nil
+	}
+
+	diff := &ToolDiff{ToolUseID: block.ID, Tool: block.Name, File: edits[0].FilePath}
+	for _, edit := range edits {
+		old := edit.OldString
+		if block.Name == "Write" {
+			content, ok := "", false
+			if previous != nil {
+				content, ok = previous(edit.FilePath)
+			}
+			old, diff.NewFile = content, !ok
+		}
+		diff.Hunks = append(diff.Hunks, DiffLines(splitLines(old), splitLines(edit.NewString))...)
+	}
+	return diff
+}
+
+// ToolDiffs returns the diffs of the file-modifying tool calls in entries,
+// in order. previous looks up files' content before the first entry, if known.
+func ToolDiffs(entries []TranscriptEntry, previous func(path string) (string, bool)) []ToolDiff {
+	files := NewFileHistory(previous)
+	var diffs []ToolDiff
+	for _, entry := range entries {
+		if entry.Type != MessageTypeAssistant || entry.Message == nil {
+			continue
+		}
+		for _, block := range entry.Message.Content {
+			if diff := NewToolDiff(block, files.Previous); diff != nil {
+				diffs = append(diffs, *diff)
+			}
+			files.Apply(block)
+		}
+	}
+	return diffs
+}
+
+// FileHistory follows the content of files through a conversation's tool
+// calls, so that a Write is diffed against what the file held just before
+// it rather than before the conversation
+type FileHistory struct {
+	// base looks up files' content before the first tool call, if known
+	base    func(path string) (string, bool)
+	content map[string]string
+	// unknown are files changed in ways that can't be followed
+	unknown map[string]bool
+}
+
+// NewFileHistory returns a history starting from the files base looks up,
+// which may be nil if their content before the conversation isn't known
+func NewFileHistory(base func(path string) (string, bool)) *FileHistory {
+	return &FileHistory{base: base, content: make(map[string]string), unknown: make(map[string]bool)}
+}
+
+// Previous returns a file's content before the next tool call, if known
+func (h *FileHistory) Previous(path string) (string, bool) {
+	path = filepath.Clean(path)
+	if content, ok := h.content[path]; ok {
+		return content, true
+	}
+	if h.unknown[path] || h.base == nil {
+		return "", false
+	}
+	return h.base(path)
+}
+
+// Apply records the change a tool call made to a file
+func (h *FileHistory) Apply(block ContentBlock) {
+	if block.Type != "tool_use" {
+		return
+	}
+	for _, edit := range parseFileEdits(block, 0, "") {
+		path := filepath.Clean(edit.FilePath)
+		switch block.Name {
+		case "Write":
+			h.content[path] = edit.NewString
+			continue
+		case "Edit", "MultiEdit":
+			current, ok := h.Previous(path)
+			if ok && edit.OldString != "" && strings.Contains(current, edit.OldString) {
+				n := 1
+				if edit.ReplaceAll {
+					n = -1
+				}
+				h.content[path] = strings.Replace(current, edit.OldString, edit.NewString, n)
+				continue
+			}
+		}
+		// Notebook cells, and edits of text not known to be in the file
+		delete(h.content, path)
+		h.unknown[path] = true
+	}
+}
+
+// splitLines splits text into lines, without an empty line after a final
+// newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// DiffLines returns the unified diff hunks that turn a into b
+func DiffLines(a, b []string) []EditHunk {
+	// ops are the lines of the whole diff, each prefixed like a hunk's
+	var ops []string
+
+	// Lines in common at the start and end needn't take part in the LCS
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for _, line := range a[:prefix] {
+		ops = append(ops, " "+line)
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, " "+line)
+	}
+	return hunks(ops)
+}
+
+// diffMiddle diffs lines by their longest common subsequence
+func diffMiddle(a, b []string) []string {
+	var ops []string
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, "-"+line)
+		}
+		for _, line := range b {
+			ops = append(ops, "+"+line)
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, " "+a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, "+"+b[j])
+			j++
+		default:
+			ops = append(ops, "-"+a[i])
+			i++
+		}
+	}
+	return ops
+}
+
+// hunks groups the changed lines of a diff into hunks with diffContext
+// lines of context
+func hunks(ops []string) []EditHunk {
+	var result []EditHunk
+	for start := 0; start < len(ops); {
+		// Find the next change
+		first := start
+		for first < len(ops) && ops[first][0] == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		end := first
+		for i := first; i < len(ops); i++ {
+			if ops[i][0] != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(end+diffContext, len(ops))
+		result = append(result, newHunk(ops, from, to))
+		start = to
+	}
+	return result
+}
+
+// newHunk makes a hunk of ops[from:to], numbering its lines by counting
+// those before it
+func newHunk(ops []string, from, to int) EditHunk {
+	oldStart, newStart := 1, 1
+	for _, op := range ops[:from] {
+		if op[0] != '+' {
+			oldStart++
+		}
+		if op[0] != '-' {
+			newStart++
+		}
+	}
+	oldLen, newLen := 0, 0
+	for _, op := range ops[from:to] {
+		if op[0] != '+' {
+			oldLen++
+		}
+		if op[0] != '-' {
+			newLen++
+		}
+	}
+	// An empty side starts at the line before it, as in git's diffs
+	if oldLen == 0 {
+		oldStart--
+	}
+	if newLen == 0 {
+		newStart--
+	}
+	return EditHunk{
+		Header: fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldLen, newStart, newLen),
+		Lines:  append([]string(nil), ops[from:to]...),
+	}
+}
@@ -0,0 +1,110 @@
+package claude
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := strings.Split("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12", "\n")
+	b := strings.Split("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13", "\n")
+
+	got := DiffLines(a, b)
+	want := []EditHunk{
+		{Header: "@@ -1,6 +1,6 @@", Lines: []string{" 1", " 2", "-3", "+three", " 4", " 5", " 6"}},
+		{Header: "@@ -10,3 +10,4 @@", Lines: []string{" 10", " 11", " 12", "+13"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffLinesMergesCloseChanges(t *testing.T) {
+	got := DiffLines([]string{"a", "b", "c", "d"}, []string{"A", "b", "c", "D"})
+	if len(got) != 1 || got[0].Header != "@@ -1,4 +1,4 @@" {
+		t.Errorf("changes sharing context should be one hunk, got %+v", got)
+	}
+}
+
+func TestDiffLinesAddedFile(t *testing.T) {
+	got := DiffLines(nil, []string{"x", "y"})
+	want := []EditHunk{{Header: "@@ -0,0 +1,2 @@", Lines: []string{"+x", "+y"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines = %+v, want %+v", got, want)
+	}
+	if got := DiffLines([]string{"same"}, []string{"same"}); len(got) != 0 {
+		t.Errorf("identical text should have no hunks, got %+v", got)
+	}
+}
+
+func TestNewToolDiff(t *testing.T) {
+	multi := ContentBlock{Type: "tool_use", ID: "t1", Name: "MultiEdit", Input: json.RawMessage(
+		`{"file_path":"/repo/a.go","edits":[{"old_string":"x := 1","new_string":"x := 2"},{"old_string":"y","new_string":"y\nz"}]}`)}
+	diff := NewToolDiff(multi, nil)
+	if diff == nil || diff.File != "/repo/a.go" || diff.ToolUseID != "t1" || len(diff.Hunks) != 2 {
+		t.Fatalf("NewToolDiff(MultiEdit) = %+v", diff)
+	}
+	if !reflect.DeepEqual(diff.Hunks[1].Lines, []string{" y", "+z"}) {
+		t.Errorf("second edit lines = %q", diff.Hunks[1].Lines)
+	}
+
+	write := ContentBlock{Type: "tool_use", ID: "t2", Name: "Write", Input: json.RawMessage(`{"file_path":"/repo/b.txt","content":"one\ntwo\n"}`)}
+	diff = NewToolDiff(write, func(path string) (string, bool) { return "one\n", path == "/repo/b.txt" })
+	if diff.NewFile || !reflect.DeepEqual(diff.Hunks[0].Lines, []string{" one", "+two"}) {
+		t.Errorf("NewToolDiff(Write) = %+v", diff)
+	}
+	diff = NewToolDiff(write, nil)
+	if !diff.NewFile || len(diff.Hunks[0].Lines) != 2 {
+		t.Errorf("Write without previous content should be a new file, got %+v", diff)
+	}
+
+	bash := ContentBlock{Type: "tool_use", Name: "Bash", Input: json.RawMessage(`{"command":"ls"}`)}
+	if diff := NewToolDiff(bash, nil); diff != nil {
+		t.Errorf("NewToolDiff(Bash) = %+v, want nil", diff)
+	}
+}
+
+func TestToolDiffsFollowFiles(t *testing.T) {
+	call := func(id, name, input string) ContentBlock {
+		return ContentBlock{Type: "tool_use", ID: id, Name: name, Input: json.RawMessage(input)}
+	}
+	entries := []TranscriptEntry{{
+		Type: MessageTypeAssistant,
+		Message: &Message{Content: []ContentBlock{
+			call("w1", "Write", `{"file_path":"/repo/a.txt","content":"one\n"}`),
+			call("e1", "Edit", `{"file_path":"/repo/a.txt","old_string":"one","new_string":"uno"}`),
+			call("w2", "Write", `{"file_path":"/repo/a.txt","content":"uno\ntwo\n"}`),
+			call("w3", "Write", `{"file_path":"/repo/b.txt","content":"new\n"}`),
+		}},
+	}}
+
+	// b.txt was in the parent; a.txt wasn't
+	parent := func(path string) (string, bool) { return "old\n", path == "/repo/b.txt" }
+	diffs := ToolDiffs(entries, parent)
+	if len(diffs) != 4 {
+		t.Fatalf("ToolDiffs() = %d diffs, want 4", len(diffs))
+	}
+	if !diffs[0].NewFile {
+		t.Errorf("first Write should be a new file, got %+v", diffs[0])
+	}
+	// The second Write is diffed against the first, with the Edit applied
+	if second := diffs[2]; second.NewFile || !reflect.DeepEqual(second.Hunks[0].Lines, []string{" uno", "+two"}) {
+		t.Errorf("second Write = %+v", second)
+	}
+	if b := diffs[3]; b.NewFile || !reflect.DeepEqual(b.Hunks[0].Lines, []string{"-old", "+new"}) {
+		t.Errorf("Write of b.txt = %+v", b)
+	}
+}
+
+func TestFileHistoryUnknownEdit(t *testing.T) {
+	files := NewFileHistory(func(path string) (string, bool) { return "a\n", true })
+	files.Apply(ContentBlock{Type: "tool_use", Name: "Edit", Input: json.RawMessage(`{"file_path":"/repo/a.txt","old_string":"missing","new_string":"b"}`)})
+	if _, ok := files.Previous("/repo/a.txt"); ok {
+		t.Error("a file changed by an edit that can't be followed should be unknown")
+	}
+	if content, ok := files.Previous("/repo/other.txt"); !ok || content != "a\n" {
+		t.Errorf("Previous(other) = %q, %v; want the base content", content, ok)
+	}
+}
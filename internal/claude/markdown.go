@@ -0,0 +1,219 @@
+package claude
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// span is a run of text in one style
+type span struct {
+	style string
+	text  string
+}
+
+// cell is a rune of styled text
+type cell struct {
+	style string
+	r     rune
+}
+
+func toCells(spans []span) []cell {
+	var cells []cell
+	for _, s := range spans {
+		for _, r := range s.text {
+			cells = append(cells, cell{style: s.style, r: r})
+		}
+	}
+	return cells
+}
+
+// styled joins cells back into text, coloring each run of a style
+func (r *Renderer) styled(cells []cell) string {
+	var b strings.Builder
+	style := ""
+	for _, c := range cells {
+		if c.style != style {
+			if style != "" {
+				b.WriteString(r.color(colorReset))
+			}
+			b.WriteString(r.color(c.style))
+			style = c.style
+		}
+		b.WriteRune(c.r)
+	}
+	if style != "" {
+		b.WriteString(r.color(colorReset))
+	}
+	return b.String()
+}
+
+// wrapCells breaks cells into lines at spaces, the first at most first cells
+// wide and the others at most rest. Words too long for a line are split. A
+// width of 0 or less doesn't wrap.
+func wrapCells(cells []cell, first, rest int) [][]cell {
+	if first <= 0 || len(cells) <= first {
+		return [][]cell{cells}
+	}
+	var lines [][]cell
+	for width := first; len(cells) > width; width = rest {
+		end := width
+		for i := width; i > 0; i-- {
+			if cells[i].r == ' ' {
+				end = i
+				break
+			}
+		}
+		lines = append(lines, cells[:end])
+		cells = cells[end:]
+		for len(cells) > 0 && cells[0].r == ' ' {
+			cells = cells[1:]
+		}
+	}
+	return append(lines, cells)
+}
+
+// minWrapWidth keeps deeply indented text from wrapping into a narrow column
+const minWrapWidth = 20
+
+// wrapWidth returns how wide text after an indent may be, or 0 for no limit
+func (r *Renderer) wrapWidth(indent int) int {
+	if r.width <= 0 {
+		return 0
+	}
+	if width := r.width - indent; width > minWrapWidth {
+		return width
+	}
+	return minWrapWidth
+}
+
+// writeWrapped writes spans after prefix, wrapped to the renderer's width.
+// Continuation lines are indented by hang spaces instead of the prefix.
+func (r *Renderer) writeWrapped(prefix string, prefixWidth, hang int, spans []span) {
+	lines := wrapCells(toCells(spans), r.wrapWidth(prefixWidth), r.wrapWidth(hang))
+	for i, line := range lines {
+		if i == 0 {
+			_, _ = fmt.Fprintf(r.w, "%s%s\n", prefix, r.styled(line))
+		} else {
+			_, _ = fmt.Fprintf(r.w, "%s%s\n", strings.Repeat(" ", hang), r.styled(line))
+		}
+	}
+}
+
+// writeText writes an indented line of text in one style, wrapped so that
+// continuation lines keep the line's own indentation
+func (r *Renderer) writeText(indent, style, line string) {
+	lead := len(line) - len(strings.TrimLeft(line, " "))
+	if lead > r.wrapWidth(0)/2 {
+		lead = 0
+	}
+	r.writeWrapped(indent, len(indent), len(indent)+lead, []span{{style: style, text: line}})
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	fencePattern    = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([^\\s`]*)")
+	inlinePattern   = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|__[^_]+__|\\[[^\\]]+\\]\\([^)\\s]+\\)")
+)
+
+// renderMarkdown renders assistant text as Markdown: headings in bold,
+// bullets for list items, quotes and rules, inline code, bold and links,
+// and code fences highlighted by their language. Without colors the text is
+// shown as written.
+func (r *Renderer) renderMarkdown(text string) {
+	if !r.useColor {
+		r.renderText(text)
+		return
+	}
+
+	// fence is the marker of the code block being rendered, if any
+	fence := ""
+	var code *highlighter
+	for _, line := range strings.Split(text, "\n") {
+		if fence != "" {
+			if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorDim), line, r.color(colorReset))
+				fence = ""
+				continue
+			}
+			_, _ = fmt.Fprintf(r.w, "  %s\n", r.styled(toCells(codeSpans(code, line, ""))))
+			continue
+		}
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			fence, code = m[1], r.newHighlighter(m[2])
+			_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorDim), line, r.color(colorReset))
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			r.writeWrapped("  ", 2, 2, inlineSpans(m[2], colorBold))
+			continue
+		}
+		if rulePattern.MatchString(line) {
+			width := 40
+			if w := r.wrapWidth(2); w > 0 && w < width {
+				width = w
+			}
+			_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorDim), strings.Repeat("─", width), r.color(colorReset))
+			continue
+		}
+		if m := listItemPattern.FindStringSubmatch(line); m != nil {
+			marker := m[2]
+			if strings.ContainsAny(marker, "-*+") {
+				marker = "•"
+			}
+			prefix := "  " + m[1] + r.color(colorDim) + marker + r.color(colorReset) + " "
+			width := 2 + len(m[1]) + len([]rune(marker)) + 1
+			r.writeWrapped(prefix, width, width, inlineSpans(m[3], ""))
+			continue
+		}
+		if quote, ok := strings.CutPrefix(strings.TrimLeft(line, " "), ">"); ok {
+			prefix := "  " + r.color(colorDim) + "│" + r.color(colorReset) + " "
+			r.writeWrapped(prefix, 4, 4, inlineSpans(strings.TrimPrefix(quote, " "), colorDim))
+			continue
+		}
+
+		lead := len(line) - len(strings.TrimLeft(line, " "))
+		r.writeWrapped("  "+line[:lead], 2+lead, 2+lead, inlineSpans(line[lead:], ""))
+	}
+}
+
+// inlineSpans splits a line of Markdown into spans of inline code, bold text
+// and links, on top of a base style
+func inlineSpans(text, base string) []span {
+	var spans []span
+	add := func(style, s string) {
+		if s != "" {
+			spans = append(spans, span{style: style, text: s})
+		}
+	}
+	last := 0
+	for _, loc := range inlinePattern.FindAllStringIndex(text, -1) {
+		add(base, text[last:loc[0]])
+		match := text[loc[0]:loc[1]]
+		switch {
+		case strings.HasPrefix(match, "`"):
+			add(colorCyan, strings.Trim(match, "`"))
+		case strings.HasPrefix(match, "["):
+			label, url, _ := strings.Cut(strings.TrimPrefix(match, "["), "](")
+			add(base+colorUnderline, label)
+			add(colorDim, " ("+strings.TrimSuffix(url, ")")+")")
+		default:
+			add(base+colorBold, match[2:len(match)-2])
+		}
+		last = loc[1]
+	}
+	add(base, text[last:])
+	return spans
+}
+
+// codeSpans splits a line of code into spans with a highlighter, if there is
+// one for its language, on top of a base style
+func codeSpans(h *highlighter, line, base string) []span {
+	if h == nil {
+		return []span{{style: base, text: line}}
+	}
+	return h.spans(line, base)
+}
@@ -0,0 +1,113 @@
+package claude
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// renderAssistant renders an assistant message of text with colors
+func renderAssistant(t *testing.T, text string, width int) string {
+	t.Setenv("NO_COLOR", "")
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.SetWidth(width)
+	r.RenderEntry(&TranscriptEntry{
+		Type:    MessageTypeAssistant,
+		Message: &Message{Role: "assistant", Content: []ContentBlock{{Type: "text", Text: text}}},
+	})
+	return buf.String()
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	got := renderAssistant(t, "## Plan\n- use `go test`\n1. **first**\n> quoted\nSee [docs](https://example.com)", 0)
+
+	for _, want := range []string{
+		"  " + colorBold + "Plan" + colorReset + "\n",
+		"  " + colorDim + "•" + colorReset + " use " + colorCyan + "go test" + colorReset + "\n",
+		"  " + colorDim + "1." + colorReset + " " + colorBold + "first" + colorReset + "\n",
+		"  " + colorDim + "│" + colorReset + " " + colorDim + "quoted" + colorReset + "\n",
+		"See " + colorUnderline + "docs" + colorReset + colorDim + " (https://example.com)" + colorReset,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output should contain %q, got:\n%q", want, got)
+		}
+	}
+	if strings.Contains(got, "##") {
+		t.Errorf("heading markers should be removed, got:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownCodeFence(t *testing.T) {
+	got := renderAssistant(t, "```go\nreturn \"x\" // done\n```\n- after", 0)
+
+	want := "  " + colorMagenta + "return" + colorReset + " " + colorGreen + `"x"` + colorReset + " " + colorDim + "// done" + colorReset + "\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("code should be highlighted as Go, got:\n%q", got)
+	}
+	if !strings.Contains(got, "•") {
+		t.Errorf("Markdown should be rendered again after the fence, got:\n%q", got)
+	}
+}
+
+func TestRenderMarkdownWithoutColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.RenderEntry(&TranscriptEntry{
+		Type:    MessageTypeAssistant,
+		Message: &Message{Role: "assistant", Content: []ContentBlock{{Type: "text", Text: "## Plan\n- use `go test`"}}},
+	})
+	if want := "Assistant:\n  ## Plan\n  - use `go test`\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRenderWrapped(t *testing.T) {
+	got := renderAssistant(t, "- one two three four five six seven eight nine ten eleven twelve", 30)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected the list item to wrap onto 3 lines, got:\n%s", got)
+	}
+	if lines[2] != "    six seven eight nine ten" {
+		t.Errorf("continuation lines should hang under the item, got %q", lines[2])
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.SetWidth(24)
+	r.RenderEntry(&TranscriptEntry{
+		Type:    MessageTypeUser,
+		Message: &Message{Role: "user", Content: []ContentBlock{{Type: "text", Text: "  indented words that go on and on"}}},
+	})
+	want := "User:\n    indented words that\n    go on and on\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWrapCells(t *testing.T) {
+	toString := func(lines [][]cell) []string {
+		var out []string
+		for _, line := range lines {
+			var b strings.Builder
+			for _, c := range line {
+				b.WriteRune(c.r)
+			}
+			out = append(out, b.String())
+		}
+		return out
+	}
+
+	got := toString(wrapCells(toCells([]span{{text: "abc defghijkl mn"}}), 5, 4))
+	want := []string{"abc", "defg", "hijk", "l mn"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapCells = %q, want %q", got, want)
+	}
+
+	got = toString(wrapCells(toCells([]span{{text: "short"}}), 0, 0))
+	if len(got) != 1 || got[0] != "short" {
+		t.Errorf("width 0 should not wrap, got %q", got)
+	}
+}
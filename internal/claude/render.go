@@ -1,35 +1,57 @@
 package claude
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/termimage"
+	"github.com/DanielJonesEB/claudit/internal/util"
 )
 
 // ANSI color codes
 const (
-	colorReset  = "\033[0m"
-	colorBold   = "\033[1m"
-	colorDim    = "\033[2m"
-	colorBlue   = "\033[34m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorCyan   = "\033[36m"
+	colorReset     = "\033[0m"
+	colorBold      = "\033[1m"
+	colorDim       = "\033[2m"
+	colorBlue      = "\033[34m"
+	colorGreen     = "\033[32m"
+	colorYellow    = "\033[33m"
+	colorCyan      = "\033[36m"
+	colorRed       = "\033[31m"
+	colorMagenta   = "\033[35m"
+	colorUnderline = "\033[4m"
 )
 
 // Renderer renders transcript entries to the terminal
 type Renderer struct {
 	w        io.Writer
 	useColor bool
+	// full turns off the truncation of long thinking, tool inputs and results
+	full bool
+	// fullThinking turns off the truncation of long thinking only
+	fullThinking bool
+	// width is the width to wrap text to, or 0 not to wrap
+	width int
+	// afterToolUse is called after each tool_use block is rendered
+	afterToolUse func(block ContentBlock)
+	// afterEntry is called after each entry, whether it is rendered or not
+	afterEntry func(entry *TranscriptEntry)
+	// files follows the content of files for diffing Write calls
+	files *FileHistory
+	// images is how to draw images inline, if at all
+	images termimage.Protocol
 }
 
 // NewRenderer creates a new terminal renderer
 func NewRenderer(w io.Writer) *Renderer {
 	// Respect NO_COLOR environment variable
 	useColor := os.Getenv("NO_COLOR") == ""
-	return &Renderer{w: w, useColor: useColor}
+	return &Renderer{w: w, useColor: useColor, files: NewFileHistory(nil)}
 }
 
 // color returns the ANSI code if colors are enabled, empty string otherwise
@@ -40,6 +62,146 @@ func (r *Renderer) color(code string) string {
 	return ""
 }
 
+// SetFull turns off the truncation of long thinking, tool inputs and tool
+// results
+func (r *Renderer) SetFull(full bool) {
+	r.full = full
+}
+
+// SetFullThinking turns off the truncation of long thinking
+func (r *Renderer) SetFullThinking(full bool) {
+	r.fullThinking = full
+}
+
+// SetWidth wraps text, thinking and one-line tool inputs to fit a terminal of
+// the given width. A width of 0 turns wrapping off.
+func (r *Renderer) SetWidth(width int) {
+	r.width = width
+}
+
+// SetPreviousContent sets how to look up a file's content before the
+// entries rendered next, so Write calls can be shown as diffs. Files written
+// by earlier tool calls are diffed against what those calls left in them.
+func (r *Renderer) SetPreviousContent(previous func(path string) (string, bool)) {
+	r.files = NewFileHistory(previous)
+}
+
+// SetInlineImages draws images in the terminal with the given protocol,
+// below their placeholders
+func (r *Renderer) SetInlineImages(p termimage.Protocol) {
+	r.images = p
+}
+
+// SetToolUseHook registers a function to call after each tool call is
+// rendered, e.g. to print what the call changed
+func (r *Renderer) SetToolUseHook(hook func(block ContentBlock)) {
+	r.afterToolUse = hook
+}
+
+// SetEntryHook registers a function to call after each entry passed to
+// RenderEntries, e.g. to print what branched off it
+func (r *Renderer) SetEntryHook(hook func(entry *TranscriptEntry)) {
+	r.afterEntry = hook
+}
+
+// RenderDiff renders unified diff lines, colored by their +/- prefix
+func (r *Renderer) RenderDiff(header string, lines []string) {
+	_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorCyan), header, r.color(colorReset))
+	for _, line := range lines {
+		code := colorDim
+		switch {
+		case strings.HasPrefix(line, "+"):
+			code = colorGreen
+		case strings.HasPrefix(line, "-"):
+			code = colorRed
+		}
+		_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(code), line, r.color(colorReset))
+	}
+}
+
+// RenderNotice renders a highlighted one-line notice
+func (r *Renderer) RenderNotice(text string) {
+	_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorYellow), text, r.color(colorReset))
+}
+
+// Limits on how much of a digest is rendered
+const (
+	digestMaxPrompts = 3
+	digestMaxFiles   = 5
+	digestLineWidth  = 100
+)
+
+// RenderDigest renders a digest in a few lines, each starting with prefix
+func (r *Renderer) RenderDigest(d Digest, prefix string) {
+	line := func(label, code, text string) {
+		_, _ = fmt.Fprintf(r.w, "%s  %s%-8s%s %s%s%s\n", prefix, r.color(colorDim), label, r.color(colorReset), r.color(code), text, r.color(colorReset))
+	}
+
+	for i, prompt := range d.Prompts {
+		if i == digestMaxPrompts {
+			line("", colorDim, fmt.Sprintf("(+%d more prompts)", len(d.Prompts)-digestMaxPrompts))
+			break
+		}
+		line("Prompt:", colorBlue, util.OneLine(prompt, digestLineWidth))
+	}
+	if d.Summary != "" {
+		line("Summary:", colorGreen, util.OneLine(d.Summary, digestLineWidth))
+	}
+	if len(d.Tools) > 0 {
+		var tools []string
+		for _, t := range d.Tools {
+			tools = append(tools, fmt.Sprintf("%s ×%d", t.Name, t.Count))
+		}
+		line("Tools:", colorYellow, strings.Join(tools, ", "))
+	}
+	if len(d.Files) > 0 {
+		files := d.Files
+		more := ""
+		if len(files) > digestMaxFiles {
+			more = fmt.Sprintf(" (+%d more)", len(files)-digestMaxFiles)
+			files = files[:digestMaxFiles]
+		}
+		line("Files:", colorCyan, strings.Join(files, ", ")+more)
+	}
+}
+
+// RenderBranch renders an abandoned branch as a one-line summary, or when
+// expanded, in full behind a gutter
+func (r *Renderer) RenderBranch(b Branch, expanded bool) {
+	messages, first := 0, ""
+	for _, entry := range b.Entries {
+		if _, ok := messageStyles[entry.Type]; !ok || entry.Message == nil {
+			continue
+		}
+		messages++
+		for _, block := range entry.Message.Content {
+			if first == "" && block.Type == "text" {
+				first = util.OneLine(block.Text, digestLineWidth)
+			}
+		}
+	}
+	summary := fmt.Sprintf("⎇ abandoned branch, %d messages", messages)
+	if first != "" {
+		summary += ": " + first
+	}
+	_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorYellow), summary, r.color(colorReset))
+	if !expanded {
+		return
+	}
+
+	var buf bytes.Buffer
+	branch := *r
+	branch.w = &buf
+	branch.afterEntry, branch.afterToolUse = nil, nil
+	if r.width > 0 {
+		branch.width = max(r.width-4, 1)
+	}
+	_ = branch.RenderEntries(b.Entries)
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		_, _ = fmt.Fprintln(r.w, strings.TrimRight(fmt.Sprintf("  %s┆%s %s", r.color(colorYellow), r.color(colorReset), line), " "))
+	}
+}
+
 // RenderTranscript renders the full transcript to the writer
 func (r *Renderer) RenderTranscript(t *Transcript) error {
 	return r.RenderEntries(t.Entries)
@@ -58,6 +220,9 @@ func (r *Renderer) RenderEntries(entries []TranscriptEntry) error {
 			r.RenderEntry(&entry)
 			hadPrevious = true
 		}
+		if r.afterEntry != nil {
+			r.afterEntry(&entry)
+		}
 	}
 	return nil
 }
@@ -80,31 +245,100 @@ var messageStyles = map[MessageType]struct {
 
 // RenderEntry renders a single transcript entry
 func (r *Renderer) RenderEntry(entry *TranscriptEntry) {
+	if _, ok := messageStyles[entry.Type]; !ok {
+		return
+	}
+	r.RenderLabel(entry)
+	if entry.Message == nil {
+		return
+	}
+	for _, block := range entry.Message.Content {
+		r.RenderBlock(entry, block)
+	}
+}
+
+// RenderLabel renders the line naming who an entry is from, e.g. "User:"
+func (r *Renderer) RenderLabel(entry *TranscriptEntry) {
 	style, ok := messageStyles[entry.Type]
 	if !ok {
 		return
 	}
 	_, _ = fmt.Fprintf(r.w, "%s%s%s:%s\n", r.color(colorBold), r.color(style.color), style.label, r.color(colorReset))
-	r.renderMessageContent(entry.Message)
 }
 
-func (r *Renderer) renderMessageContent(msg *Message) {
-	if msg == nil {
-		return
+// RenderBlock renders a single content block of an entry's message. The
+// assistant's text is rendered as Markdown.
+func (r *Renderer) RenderBlock(entry *TranscriptEntry, block ContentBlock) {
+	switch block.Type {
+	case "text":
+		if entry != nil && entry.Type == MessageTypeAssistant {
+			r.renderMarkdown(block.Text)
+		} else {
+			r.renderText(block.Text)
+		}
+	case "thinking":
+		r.renderThinking(block.Thinking)
+	case "tool_use":
+		r.renderToolUse(block)
+		if r.afterToolUse != nil {
+			r.afterToolUse(block)
+		}
+	case "tool_result":
+		r.renderToolResult(block)
+	case "image", "document":
+		r.renderAttachment(block)
 	}
+}
 
-	for _, block := range msg.Content {
-		switch block.Type {
-		case "text":
-			r.renderText(block.Text)
-		case "thinking":
-			r.renderThinking(block.Thinking)
-		case "tool_use":
-			r.renderToolUse(block)
-		case "tool_result":
-			r.renderToolResult(block)
+// RenderCollapsed renders a thinking, tool call or tool result block as a
+// single summary line
+func (r *Renderer) RenderCollapsed(block ContentBlock) {
+	line := func(code, tag, summary string, lines int) {
+		if lines > 1 {
+			summary += fmt.Sprintf(" (%d lines)", lines)
+		}
+		_, _ = fmt.Fprintf(r.w, "  %s[%s]%s %s%s%s\n", r.color(code), tag, r.color(colorReset), r.color(colorDim), summary, r.color(colorReset))
+	}
+
+	switch block.Type {
+	case "thinking":
+		line(colorDim, "thinking", util.OneLine(block.Thinking, digestLineWidth), strings.Count(block.Thinking, "\n")+1)
+	case "tool_use":
+		line(colorCyan, "tool: "+block.Name, util.OneLine(toolSummary(block), digestLineWidth), 0)
+	case "tool_result":
+		text := ResultText(block)
+		code, tag := colorDim, "tool result"
+		if block.IsError {
+			code, tag = colorRed, "tool error"
+		}
+		line(code, tag, util.OneLine(text, digestLineWidth), strings.Count(text, "\n")+1)
+	default:
+		r.RenderBlock(nil, block)
+	}
+}
+
+// toolSummary returns the main argument of a tool call, e.g. a command or path
+func toolSummary(block ContentBlock) string {
+	var input map[string]interface{}
+	if err := json.Unmarshal(block.Input, &input); err != nil {
+		return ""
+	}
+	for _, key := range []string{"command", "file_path", "pattern", "path", "url", "query", "description"} {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if s, ok := input[k].(string); ok && s != "" {
+			return s
 		}
 	}
+	return ""
 }
 
 func (r *Renderer) renderText(text string) {
@@ -114,7 +348,7 @@ func (r *Renderer) renderText(text string) {
 	// Indent the text for readability
 	lines := strings.Split(text, "\n")
 	for _, line := range lines {
-		_, _ = fmt.Fprintf(r.w, "  %s\n", line)
+		r.writeText("  ", "", line)
 	}
 }
 
@@ -127,15 +361,16 @@ func (r *Renderer) renderThinking(thinking string) {
 	// Show just a summary - first few lines
 	maxLines := 3
 	for i, line := range lines {
-		if i >= maxLines {
+		if !r.full && !r.fullThinking && i >= maxLines {
 			_, _ = fmt.Fprintf(r.w, "  %s... (%d more lines)%s\n", r.color(colorDim), len(lines)-maxLines, r.color(colorReset))
 			break
 		}
-		_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorDim), line, r.color(colorReset))
+		r.writeText("  ", colorDim, line)
 	}
 }
 
 func (r *Renderer) renderToolUse(block ContentBlock) {
+	defer r.files.Apply(block)
 	_, _ = fmt.Fprintf(r.w, "  %s[tool: %s]%s\n", r.color(colorCyan), block.Name, r.color(colorReset))
 
 	if len(block.Input) == 0 {
@@ -148,31 +383,31 @@ func (r *Renderer) renderToolUse(block ContentBlock) {
 		return
 	}
 
+	path, _ := input["file_path"].(string)
 	switch block.Name {
 	case "Bash":
 		if cmd, ok := input["command"].(string); ok {
-			r.renderToolInput("command", cmd)
+			r.renderCode("command", cmd, "shell")
 		}
 	case "Write":
-		if path, ok := input["file_path"].(string); ok {
+		if path != "" {
 			r.renderToolInput("file", path)
 		}
-		if content, ok := input["content"].(string); ok {
-			r.renderToolInput("content", content)
+		if diff := NewToolDiff(block, r.files.Previous); diff != nil && !diff.NewFile {
+			r.renderToolDiff(diff)
+		} else if content, ok := input["content"].(string); ok {
+			r.renderCode("content", content, DetectLanguage(path, content))
 		}
 	case "Read":
 		if path, ok := input["file_path"].(string); ok {
 			r.renderToolInput("file", path)
 		}
-	case "Edit":
-		if path, ok := input["file_path"].(string); ok {
-			r.renderToolInput("file", path)
-		}
-		if old, ok := input["old_string"].(string); ok {
-			r.renderToolInput("old", old)
-		}
-		if new, ok := input["new_string"].(string); ok {
-			r.renderToolInput("new", new)
+	case "Edit", "MultiEdit", "NotebookEdit":
+		if diff := NewToolDiff(block, nil); diff != nil {
+			if diff.File != "" {
+				r.renderToolInput("file", diff.File)
+			}
+			r.renderToolDiff(diff)
 		}
 	case "Grep":
 		if pattern, ok := input["pattern"].(string); ok {
@@ -195,26 +430,80 @@ func (r *Renderer) renderToolUse(block ContentBlock) {
 	}
 }
 
+// renderToolDiff renders the hunks of a tool call's diff, with the file's
+// unchanged lines highlighted by its language
+func (r *Renderer) renderToolDiff(diff *ToolDiff) {
+	const maxLines = 20
+	if len(diff.Hunks) == 0 {
+		_, _ = fmt.Fprintf(r.w, "    %s(no changes)%s\n", r.color(colorDim), r.color(colorReset))
+		return
+	}
+	var rows []string
+	for _, hunk := range diff.Hunks {
+		rows = append(rows, hunk.Header)
+		rows = append(rows, hunk.Lines...)
+	}
+
+	h := r.newHighlighter(DetectLanguage(diff.File, ""))
+	for i, row := range rows {
+		if !r.full && i >= maxLines {
+			_, _ = fmt.Fprintf(r.w, "    %s... (%d more lines)%s\n", r.color(colorDim), len(rows)-maxLines, r.color(colorReset))
+			break
+		}
+		switch {
+		case strings.HasPrefix(row, "@@"):
+			_, _ = fmt.Fprintf(r.w, "    %s%s%s\n", r.color(colorCyan), row, r.color(colorReset))
+		case row[0] == '+':
+			_, _ = fmt.Fprintf(r.w, "    %s%s%s\n", r.color(colorGreen), row, r.color(colorReset))
+		case row[0] == '-':
+			_, _ = fmt.Fprintf(r.w, "    %s%s%s\n", r.color(colorRed), row, r.color(colorReset))
+		default:
+			spans := append([]span{{style: colorDim, text: " "}}, codeSpans(h, row[1:], colorDim)...)
+			_, _ = fmt.Fprintf(r.w, "    %s\n", r.styled(toCells(spans)))
+		}
+	}
+}
+
 func (r *Renderer) renderToolInput(label, value string) {
+	r.renderCode(label, value, "")
+}
+
+// renderCode renders a tool input highlighted as code of the given language,
+// or dimmed if the language is unknown
+func (r *Renderer) renderCode(label, value, lang string) {
+	const maxLines = 10
+	h := r.newHighlighter(lang)
 	lines := strings.Split(value, "\n")
 	if len(lines) == 1 {
-		// Single line - show inline, truncate if long
-		display := value
-		if len(display) > 100 {
-			display = display[:100] + "..."
+		// Single line - show inline, wrapped to the terminal or truncated if
+		// the width isn't known
+		if r.width <= 0 && !r.full && len(value) > 100 {
+			value = value[:100] + "..."
 		}
-		_, _ = fmt.Fprintf(r.w, "  %s%s: %s%s\n", r.color(colorDim), label, display, r.color(colorReset))
-	} else {
-		// Multi-line - show indented block
-		_, _ = fmt.Fprintf(r.w, "  %s%s:%s\n", r.color(colorDim), label, r.color(colorReset))
-		maxLines := 10
-		for i, line := range lines {
-			if i >= maxLines {
-				_, _ = fmt.Fprintf(r.w, "    %s... (%d more lines)%s\n", r.color(colorDim), len(lines)-maxLines, r.color(colorReset))
+		spans := append([]span{{style: colorDim, text: label + ": "}}, codeSpans(h, value, colorDim)...)
+		wrapped := wrapCells(toCells(spans), r.wrapWidth(2), r.wrapWidth(4))
+		for i, line := range wrapped {
+			if i == 0 {
+				_, _ = fmt.Fprintf(r.w, "  %s\n", r.styled(line))
+				continue
+			}
+			if !r.full && i >= maxLines {
+				_, _ = fmt.Fprintf(r.w, "    %s... (%d more lines)%s\n", r.color(colorDim), len(wrapped)-maxLines, r.color(colorReset))
 				break
 			}
-			_, _ = fmt.Fprintf(r.w, "    %s%s%s\n", r.color(colorDim), line, r.color(colorReset))
+			_, _ = fmt.Fprintf(r.w, "    %s\n", r.styled(line))
 		}
+		return
+	}
+
+	// Multi-line - show indented block
+	_, _ = fmt.Fprintf(r.w, "  %s%s:%s\n", r.color(colorDim), label, r.color(colorReset))
+	for i, line := range lines {
+		if !r.full && i >= maxLines {
+			_, _ = fmt.Fprintf(r.w, "    %s... (%d more lines)%s\n", r.color(colorDim), len(lines)-maxLines, r.color(colorReset))
+			break
+		}
+		_, _ = fmt.Fprintf(r.w, "    %s\n", r.styled(toCells(codeSpans(h, line, colorDim))))
 	}
 }
 
@@ -238,6 +527,8 @@ func (r *Renderer) renderToolResult(block ContentBlock) {
 		for _, b := range blocks {
 			if b.Type == "text" && b.Text != "" {
 				r.renderToolResultContent(b.Text)
+			} else if IsAttachment(b) {
+				r.renderAttachment(b)
 			}
 		}
 		return
@@ -245,12 +536,29 @@ func (r *Renderer) renderToolResult(block ContentBlock) {
 
 	// Fallback: show raw content (truncated)
 	raw := string(block.Content)
-	if len(raw) > 200 {
+	if !r.full && len(raw) > 200 {
 		raw = raw[:200] + "..."
 	}
 	_, _ = fmt.Fprintf(r.w, "  %s%s%s\n", r.color(colorDim), raw, r.color(colorReset))
 }
 
+// renderAttachment renders a placeholder for an image or document, and
+// draws images inline if the terminal can
+func (r *Renderer) renderAttachment(block ContentBlock) {
+	a, ok := NewAttachment("", block)
+	if !ok {
+		_, _ = fmt.Fprintf(r.w, "  %s[%s]%s\n", r.color(colorMagenta), block.Type, r.color(colorReset))
+		return
+	}
+	_, _ = fmt.Fprintf(r.w, "  %s[%s]%s %s%s%s\n", r.color(colorMagenta), a.Type, r.color(colorReset), r.color(colorDim), a.Describe(), r.color(colorReset))
+	if a.Type == "image" && termimage.Fits(a.Width, a.Height) && r.images != termimage.None {
+		_, _ = fmt.Fprint(r.w, "  ")
+		if err := termimage.Write(r.w, r.images, a.Data, r.wrapWidth(2)); err != nil {
+			_, _ = fmt.Fprintln(r.w)
+		}
+	}
+}
+
 func (r *Renderer) renderToolResultContent(content string) {
 	if content == "" {
 		return
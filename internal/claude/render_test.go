@@ -2,10 +2,14 @@ package claude
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/DanielJonesEB/claudit/internal/termimage"
 )
 
 func TestRendererUserMessage(t *testing.T) {
@@ -361,3 +365,118 @@ func TestRendererSkipsProgressEntries(t *testing.T) {
 		t.Errorf("Output should not have excessive blank lines from skipped entries")
 	}
 }
+
+func TestRendererCollapsed(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	r.RenderCollapsed(ContentBlock{Type: "tool_use", Name: "Bash", Input: json.RawMessage(`{"description":"Run tests","command":"go test ./..."}`)})
+	r.RenderCollapsed(ContentBlock{Type: "thinking", Thinking: "First thought\nSecond thought"})
+	r.RenderCollapsed(ContentBlock{Type: "tool_result", Content: json.RawMessage(`"FAIL"`), IsError: true})
+
+	want := "  [tool: Bash] go test ./...\n" +
+		"  [thinking] First thought (2 lines)\n" +
+		"  [tool error] FAIL\n"
+	if buf.String() != want {
+		t.Errorf("collapsed blocks = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRendererFull(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	thinking := ContentBlock{Type: "thinking", Thinking: "1\n2\n3\n4\n5"}
+
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.RenderBlock(nil, thinking)
+	if !strings.Contains(buf.String(), "(2 more lines)") {
+		t.Errorf("thinking should be truncated, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	r.SetFull(true)
+	r.RenderBlock(nil, thinking)
+	if strings.Contains(buf.String(), "more lines") || !strings.Contains(buf.String(), "  5\n") {
+		t.Errorf("thinking should be shown in full, got: %s", buf.String())
+	}
+}
+
+func TestRendererEditDiff(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	r.RenderBlock(nil, ContentBlock{Type: "tool_use", Name: "Edit", Input: json.RawMessage(
+		`{"file_path":"/repo/main.go","old_string":"a\nb\nc","new_string":"a\nB\nc"}`)})
+
+	want := "  [tool: Edit]\n" +
+		"  file: /repo/main.go\n" +
+		"    @@ -1,3 +1,3 @@\n" +
+		"     a\n" +
+		"    -b\n" +
+		"    +B\n" +
+		"     c\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRendererWriteDiff(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	write := ContentBlock{Type: "tool_use", Name: "Write", Input: json.RawMessage(`{"file_path":"/repo/notes.txt","content":"kept\nadded\n"}`)}
+
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.SetPreviousContent(func(path string) (string, bool) { return "kept\nremoved\n", true })
+	r.RenderBlock(nil, write)
+	if !strings.Contains(buf.String(), "    -removed\n    +added\n") {
+		t.Errorf("Write should be diffed against the previous content, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	r.SetPreviousContent(nil)
+	r.RenderBlock(nil, write)
+	if !strings.Contains(buf.String(), "content:\n    kept\n    added\n") {
+		t.Errorf("Write of a new file should show its content, got: %s", buf.String())
+	}
+}
+
+func TestRendererAttachment(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	data := testPNG(t, 4, 3)
+	block := ContentBlock{Type: "image", Source: &BlockSource{Type: "base64", MediaType: "image/png", Data: base64.StdEncoding.EncodeToString(data)}}
+
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.RenderBlock(nil, block)
+	if want := fmt.Sprintf("  [image] image/png 4×3, %d B\n", len(data)); buf.String() != want {
+		t.Errorf("placeholder = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	r.SetInlineImages(termimage.Kitty)
+	r.RenderBlock(nil, block)
+	if !strings.Contains(buf.String(), "\033_Ga=T,f=100,") {
+		t.Errorf("image should be drawn inline, got %q", buf.String())
+	}
+}
+
+func TestRendererBranch(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	tree := NewTree(parseEntries(t, editedTranscript))
+	branch := tree.Branches(tree.ActivePath())[0]
+
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+	r.RenderBranch(branch, false)
+	if want := "  ⎇ abandoned branch, 2 messages: Add a sleep\n"; buf.String() != want {
+		t.Errorf("collapsed = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	r.RenderBranch(branch, true)
+	if !strings.Contains(buf.String(), "  ┆   Sleeping\n") {
+		t.Errorf("expanded branch should be behind a gutter, got %q", buf.String())
+	}
+}
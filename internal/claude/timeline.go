@@ -0,0 +1,134 @@
+package claude
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultIdleThreshold is the gap between entries above which a session is
+// considered idle rather than active
+const DefaultIdleThreshold = 5 * time.Minute
+
+// Span is a period of time
+type Span struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration returns the length of the span
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// CommitMark is a commit made during a session
+type CommitMark struct {
+	SHA  string    `json:"sha"`
+	Time time.Time `json:"time"`
+}
+
+// Timeline is the timing of a session, worked out from entry timestamps
+type Timeline struct {
+	Span
+	// Active holds the periods in which entries were less than the idle
+	// threshold apart
+	Active []Span `json:"active"`
+	// Gaps holds the idle periods between them
+	Gaps []Span `json:"gaps"`
+	// Commits are the commits made during the session, oldest first
+	Commits []CommitMark `json:"commits"`
+}
+
+// EntryTime returns the parsed timestamp of an entry
+func EntryTime(entry *TranscriptEntry) (time.Time, bool) {
+	if entry.Timestamp == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+	return t, err == nil
+}
+
+// NewTimeline builds the timeline of the entries. Gaps between consecutive
+// entries longer than idleThreshold count as idle time. Returns nil if no
+// entry has a timestamp.
+func NewTimeline(entries []TranscriptEntry, idleThreshold time.Duration) *Timeline {
+	var times []time.Time
+	for i := range entries {
+		if t, ok := EntryTime(&entries[i]); ok {
+			times = append(times, t)
+		}
+	}
+	if len(times) == 0 {
+		return nil
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	tl := &Timeline{Span: Span{Start: times[0], End: times[len(times)-1]}, Active: []Span{}, Gaps: []Span{}, Commits: []CommitMark{}}
+	current := Span{Start: times[0], End: times[0]}
+	for _, t := range times[1:] {
+		if t.Sub(current.End) > idleThreshold {
+			tl.Active = append(tl.Active, current)
+			tl.Gaps = append(tl.Gaps, Span{Start: current.End, End: t})
+			current = Span{Start: t, End: t}
+			continue
+		}
+		current.End = t
+	}
+	tl.Active = append(tl.Active, current)
+	return tl
+}
+
+// ActiveTime returns the total time the session was active
+func (t *Timeline) ActiveTime() time.Duration {
+	return sumSpans(t.Active)
+}
+
+// IdleTime returns the total time the session was idle
+func (t *Timeline) IdleTime() time.Duration {
+	return sumSpans(t.Gaps)
+}
+
+// AddCommits records commits made during the session, keeping them in time order
+func (t *Timeline) AddCommits(commits ...CommitMark) {
+	t.Commits = append(t.Commits, commits...)
+	sort.SliceStable(t.Commits, func(i, j int) bool { return t.Commits[i].Time.Before(t.Commits[j].Time) })
+}
+
+// TimeToFirstCommit returns the time from the start of the session to its
+// first commit, or false if there are no commits
+func (t *Timeline) TimeToFirstCommit() (time.Duration, bool) {
+	if len(t.Commits) == 0 {
+		return 0, false
+	}
+	return t.Commits[0].Time.Sub(t.Start), true
+}
+
+// TimeBetweenCommits returns the time between each pair of consecutive commits
+func (t *Timeline) TimeBetweenCommits() []time.Duration {
+	var gaps []time.Duration
+	for i := 1; i < len(t.Commits); i++ {
+		gaps = append(gaps, t.Commits[i].Time.Sub(t.Commits[i-1].Time))
+	}
+	return gaps
+}
+
+// MeanTimeBetweenCommits returns the average time between consecutive
+// commits, or false if there are fewer than two
+func (t *Timeline) MeanTimeBetweenCommits() (time.Duration, bool) {
+	gaps := t.TimeBetweenCommits()
+	if len(gaps) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for _, gap := range gaps {
+		total += gap
+	}
+	return total / time.Duration(len(gaps)), true
+}
+
+func sumSpans(spans []Span) time.Duration {
+	var total time.Duration
+	for _, s := range spans {
+		total += s.Duration()
+	}
+	return total
+}
@@ -0,0 +1,62 @@
+package claude
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTimeline(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) TranscriptEntry {
+		return TranscriptEntry{Timestamp: start.Add(offset).Format(time.RFC3339Nano)}
+	}
+	entries := []TranscriptEntry{
+		at(0),
+		at(2 * time.Minute),
+		{}, // entries without timestamps are ignored
+		at(4 * time.Minute),
+		at(20 * time.Minute), // 16 minute gap
+		at(23 * time.Minute),
+	}
+
+	tl := NewTimeline(entries, DefaultIdleThreshold)
+	if tl == nil {
+		t.Fatal("NewTimeline() returned nil")
+	}
+	if tl.Duration() != 23*time.Minute {
+		t.Errorf("Duration() = %s, want 23m", tl.Duration())
+	}
+	if len(tl.Active) != 2 || len(tl.Gaps) != 1 {
+		t.Fatalf("got %d active spans and %d gaps, want 2 and 1", len(tl.Active), len(tl.Gaps))
+	}
+	if tl.ActiveTime() != 7*time.Minute {
+		t.Errorf("ActiveTime() = %s, want 7m", tl.ActiveTime())
+	}
+	if tl.IdleTime() != 16*time.Minute {
+		t.Errorf("IdleTime() = %s, want 16m", tl.IdleTime())
+	}
+
+	if _, ok := tl.TimeToFirstCommit(); ok {
+		t.Error("TimeToFirstCommit() reported a commit before any were added")
+	}
+
+	tl.AddCommits(
+		CommitMark{SHA: "b", Time: start.Add(22 * time.Minute)},
+		CommitMark{SHA: "a", Time: start.Add(5 * time.Minute)},
+	)
+	if tl.Commits[0].SHA != "a" {
+		t.Errorf("commits not sorted by time: %+v", tl.Commits)
+	}
+	if d, ok := tl.TimeToFirstCommit(); !ok || d != 5*time.Minute {
+		t.Errorf("TimeToFirstCommit() = %s, %v, want 5m", d, ok)
+	}
+	if d, ok := tl.MeanTimeBetweenCommits(); !ok || d != 17*time.Minute {
+		t.Errorf("MeanTimeBetweenCommits() = %s, %v, want 17m", d, ok)
+	}
+}
+
+func TestNewTimelineWithoutTimestamps(t *testing.T) {
+	if tl := NewTimeline([]TranscriptEntry{{}, {Timestamp: "not a time"}}, DefaultIdleThreshold); tl != nil {
+		t.Errorf("NewTimeline() = %+v, want nil", tl)
+	}
+}
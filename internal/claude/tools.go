@@ -0,0 +1,81 @@
+package claude
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ToolCall is a tool_use block paired with the tool_result that answered it
+type ToolCall struct {
+	ID    string
+	Name  string
+	Input json.RawMessage
+	// EntryIndex is the index of the assistant entry containing the call
+	EntryIndex int
+	// Result is nil if the transcript has no result for the call
+	Result *ContentBlock
+}
+
+// Failed returns true if the call's result was an error
+func (c ToolCall) Failed() bool {
+	return c.Result != nil && c.Result.IsError
+}
+
+// Command returns the command of a Bash call, or "" for other tools
+func (c ToolCall) Command() string {
+	if c.Name != "Bash" || len(c.Input) == 0 {
+		return ""
+	}
+	var input struct {
+		Command string `json:"command"`
+	}
+	_ = json.Unmarshal(c.Input, &input)
+	return input.Command
+}
+
+// ToolCalls returns the tool calls in entries, in order, each paired with
+// its result when there is one
+func ToolCalls(entries []TranscriptEntry) []ToolCall {
+	var calls []ToolCall
+	byID := make(map[string]int)
+	for i, entry := range entries {
+		if entry.Message == nil {
+			continue
+		}
+		for j, block := range entry.Message.Content {
+			switch block.Type {
+			case "tool_use":
+				byID[block.ID] = len(calls)
+				calls = append(calls, ToolCall{ID: block.ID, Name: block.Name, Input: block.Input, EntryIndex: i})
+			case "tool_result":
+				if k, ok := byID[block.ToolUseID]; ok {
+					calls[k].Result = &entry.Message.Content[j]
+				}
+			}
+		}
+	}
+	return calls
+}
+
+// ResultText returns the text of a tool_result block, whose content is
+// either a string or a list of content blocks
+func ResultText(block ContentBlock) string {
+	if len(block.Content) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(block.Content, &text); err == nil {
+		return text
+	}
+	var blocks []ContentBlock
+	if err := json.Unmarshal(block.Content, &blocks); err != nil {
+		return string(block.Content)
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
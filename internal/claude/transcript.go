@@ -26,6 +26,21 @@ type ContentBlock struct {
 	Input     json.RawMessage `json:"input,omitempty"`
 	ToolUseID string          `json:"tool_use_id,omitempty"`
 	Content   json.RawMessage `json:"content,omitempty"`
+	// IsError is set on tool results for calls that failed
+	IsError bool `json:"is_error,omitempty"`
+	// Source holds the data of image and document blocks
+	Source *BlockSource `json:"source,omitempty"`
+	// Title is the name given to a document block
+	Title string `json:"title,omitempty"`
+}
+
+// BlockSource is the data of an image or document block: base64 data, a
+// URL, or plain text for text documents
+type BlockSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
 // TranscriptEntry represents a single entry in the JSONL transcript
@@ -37,12 +52,20 @@ type TranscriptEntry struct {
 	Message                 *Message        `json:"message,omitempty"`
 	SourceToolAssistantUUID string          `json:"sourceToolAssistantUUID,omitempty"`
 	Raw                     json.RawMessage `json:"-"`
+	// LogicalParentUUID links an entry that starts over after compaction
+	// to the entry it follows
+	LogicalParentUUID string `json:"logicalParentUuid,omitempty"`
 }
 
 // Message represents a message content structure
 type Message struct {
-	Role    string         `json:"role,omitempty"`
-	Content []ContentBlock `json:"-"` // Custom unmarshal handles string or array
+	// ID identifies the API response; assistant messages with several
+	// content blocks are split across entries that share it
+	ID         string          `json:"id,omitempty"`
+	Role       string          `json:"role,omitempty"`
+	Model      string          `json:"model,omitempty"`
+	Usage      *Usage          `json:"usage,omitempty"`
+	Content    []ContentBlock  `json:"-"` // Custom unmarshal handles string or array
 	RawContent json.RawMessage `json:"content,omitempty"`
 }
 
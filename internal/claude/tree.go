@@ -0,0 +1,186 @@
+package claude
+
+import "sort"
+
+// TreeNode is an entry in the tree of a transcript
+type TreeNode struct {
+	Entry TranscriptEntry
+	// Index is the entry's position in the transcript
+	Index    int
+	Parent   *TreeNode
+	Children []*TreeNode
+	// OnPath is set for the entries on the tree's active path
+	OnPath bool
+}
+
+// Tree links a transcript's entries through their parentUuid, which branches
+// when a message is edited, retried or rewound to
+type Tree struct {
+	Root *TreeNode
+	// Path is the active path, from the root to the last entry written,
+	// which is the one the commit was made after
+	Path   []*TreeNode
+	byUUID map[string]*TreeNode
+}
+
+// Branch is a part of a conversation that was abandoned, e.g. by editing
+// an earlier message
+type Branch struct {
+	// ForkUUID is the entry on the active path the branch continues from
+	ForkUUID string            `json:"fork_uuid"`
+	Entries  []TranscriptEntry `json:"entries"`
+	// After is the entry to show the branch after, set by PlaceBranches
+	After string `json:"after,omitempty"`
+}
+
+// NewTree builds the tree of entries. Entries without a UUID, such as
+// summaries, are left out. An entry whose parent isn't in the transcript,
+// or that has none, follows the entry before it, so that transcripts
+// without parent links form a single path.
+func NewTree(entries []TranscriptEntry) *Tree {
+	t := &Tree{byUUID: make(map[string]*TreeNode)}
+	var previous *TreeNode
+	for i, entry := range entries {
+		if entry.UUID == "" {
+			continue
+		}
+		node := &TreeNode{Entry: entry, Index: i}
+		parentUUID := entry.ParentUUID
+		if parentUUID == "" {
+			parentUUID = entry.LogicalParentUUID
+		}
+		parent, ok := t.byUUID[parentUUID]
+		if !ok {
+			parent = previous
+		}
+		if parent == nil {
+			t.Root = node
+		} else {
+			node.Parent = parent
+			parent.Children = append(parent.Children, node)
+		}
+		if _, seen := t.byUUID[entry.UUID]; !seen {
+			t.byUUID[entry.UUID] = node
+		}
+		previous = node
+	}
+
+	for node := previous; node != nil; node = node.Parent {
+		node.OnPath = true
+		t.Path = append(t.Path, node)
+	}
+	for i, j := 0, len(t.Path)-1; i < j; i, j = i+1, j-1 {
+		t.Path[i], t.Path[j] = t.Path[j], t.Path[i]
+	}
+	return t
+}
+
+// ActivePath returns the entries on the active path
+func (t *Tree) ActivePath() []TranscriptEntry {
+	return nodeEntries(t.Path)
+}
+
+// PathSince returns the entries on the active path after the entry with
+// the given UUID. If that entry was abandoned, the path is taken from where
+// its branch forked. Returns the whole path if the UUID isn't found.
+func (t *Tree) PathSince(uuid string) []TranscriptEntry {
+	node := t.byUUID[uuid]
+	for node != nil && !node.OnPath {
+		node = node.Parent
+	}
+	if node == nil {
+		return t.ActivePath()
+	}
+	for i, n := range t.Path {
+		if n == node {
+			return nodeEntries(t.Path[i+1:])
+		}
+	}
+	return t.ActivePath()
+}
+
+// Branches returns the abandoned branches that fork from the given entries
+// of the active path, in order. Branches with no messages, e.g. only
+// progress updates, are left out.
+func (t *Tree) Branches(path []TranscriptEntry) []Branch {
+	var branches []Branch
+	for _, entry := range path {
+		node := t.byUUID[entry.UUID]
+		if node == nil || !node.OnPath {
+			continue
+		}
+		for _, child := range node.Children {
+			if child.OnPath {
+				continue
+			}
+			entries := nodeEntries(subtree(child))
+			if hasMessages(entries) {
+				branches = append(branches, Branch{ForkUUID: entry.UUID, Entries: entries})
+			}
+		}
+	}
+	return branches
+}
+
+// PlaceBranches sets where each branch is shown among the entries shown of
+// the active path: after its fork, or after the closest entry before the
+// fork that is shown, or "" if none is
+func (t *Tree) PlaceBranches(shown []TranscriptEntry, branches []Branch) []Branch {
+	isShown := make(map[string]bool, len(shown))
+	for _, entry := range shown {
+		isShown[entry.UUID] = true
+	}
+	placed := make([]Branch, 0, len(branches))
+	for _, branch := range branches {
+		branch.After = ""
+		for node := t.byUUID[branch.ForkUUID]; node != nil; node = node.Parent {
+			if isShown[node.Entry.UUID] {
+				branch.After = node.Entry.UUID
+				break
+			}
+		}
+		placed = append(placed, branch)
+	}
+	return placed
+}
+
+// FilterBranches applies filters to the entries of each branch, leaving out
+// branches with nothing left
+func FilterBranches(branches []Branch, filters ...EntryFilter) []Branch {
+	var filtered []Branch
+	for _, branch := range branches {
+		branch.Entries = ApplyFilters(branch.Entries, filters...)
+		if len(branch.Entries) > 0 {
+			filtered = append(filtered, branch)
+		}
+	}
+	return filtered
+}
+
+// subtree returns a node and its descendants in transcript order
+func subtree(node *TreeNode) []*TreeNode {
+	nodes := []*TreeNode{node}
+	for i := 0; i < len(nodes); i++ {
+		nodes = append(nodes, nodes[i].Children...)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Index < nodes[j].Index })
+	return nodes
+}
+
+func nodeEntries(nodes []*TreeNode) []TranscriptEntry {
+	entries := make([]TranscriptEntry, 0, len(nodes))
+	for _, node := range nodes {
+		entries = append(entries, node.Entry)
+	}
+	return entries
+}
+
+// hasMessages returns true if any entry is a user, assistant or system message
+func hasMessages(entries []TranscriptEntry) bool {
+	for _, entry := range entries {
+		if _, ok := messageStyles[entry.Type]; ok && entry.Message != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package claude
+
+import (
+	"reflect"
+	"testing"
+)
+
+// editedTranscript has its second user message edited: u2 and a2 were
+// abandoned for u3 and a3
+const editedTranscript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Fix the test"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":"Retrying"}}
+{"uuid":"u2","parentUuid":"a1","type":"user","message":{"role":"user","content":"Add a sleep"}}
+{"uuid":"a2","parentUuid":"u2","type":"assistant","message":{"role":"assistant","content":"Sleeping"}}
+{"uuid":"u3","parentUuid":"a1","type":"user","message":{"role":"user","content":"Mock the clock"}}
+{"uuid":"a3","parentUuid":"u3","type":"assistant","message":{"role":"assistant","content":"Mocked"}}
+`
+
+func TestTreeActivePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		want       []string
+	}{
+		{
+			name:       "edited message",
+			transcript: editedTranscript,
+			want:       []string{"u1", "a1", "u3", "a3"},
+		},
+		{
+			name: "no parent links",
+			transcript: `{"uuid":"u1","type":"user","message":{"role":"user","content":"Hi"}}
+{"type":"summary","summary":"Greeting"}
+{"uuid":"a1","type":"assistant","message":{"role":"assistant","content":"Hello"}}`,
+			want: []string{"u1", "a1"},
+		},
+		{
+			name: "compacted with a logical parent",
+			transcript: `{"uuid":"u1","type":"user","message":{"role":"user","content":"Hi"}}
+{"uuid":"c1","logicalParentUuid":"u1","type":"system","message":{"role":"system","content":"Compacted"}}
+{"uuid":"u2","parentUuid":"c1","type":"user","message":{"role":"user","content":"Go on"}}`,
+			want: []string{"u1", "c1", "u2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree(parseEntries(t, tt.transcript))
+			if got := uuids(tree.ActivePath()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ActivePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTreeBranches(t *testing.T) {
+	tree := NewTree(parseEntries(t, editedTranscript))
+	branches := tree.Branches(tree.ActivePath())
+	if len(branches) != 1 {
+		t.Fatalf("want 1 branch, got %d", len(branches))
+	}
+	if branches[0].ForkUUID != "a1" {
+		t.Errorf("ForkUUID = %q, want a1", branches[0].ForkUUID)
+	}
+	if got := uuids(branches[0].Entries); !reflect.DeepEqual(got, []string{"u2", "a2"}) {
+		t.Errorf("Entries = %v, want [u2 a2]", got)
+	}
+
+	// The branch moves up to the closest entry shown before its fork
+	shown := ApplyFilters(tree.ActivePath(), OnlyTypes(MessageTypeUser))
+	placed := tree.PlaceBranches(shown, branches)
+	if placed[0].After != "u1" {
+		t.Errorf("After = %q, want u1", placed[0].After)
+	}
+}
+
+func TestTreePathSince(t *testing.T) {
+	tree := NewTree(parseEntries(t, editedTranscript))
+	tests := []struct {
+		uuid string
+		want []string
+	}{
+		{uuid: "u3", want: []string{"a3"}},
+		// The last commit was made in the abandoned branch
+		{uuid: "a2", want: []string{"u3", "a3"}},
+		{uuid: "missing", want: []string{"u1", "a1", "u3", "a3"}},
+	}
+	for _, tt := range tests {
+		if got := uuids(tree.PathSince(tt.uuid)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PathSince(%q) = %v, want %v", tt.uuid, got, tt.want)
+		}
+	}
+}
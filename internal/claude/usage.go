@@ -0,0 +1,125 @@
+package claude
+
+import (
+	"sort"
+	"strings"
+)
+
+// Usage is the token usage reported for an API response
+type Usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// Add adds other to u
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationInputTokens += other.CacheCreationInputTokens
+	u.CacheReadInputTokens += other.CacheReadInputTokens
+}
+
+// Total returns the total number of tokens
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// EntryUsage is the usage of a single API response
+type EntryUsage struct {
+	// Index is the index of the first entry of the response
+	Index int
+	Model string
+	Usage Usage
+}
+
+// ResponseUsage returns the usage of each API response in entries. Claude
+// Code writes one entry per content block, each repeating the response's
+// usage, so entries sharing a message ID are counted once.
+func ResponseUsage(entries []TranscriptEntry) []EntryUsage {
+	var result []EntryUsage
+	seen := make(map[string]int)
+	for i, entry := range entries {
+		if entry.Type != MessageTypeAssistant || entry.Message == nil || entry.Message.Usage == nil {
+			continue
+		}
+		msg := entry.Message
+		if msg.ID != "" {
+			if j, ok := seen[msg.ID]; ok {
+				// Later entries of a streamed response carry the final counts
+				result[j].Usage = *msg.Usage
+				continue
+			}
+			seen[msg.ID] = len(result)
+		}
+		result = append(result, EntryUsage{Index: i, Model: msg.Model, Usage: *msg.Usage})
+	}
+	return result
+}
+
+// Price is the price of a model in US dollars per million tokens
+type Price struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheWrite float64 `json:"cache_write"`
+	CacheRead  float64 `json:"cache_read"`
+}
+
+// Cost returns the cost of the usage in US dollars
+func (p Price) Cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationInputTokens)*p.CacheWrite +
+		float64(u.CacheReadInputTokens)*p.CacheRead) / 1e6
+}
+
+// PriceTable maps model names, or prefixes of them, to prices
+type PriceTable map[string]Price
+
+// DefaultPrices returns the list prices of Claude models at the time of
+// writing. They are estimates; override them in .claudit/config.
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		"claude-opus-4-5":   {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.50},
+		"claude-opus-4":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50},
+		"claude-sonnet-4":   {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+		"claude-haiku-4-5":  {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.10},
+		"claude-3-opus":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50},
+		"claude-3-7-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+		"claude-3-5-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+		"claude-3-5-haiku":  {Input: 0.80, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+		"claude-3-haiku":    {Input: 0.25, Output: 1.25, CacheWrite: 0.30, CacheRead: 0.03},
+	}
+}
+
+// With returns a copy of the table with the given prices added or replaced
+func (t PriceTable) With(overrides map[string]Price) PriceTable {
+	merged := make(PriceTable, len(t)+len(overrides))
+	for model, price := range t {
+		merged[model] = price
+	}
+	for model, price := range overrides {
+		merged[model] = price
+	}
+	return merged
+}
+
+// Lookup returns the price of a model: an exact match, or else the longest
+// prefix of the model name in the table
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+	var prefixes []string
+	for prefix := range t {
+		if strings.HasPrefix(model, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return Price{}, false
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	return t[prefixes[0]], true
+}
@@ -0,0 +1,65 @@
+package claude
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const usageTranscript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Hi"}}
+{"uuid":"a1","type":"assistant","message":{"id":"msg_1","role":"assistant","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":1,"cache_read_input_tokens":100},"content":[{"type":"thinking","thinking":"..."}]}}
+{"uuid":"a2","type":"assistant","message":{"id":"msg_1","role":"assistant","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":50,"cache_read_input_tokens":100},"content":[{"type":"text","text":"Hello"}]}}
+{"uuid":"a3","type":"assistant","message":{"id":"msg_2","role":"assistant","model":"claude-opus-4-5-20251101","usage":{"input_tokens":5,"output_tokens":20,"cache_creation_input_tokens":1000},"content":[{"type":"text","text":"Bye"}]}}`
+
+func TestResponseUsage(t *testing.T) {
+	transcript, err := ParseTranscript(strings.NewReader(usageTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+
+	responses := ResponseUsage(transcript.Entries)
+	if len(responses) != 2 {
+		t.Fatalf("ResponseUsage() returned %d responses, want 2", len(responses))
+	}
+
+	// Entries sharing a message ID are counted once, with the final counts
+	want := Usage{InputTokens: 10, OutputTokens: 50, CacheReadInputTokens: 100}
+	if responses[0].Usage != want || responses[0].Index != 1 {
+		t.Errorf("response 0 = %+v, want %+v at index 1", responses[0], want)
+	}
+	if responses[1].Model != "claude-opus-4-5-20251101" || responses[1].Usage.Total() != 1025 {
+		t.Errorf("response 1 = %+v", responses[1])
+	}
+}
+
+func TestPriceTableLookup(t *testing.T) {
+	prices := DefaultPrices().With(map[string]Price{"custom-model": {Input: 1}})
+
+	tests := []struct {
+		model string
+		input float64
+		found bool
+	}{
+		{"claude-opus-4-5-20251101", 5, true}, // longest prefix wins over claude-opus-4
+		{"claude-opus-4-1-20250805", 15, true},
+		{"claude-sonnet-4-20250514", 3, true},
+		{"custom-model", 1, true},
+		{"<synthetic>", 0, false},
+	}
+	for _, tc := range tests {
+		price, found := prices.Lookup(tc.model)
+		if found != tc.found || price.Input != tc.input {
+			t.Errorf("Lookup(%q) = %+v, %v; want input %v, %v", tc.model, price, found, tc.input, tc.found)
+		}
+	}
+}
+
+func TestPriceCost(t *testing.T) {
+	price := Price{Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30}
+	usage := Usage{InputTokens: 1_000_000, OutputTokens: 100_000, CacheCreationInputTokens: 200_000, CacheReadInputTokens: 1_000_000}
+
+	// 3 + 1.5 + 0.75 + 0.30
+	if got := price.Cost(usage); math.Abs(got-5.55) > 1e-9 {
+		t.Errorf("Cost() = %v, want 5.55", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/DanielJonesEB/claudit/internal/claude"
 	"github.com/DanielJonesEB/claudit/internal/util"
 )
 
@@ -15,6 +16,13 @@ const clauditDir = ".claudit"
 type Config struct {
 	NotesRef string `json:"notes_ref"`
 	Debug    bool   `json:"debug"`
+	// Pricing overrides the built-in per-model prices used to estimate cost,
+	// keyed by model name or prefix, in US dollars per million tokens
+	Pricing map[string]claude.Price `json:"pricing,omitempty"`
+	// Summarizer is a shell command that summarizes conversations as they
+	// are stored, given the transcript on stdin. Without one, summaries are
+	// made from the first prompt, files, tools and final reply.
+	Summarizer string `json:"summarizer,omitempty"`
 }
 
 // Read reads the config from .claudit/config in the project root.
@@ -0,0 +1,221 @@
+// Package export renders stored conversations as documents for sharing
+// outside of claudit, e.g. in design reviews and audits.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+// Export formats
+const (
+	Markdown = "markdown"
+	HTML     = "html"
+	JSONL    = "jsonl"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{Markdown, HTML, JSONL}
+
+// Conversation is the part of a commit's conversation to export
+type Conversation struct {
+	SHA       string
+	Subject   string
+	Author    string
+	Date      time.Time
+	SessionID string
+	Branch    string
+	// ParentSHA is the previous commit of the session when Entries only
+	// hold the conversation since then
+	ParentSHA string
+	Entries   []claude.TranscriptEntry
+}
+
+// Write renders the conversations to w in the given format
+func Write(w io.Writer, format string, convs []Conversation) error {
+	switch format {
+	case Markdown:
+		return WriteMarkdown(w, convs)
+	case HTML:
+		return WriteHTML(w, convs)
+	case JSONL:
+		return WriteJSONL(w, convs)
+	}
+	return fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteJSONL writes the transcript entries as they were recorded, one per line
+func WriteJSONL(w io.Writer, convs []Conversation) error {
+	for _, conv := range convs {
+		for _, entry := range conv.Entries {
+			line := entry.Raw
+			if len(line) == 0 {
+				var err error
+				if line, err = json.Marshal(entry); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Kinds of section
+const (
+	kindText     = "text"
+	kindThinking = "thinking"
+	kindTool     = "tool"
+	kindResult   = "result"
+)
+
+// section is a piece of a conversation, laid out the same way by every format
+type section struct {
+	// Role starts a new turn: "User", "Assistant" or "System"
+	Role string
+	Kind string
+	// Text is the message text, tool input or tool output
+	Text string
+	// Lang is the language of Text for syntax highlighting, if it is code
+	Lang string
+	// Title summarizes a tool call or result, e.g. "Edit main.go"
+	Title string
+	// Diff holds the -/+ lines of a file edit, replacing Text
+	Diff []string
+	// Error is set for failed tool calls
+	Error bool
+}
+
+// sections lays out the entries of a conversation. A new turn starts when
+// the speaker changes; tool results stay in the assistant's turn.
+func sections(entries []claude.TranscriptEntry) []section {
+	edits := make(map[string][]claude.FileEdit)
+	for _, edit := range claude.ExtractFileEdits(entries) {
+		edits[edit.ToolUseID] = append(edits[edit.ToolUseID], edit)
+	}
+	toolNames := make(map[string]string)
+
+	var out []section
+	role := ""
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Message == nil {
+			continue
+		}
+
+		speaker := ""
+		switch {
+		case claude.IsPrompt(entry):
+			speaker = "User"
+		case entry.Type == claude.MessageTypeAssistant:
+			speaker = "Assistant"
+		case entry.Type == claude.MessageTypeSystem:
+			speaker = "System"
+		case entry.Type != claude.MessageTypeUser:
+			continue
+		}
+		// Only a new speaker starts a turn
+		turn := ""
+		if speaker != "" && speaker != role {
+			turn, role = speaker, speaker
+		}
+
+		for _, block := range entry.Message.Content {
+			s := section{Role: turn}
+			switch block.Type {
+			case "text":
+				if strings.TrimSpace(block.Text) == "" {
+					continue
+				}
+				s.Kind, s.Text = kindText, strings.TrimSpace(block.Text)
+			case "thinking":
+				if strings.TrimSpace(block.Thinking) == "" {
+					continue
+				}
+				s.Kind, s.Title, s.Text = kindThinking, "Thinking", strings.TrimSpace(block.Thinking)
+			case "tool_use":
+				toolNames[block.ID] = block.Name
+				s = toolSection(block, edits[block.ID])
+				s.Role = turn
+			case "tool_result":
+				name := toolNames[block.ToolUseID]
+				if name == "" {
+					name = "tool"
+				}
+				s.Kind, s.Text, s.Error = kindResult, strings.TrimRight(claude.ResultText(block), "\n"), block.IsError
+				s.Title = name + " result"
+				if block.IsError {
+					s.Title = name + " error"
+				}
+			default:
+				continue
+			}
+			out = append(out, s)
+			turn = ""
+		}
+	}
+	return out
+}
+
+// toolSection describes a tool call by its main argument, showing file
+// edits as diffs and other input as code
+func toolSection(block claude.ContentBlock, edits []claude.FileEdit) section {
+	s := section{Kind: kindTool, Title: block.Name}
+
+	var input map[string]interface{}
+	_ = json.Unmarshal(block.Input, &input)
+	for _, key := range []string{"file_path", "notebook_path", "command", "pattern", "url", "description"} {
+		if value, ok := input[key].(string); ok && value != "" {
+			s.Title = block.Name + " " + util.OneLine(value, 80)
+			break
+		}
+	}
+
+	switch {
+	case len(edits) > 0:
+		for _, edit := range edits {
+			s.Diff = append(s.Diff, editDiff(edit)...)
+		}
+	case block.Name == "Bash":
+		s.Text, _ = input["command"].(string)
+		s.Lang = "sh"
+	case len(block.Input) > 0:
+		if pretty, err := json.MarshalIndent(input, "", "  "); err == nil {
+			s.Text = string(pretty)
+		} else {
+			s.Text = string(block.Input)
+		}
+		s.Lang = "json"
+	}
+	return s
+}
+
+// editDiff renders a file edit as removed and added lines
+func editDiff(edit claude.FileEdit) []string {
+	var lines []string
+	if edit.OldString != "" {
+		for _, line := range strings.Split(edit.OldString, "\n") {
+			lines = append(lines, "-"+line)
+		}
+	}
+	for _, line := range strings.Split(edit.NewString, "\n") {
+		lines = append(lines, "+"+line)
+	}
+	return lines
+}
+
+// scope describes which part of the session is exported
+func (c Conversation) scope() string {
+	if c.ParentSHA != "" {
+		return fmt.Sprintf("%d entries since %s", len(c.Entries), util.ShortSHA(c.ParentSHA))
+	}
+	return fmt.Sprintf("%d entries (full session)", len(c.Entries))
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+const exportTranscript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Greet <everyone>"}}
+{"uuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Updating the greeting."},{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/repo/hello.go","old_string":"hello","new_string":"hello, world"}}]}}
+{"uuid":"r1","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"uuid":"a2","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"echo '` + "```" + `'"}}]}}
+{"uuid":"r2","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"boom","is_error":true}]}}
+{"uuid":"a3","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Done."}]}}`
+
+func testConversation(t *testing.T) Conversation {
+	t.Helper()
+	transcript, err := claude.ParseTranscript(strings.NewReader(exportTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	return Conversation{
+		SHA:       "0123456789abcdef0123456789abcdef01234567",
+		Subject:   "Greet everyone",
+		Author:    "Test User",
+		Date:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		SessionID: "session-1",
+		Branch:    "main",
+		ParentSHA: "fedcba9876543210fedcba9876543210fedcba98",
+		Entries:   transcript.Entries,
+	}
+}
+
+func TestSections(t *testing.T) {
+	got := sections(testConversation(t).Entries)
+
+	var roles, kinds []string
+	for _, s := range got {
+		roles = append(roles, s.Role)
+		kinds = append(kinds, s.Kind)
+	}
+	// Tool results stay in the assistant's turn
+	if want := "User,Assistant,,,,,"; strings.Join(roles, ",") != want {
+		t.Errorf("roles = %q, want %q", strings.Join(roles, ","), want)
+	}
+	if want := "text,text,tool,result,tool,result,text"; strings.Join(kinds, ",") != want {
+		t.Errorf("kinds = %q, want %q", strings.Join(kinds, ","), want)
+	}
+
+	edit := got[2]
+	if edit.Title != "Edit /repo/hello.go" {
+		t.Errorf("edit title = %q", edit.Title)
+	}
+	if want := "-hello,+hello, world"; strings.Join(edit.Diff, ",") != want {
+		t.Errorf("edit diff = %q, want %q", strings.Join(edit.Diff, ","), want)
+	}
+	if result := got[5]; !result.Error || result.Title != "Bash error" || result.Text != "boom" {
+		t.Errorf("failed result = %+v", result)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, []Conversation{testConversation(t)}); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	md := buf.String()
+
+	for _, want := range []string{
+		"# Greet everyone\n",
+		"- **Session:** session-1 on main\n",
+		"- **Showing:** 6 entries since fedcba9\n",
+		"## User\n\nGreet <everyone>\n",
+		"<summary>Edit /repo/hello.go</summary>\n\n```diff\n-hello\n+hello, world\n```\n",
+		// Code containing a fence gets a longer one
+		"````sh\necho '```'\n````\n",
+		"<summary>Bash error</summary>",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Count(md, "## Assistant") != 1 {
+		t.Errorf("expected a single assistant turn:\n%s", md)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, []Conversation{testConversation(t)}); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	page := buf.String()
+
+	for _, want := range []string{
+		"<title>Greet everyone</title>",
+		`<div class="text">Greet &lt;everyone&gt;</div>`,
+		`<span class="diff-line removed">-hello</span>`,
+		`<span class="diff-line added">&#43;hello, world</span>`,
+		`<details class="result error">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, JSONL, []Conversation{testConversation(t)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if buf.String() != exportTranscript+"\n" {
+		t.Errorf("jsonl = %q, want the transcript as recorded", buf.String())
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "pdf", nil); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
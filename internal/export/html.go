@@ -0,0 +1,65 @@
+package export
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+//go:embed page.html
+var pageHTML string
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"short": util.ShortSHA,
+	"lower": strings.ToLower,
+	"diffClass": func(line string) string {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			return "added"
+		case strings.HasPrefix(line, "-"):
+			return "removed"
+		}
+		return ""
+	},
+}).Parse(pageHTML))
+
+// htmlConversation is a conversation split into turns for the page template
+type htmlConversation struct {
+	Conversation
+	Scope string
+	Turns []turn
+}
+
+// turn is what one speaker said in a row
+type turn struct {
+	Role     string
+	Sections []section
+}
+
+// WriteHTML renders the conversations as a self-contained HTML page, with
+// tool calls in collapsible sections and diffs highlighted
+func WriteHTML(w io.Writer, convs []Conversation) error {
+	page := struct {
+		Title         string
+		Conversations []htmlConversation
+	}{Title: "Conversation history"}
+	if len(convs) == 1 {
+		page.Title = convs[0].Subject
+	}
+
+	for _, conv := range convs {
+		hc := htmlConversation{Conversation: conv, Scope: conv.scope()}
+		for _, s := range sections(conv.Entries) {
+			if s.Role != "" || len(hc.Turns) == 0 {
+				hc.Turns = append(hc.Turns, turn{Role: s.Role})
+			}
+			t := &hc.Turns[len(hc.Turns)-1]
+			t.Sections = append(t.Sections, s)
+		}
+		page.Conversations = append(page.Conversations, hc)
+	}
+	return pageTemplate.Execute(w, page)
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown renders the conversations as a Markdown document, with tool
+// calls in collapsible <details> sections
+func WriteMarkdown(w io.Writer, convs []Conversation) error {
+	var b strings.Builder
+	for i, conv := range convs {
+		if i > 0 {
+			fmt.Fprint(&b, "\n---\n\n")
+		}
+		fmt.Fprintf(&b, "# %s\n\n", conv.Subject)
+		fmt.Fprintf(&b, "- **Commit:** `%s`\n", conv.SHA)
+		fmt.Fprintf(&b, "- **Author:** %s\n", conv.Author)
+		fmt.Fprintf(&b, "- **Date:** %s\n", conv.Date.Format("2006-01-02 15:04:05 -0700"))
+		session := conv.SessionID
+		if conv.Branch != "" {
+			session += " on " + conv.Branch
+		}
+		fmt.Fprintf(&b, "- **Session:** %s\n", session)
+		fmt.Fprintf(&b, "- **Showing:** %s\n", conv.scope())
+
+		for _, s := range sections(conv.Entries) {
+			if s.Role != "" {
+				fmt.Fprintf(&b, "\n## %s\n", s.Role)
+			}
+			fmt.Fprintln(&b)
+			writeMarkdownSection(&b, s)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownSection(w *strings.Builder, s section) {
+	if s.Kind == kindText {
+		fmt.Fprintln(w, s.Text)
+		return
+	}
+
+	fmt.Fprintf(w, "<details>\n<summary>%s</summary>\n\n", escapeHTML(s.Title))
+	switch {
+	case len(s.Diff) > 0:
+		writeFenced(w, "diff", strings.Join(s.Diff, "\n"))
+	case s.Kind == kindThinking:
+		fmt.Fprintln(w, s.Text)
+	case s.Text != "":
+		writeFenced(w, s.Lang, s.Text)
+	default:
+		fmt.Fprintln(w, "*(empty)*")
+	}
+	fmt.Fprint(w, "\n</details>\n")
+}
+
+// writeFenced writes a code block, with a fence longer than any run of
+// backticks in the code
+func writeFenced(w *strings.Builder, lang, code string) {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(w, "%s%s\n%s\n%s\n", fence, lang, code, fence)
+}
+
+// escapeHTML escapes text for use inside the HTML tags of a Markdown document
+func escapeHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
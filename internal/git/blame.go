@@ -0,0 +1,95 @@
+package git
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// BlameLine is a single line of a file annotated with the commit that last changed it
+type BlameLine struct {
+	CommitSHA string
+	Author    string
+	// AuthorTime is the author date as a Unix timestamp
+	AuthorTime int64
+	Summary    string
+	// LineNumber is the 1-based line number in the blamed revision
+	LineNumber int
+	Text       string
+}
+
+// Blame runs git blame on a file at the given revision (HEAD if empty)
+func Blame(rev, file string) ([]BlameLine, error) {
+	args := []string{"blame", "--porcelain"}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	args = append(args, "--", file)
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(string(output)), nil
+}
+
+// blameCommit holds the per-commit metadata that porcelain output prints only once
+type blameCommit struct {
+	author     string
+	authorTime int64
+	summary    string
+}
+
+// parseBlamePorcelain parses the output of git blame --porcelain
+func parseBlamePorcelain(output string) []BlameLine {
+	var lines []BlameLine
+	commits := make(map[string]*blameCommit)
+
+	var current *BlameLine
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "\t") {
+			if current != nil {
+				current.Text = line[1:]
+				info := commits[current.CommitSHA]
+				current.Author = info.author
+				current.AuthorTime = info.authorTime
+				current.Summary = info.summary
+				lines = append(lines, *current)
+				current = nil
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if current == nil {
+			// Header: "<sha> <orig-line> <final-line> [<group-size>]"
+			if len(fields) < 3 || len(fields[0]) != 40 {
+				continue
+			}
+			finalLine, _ := strconv.Atoi(fields[2])
+			current = &BlameLine{CommitSHA: fields[0], LineNumber: finalLine}
+			if commits[current.CommitSHA] == nil {
+				commits[current.CommitSHA] = &blameCommit{}
+			}
+			continue
+		}
+
+		info := commits[current.CommitSHA]
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			info.author = value
+		case "author-time":
+			info.authorTime, _ = strconv.ParseInt(value, 10, 64)
+		case "summary":
+			info.summary = value
+		}
+	}
+
+	return lines
+}
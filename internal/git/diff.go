@@ -0,0 +1,111 @@
+package git
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DiffHunk is a single hunk of a unified diff
+type DiffHunk struct {
+	File string `json:"file"`
+	// Header is the hunk's "@@ -a,b +c,d @@" line
+	Header   string `json:"header"`
+	OldStart int    `json:"old_start"`
+	NewStart int    `json:"new_start"`
+	// Lines are the hunk's lines, each prefixed with ' ', '+' or '-'
+	Lines []string `json:"lines"`
+}
+
+// Added returns the lines the hunk adds, without their '+' prefix
+func (h DiffHunk) Added() []string {
+	return h.linesWithPrefix('+')
+}
+
+// Removed returns the lines the hunk removes, without their '-' prefix
+func (h DiffHunk) Removed() []string {
+	return h.linesWithPrefix('-')
+}
+
+func (h DiffHunk) linesWithPrefix(prefix byte) []string {
+	var lines []string
+	for _, line := range h.Lines {
+		if len(line) > 0 && line[0] == prefix {
+			lines = append(lines, line[1:])
+		}
+	}
+	return lines
+}
+
+// CommitDiff returns the hunks a commit introduced relative to its first
+// parent (or the empty tree for a root commit)
+func CommitDiff(commitSHA string) ([]DiffHunk, error) {
+	output, err := exec.Command("git", "diff-tree", "-p", "-r", "--root", "--no-commit-id",
+		"--no-color", "--no-ext-diff", "--no-prefix", commitSHA).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseUnifiedDiff(string(output)), nil
+}
+
+// parseUnifiedDiff parses git's unified diff output into hunks
+func parseUnifiedDiff(output string) []DiffHunk {
+	var hunks []DiffHunk
+	var oldFile, newFile string
+	var current *DiffHunk
+
+	flush := func() {
+		if current != nil {
+			hunks = append(hunks, *current)
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			flush()
+			oldFile, newFile = "", ""
+		case current == nil && strings.HasPrefix(line, "--- "):
+			oldFile = strings.TrimPrefix(line, "--- ")
+		case current == nil && strings.HasPrefix(line, "+++ "):
+			newFile = strings.TrimPrefix(line, "+++ ")
+		case strings.HasPrefix(line, "@@ "):
+			flush()
+			file := newFile
+			if file == "/dev/null" {
+				file = oldFile
+			}
+			oldStart, newStart := parseHunkHeader(line)
+			current = &DiffHunk{File: file, Header: line, OldStart: oldStart, NewStart: newStart}
+		case current != nil && len(line) > 0 && strings.ContainsRune(" +-", rune(line[0])):
+			current.Lines = append(current.Lines, line)
+		case current != nil && line == "":
+			// Some tools strip the trailing space of empty context lines
+			current.Lines = append(current.Lines, " ")
+		}
+	}
+	flush()
+
+	return hunks
+}
+
+// parseHunkHeader extracts the start lines from "@@ -a,b +c,d @@"
+func parseHunkHeader(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	return parseRangeStart(fields[1]), parseRangeStart(fields[2])
+}
+
+func parseRangeStart(r string) int {
+	start, _, _ := strings.Cut(r[1:], ",")
+	n, _ := strconv.Atoi(start)
+	return n
+}
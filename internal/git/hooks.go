@@ -15,6 +15,7 @@ const (
 	HookPostMerge    HookType = "post-merge"
 	HookPostCheckout HookType = "post-checkout"
 	HookPostCommit   HookType = "post-commit"
+	HookCommitMsg    HookType = "commit-msg"
 )
 
 // clauditMarker identifies claudit-managed hook sections
@@ -105,3 +106,12 @@ func InstallAllHooks(gitDir string) error {
 
 	return nil
 }
+
+// InstallTrailerHook installs the optional commit-msg hook that adds
+// Claude session trailers to commit messages
+func InstallTrailerHook(gitDir string) error {
+	if err := InstallHook(gitDir, HookCommitMsg, `claudit commit-msg "$1"`); err != nil {
+		return fmt.Errorf("failed to install %s hook: %w", HookCommitMsg, err)
+	}
+	return nil
+}
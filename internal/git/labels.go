@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// LabelsRef is the git notes ref used to store conversation labels. Keeping
+// them apart from NotesRef means labelling never rewrites a conversation.
+const LabelsRef = "refs/notes/claude-labels"
+
+// labelsMergeStrategy merges labels line by line when syncing, so labels
+// added on either side are kept
+const labelsMergeStrategy = "cat_sort_uniq"
+
+// SetLabelsNote replaces the labels note of a commit
+func SetLabelsNote(commitSHA string, content []byte) error {
+	cmd := exec.Command("git", "notes", "--ref", LabelsRef, "add", "-f", "-F", "-", commitSHA)
+	cmd.Stdin = bytes.NewReader(content)
+	return cmd.Run()
+}
+
+// GetLabelsNote returns the labels note of a commit, or nil if it has none
+func GetLabelsNote(commitSHA string) ([]byte, error) {
+	cmd := exec.Command("git", "notes", "--ref", LabelsRef, "show", commitSHA)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// RemoveLabelsNote removes the labels note of a commit, if it has one
+func RemoveLabelsNote(commitSHA string) error {
+	return exec.Command("git", "notes", "--ref", LabelsRef, "remove", "--ignore-missing", commitSHA).Run()
+}
+
+// ListLabelsNotes returns the labels notes of every commit that has one,
+// keyed by commit SHA
+func ListLabelsNotes() (map[string][]byte, error) {
+	output, err := exec.Command("git", "notes", "--ref", LabelsRef, "list").Output()
+	if err != nil {
+		// No labels exist yet - this is not an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Format: "note_sha commit_sha"; read every note in one cat-file
+	var blobs, commits []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if parts := strings.Fields(line); len(parts) >= 2 {
+			blobs = append(blobs, parts[0])
+			commits = append(commits, parts[1])
+		}
+	}
+	if len(blobs) == 0 {
+		return nil, nil
+	}
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(blobs, "\n") + "\n")
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make(map[string][]byte, len(commits))
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for _, commit := range commits {
+		// Each object is "<sha> blob <size>\n<content>\n"
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("could not read labels of %s: %w", commit, err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("could not read labels of %s: %s", commit, strings.TrimSpace(header))
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("could not read labels of %s: %w", commit, err)
+		}
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("could not read labels of %s: %w", commit, err)
+		}
+		notes[commit] = content[:size]
+	}
+	return notes, nil
+}
+
+// PushLabels pushes labels to the remote, if there are any
+func PushLabels(remote string) error {
+	if !refExists(LabelsRef) {
+		return nil
+	}
+	return exec.Command("git", "push", "--no-verify", remote, LabelsRef).Run()
+}
+
+// FetchLabels fetches labels from the remote, if it has any, and merges
+// them into the local ones
+func FetchLabels(remote string) error {
+	// ls-remote exits with 2 when the remote has no labels yet
+	err := exec.Command("git", "ls-remote", "--exit-code", remote, LabelsRef).Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 2 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tracking := "refs/notes/remotes/" + remote + "/claude-labels"
+	if err := exec.Command("git", "fetch", remote, "+"+LabelsRef+":"+tracking).Run(); err != nil {
+		return err
+	}
+	if !refExists(LabelsRef) {
+		return exec.Command("git", "update-ref", LabelsRef, tracking).Run()
+	}
+	return exec.Command("git", "notes", "--ref", LabelsRef, "merge", "--quiet", "--strategy", labelsMergeStrategy, tracking).Run()
+}
+
+// refExists returns true if ref names an object
+func refExists(ref string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", ref).Run() == nil
+}
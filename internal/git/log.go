@@ -0,0 +1,228 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// RevList returns the commit SHAs selected by the given git rev-list arguments,
+// e.g. a revision range such as "main..feature" plus any rev-list options.
+func RevList(args ...string) ([]string, error) {
+	output, err := RunGitCommand(append([]string{"rev-list"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+// CommitMeta is the metadata of a commit as listed by git log
+type CommitMeta struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	// Date is the author date
+	Date    time.Time
+	Subject string
+}
+
+// logFormat separates CommitMeta fields with the ASCII unit separator
+const logFormat = "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%s"
+
+// LogCommits lists the commits selected by the given git log arguments
+// (revision ranges, --author, --since and so on), newest first
+func LogCommits(args ...string) ([]CommitMeta, error) {
+	output, err := RunGitCommand(append([]string{"log", logFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitMetas(output), nil
+}
+
+// LogCommitsOf lists the given commits along with any further git log
+// arguments, e.g. --no-walk. The SHAs are passed on stdin, as there can be
+// more of them than fit on a command line.
+func LogCommitsOf(shas []string, args ...string) ([]CommitMeta, error) {
+	cmd := exec.Command("git", append(append([]string{"log", logFormat}, args...), "--stdin")...)
+	cmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitMetas(strings.TrimSpace(string(output))), nil
+}
+
+// parseCommitMetas parses git log output in logFormat
+func parseCommitMetas(output string) []CommitMeta {
+	var commits []CommitMeta
+	for _, line := range splitLines(output) {
+		if meta, ok := parseCommitMeta(line); ok {
+			commits = append(commits, meta)
+		}
+	}
+	return commits
+}
+
+// parseCommitMeta parses a line of git log output in logFormat
+func parseCommitMeta(line string) (CommitMeta, bool) {
+	fields := strings.SplitN(line, "\x1f", 5)
+	if len(fields) != 5 {
+		return CommitMeta{}, false
+	}
+	date, _ := time.Parse(time.RFC3339, fields[3])
+	return CommitMeta{
+		SHA:         fields[0],
+		Author:      fields[1],
+		AuthorEmail: fields[2],
+		Date:        date,
+		Subject:     fields[4],
+	}, true
+}
+
+// GraphLine is a line of 'git log --graph' output: the graph drawing, and
+// the commit drawn on that line if there is one
+type GraphLine struct {
+	Graph  string
+	Commit *CommitMeta
+}
+
+// LogGraph lists the commits selected by the given git log arguments along
+// with the ASCII graph git draws for them
+func LogGraph(args ...string) ([]GraphLine, error) {
+	// The record separator marks where the graph ends and the commit begins
+	format := "--format=%x1e" + strings.TrimPrefix(logFormat, "--format=")
+	output, err := RunGitCommand(append([]string{"log", "--graph", "--color=never", format}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if output == "" {
+		return nil, nil
+	}
+	var lines []GraphLine
+	for _, line := range strings.Split(output, "\n") {
+		graph, record, found := strings.Cut(line, "\x1e")
+		if !found {
+			lines = append(lines, GraphLine{Graph: strings.TrimRight(line, " ")})
+			continue
+		}
+		meta, ok := parseCommitMeta(record)
+		if !ok {
+			continue
+		}
+		lines = append(lines, GraphLine{Graph: graph, Commit: &meta})
+	}
+	return lines, nil
+}
+
+// IsRevisionRange returns true if the argument names a range of commits
+// (A..B, A...B, ^A B or the ^@/^! suffixes) rather than a single revision.
+func IsRevisionRange(arg string) bool {
+	return strings.Contains(arg, "..") ||
+		strings.HasPrefix(arg, "^") ||
+		strings.HasSuffix(arg, "^@") ||
+		strings.HasSuffix(arg, "^!")
+}
+
+// MergeBase returns the best common ancestor of two commits
+func MergeBase(a, b string) (string, error) {
+	return RunGitCommand("merge-base", a, b)
+}
+
+// IsAncestor returns true if ancestor is reachable from descendant
+func IsAncestor(ancestor, descendant string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	return cmd.Run() == nil
+}
+
+// GetCommitMessage returns the full commit message (subject, body and trailers)
+func GetCommitMessage(commitSHA string) (string, error) {
+	return RunGitCommand("log", "-1", "--format=%B", commitSHA)
+}
+
+// PatchID returns the stable patch ID of the diff between two commits.
+// Two diffs with the same patch ID introduce the same change, regardless of
+// line numbers or how the change was split into commits.
+func PatchID(from, to string) (string, error) {
+	diff, err := exec.Command("git", "diff", "--no-color", "--no-ext-diff", from, to).Output()
+	if err != nil {
+		return "", err
+	}
+	if len(diff) == 0 {
+		return "", nil
+	}
+
+	cmd := exec.Command("git", "patch-id", "--stable")
+	cmd.Stdin = strings.NewReader(string(diff))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	// Format: "<patch-id> <commit-id>"
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+// ListRefs returns the SHAs and names of refs matching the given patterns,
+// as a map from ref name to the commit it points at.
+func ListRefs(patterns ...string) (map[string]string, error) {
+	args := append([]string{"for-each-ref", "--format=%(objectname) %(refname)"}, patterns...)
+	output, err := RunGitCommand(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]string)
+	for _, line := range splitLines(output) {
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) == 2 {
+			refs[parts[1]] = parts[0]
+		}
+	}
+	return refs, nil
+}
+
+// LineChange is the most recent commit to change a line of a file
+type LineChange struct {
+	CommitSHA string
+	// Added holds the lines that commit added within the traced range
+	Added []string
+}
+
+// LastLineChange uses git log -L to find the last commit, reachable from rev
+// (HEAD if empty), that changed the given 1-based line of a file.
+func LastLineChange(rev, file string, line int) (*LineChange, error) {
+	args := []string{"log", "-1", "--format=%H", "--no-color", "-L", fmt.Sprintf("%d,%d:%s", line, line, file)}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(output), "\n")
+	change := &LineChange{CommitSHA: strings.TrimSpace(lines[0])}
+	for _, l := range lines[1:] {
+		if strings.HasPrefix(l, "+") && !strings.HasPrefix(l, "+++") {
+			change.Added = append(change.Added, l[1:])
+		}
+	}
+	return change, nil
+}
+
+// splitLines splits command output into non-empty lines
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -32,38 +32,18 @@ func HasNote(commitSHA string) bool {
 // ListCommitsWithNotes returns a list of commit SHAs that have conversation notes
 // sorted in reverse chronological order (matching git log)
 func ListCommitsWithNotes() ([]string, error) {
-	cmd := exec.Command("git", "notes", "--ref", NotesRef, "list")
-	output, err := cmd.Output()
+	commitSet, err := CommitsWithNotesSet()
 	if err != nil {
-		// No notes exist yet - this is not an error
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return nil, nil
-		}
 		return nil, err
 	}
-
-	// Build a set of commits with notes
-	commitSet := make(map[string]bool)
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		// Format: "note_sha commit_sha"
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			commitSet[parts[1]] = true
-		}
-	}
-
 	if len(commitSet) == 0 {
 		return nil, nil
 	}
 
 	// Use git rev-list to sort commits in reverse chronological order
 	// --all ensures we see all branches, --topo-order maintains parent-child relationships
-	cmd = exec.Command("git", "rev-list", "--all", "--topo-order")
-	output, err = cmd.Output()
+	cmd := exec.Command("git", "rev-list", "--all", "--topo-order")
+	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +62,29 @@ func ListCommitsWithNotes() ([]string, error) {
 	return commits, nil
 }
 
+// CommitsWithNotesSet returns the set of commit SHAs that have conversation notes
+func CommitsWithNotesSet() (map[string]bool, error) {
+	cmd := exec.Command("git", "notes", "--ref", NotesRef, "list")
+	output, err := cmd.Output()
+	if err != nil {
+		// No notes exist yet - this is not an error
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	commitSet := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// Format: "note_sha commit_sha"
+		parts := strings.Fields(line)
+		if len(parts) >= 2 {
+			commitSet[parts[1]] = true
+		}
+	}
+	return commitSet, nil
+}
+
 // PushNotes pushes notes to the remote
 func PushNotes(remote string) error {
 	// Use --no-verify to prevent pre-push hook from triggering recursively
@@ -45,6 +45,14 @@ func GetRepoRoot() (string, error) {
 	return RunGitCommand("rev-parse", "--show-toplevel")
 }
 
+// IsCommitCommand checks if a shell command runs git commit
+func IsCommitCommand(command string) bool {
+	// Simple heuristic: check if command contains "git commit"
+	// This handles: git commit, git commit -m, git commit -am, etc.
+	return strings.Contains(command, "git commit") ||
+		strings.Contains(command, "git-commit")
+}
+
 // GetCurrentBranch returns the name of the current branch
 func GetCurrentBranch() (string, error) {
 	return RunGitCommand("rev-parse", "--abbrev-ref", "HEAD")
@@ -145,3 +153,13 @@ func GetCommitInfo(commitSHA string) (message string, date string, err error) {
 
 	return message, date, nil
 }
+
+// ReadFile returns the content of a file at a revision. Unlike
+// RunGitCommand, the content is returned exactly, whitespace included.
+func ReadFile(rev, path string) (string, error) {
+	output, err := exec.Command("git", "show", rev+":"+path).Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SquashSource describes the feature branch commits that a squash commit
+// was most likely produced from.
+type SquashSource struct {
+	// Range is a rev-list range selecting the original commits, e.g. "base..tip"
+	Range string
+	// Ref is the ref the original commits were found on
+	Ref string
+	// Method is how the source was detected: "pr-number" or "patch-id"
+	Method string
+}
+
+// prSubjectPattern matches the "(#123)" suffix GitHub adds to squash-merge subjects
+var prSubjectPattern = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+
+// prTrailerPattern matches PR trailers such as "PR: #123" or "Pull-Request: .../pull/123"
+var prTrailerPattern = regexp.MustCompile(`(?mi)^(?:PR|Pull-Request):\s*\S*?#?(\d+)\s*$`)
+
+// FindSquashSource attempts to locate the original commits of a squash-merged
+// commit. It first looks for a PR number in the commit message and a matching
+// pull request ref, then falls back to comparing patch IDs against the tips
+// of local and remote-tracking branches that were never merged into it.
+// Returns nil if no source could be found.
+func FindSquashSource(commitSHA string) (*SquashSource, error) {
+	parents, err := GetParentCommits(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	if len(parents) != 1 {
+		// Squash commits have exactly one parent
+		return nil, nil
+	}
+	parent := parents[0]
+
+	message, err := GetCommitMessage(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr := ParsePRNumber(message); pr != "" {
+		source, err := findPRSource(commitSHA, parent, pr)
+		if err != nil {
+			return nil, err
+		}
+		if source != nil {
+			return source, nil
+		}
+	}
+
+	return findPatchIDSource(commitSHA, parent)
+}
+
+// ParsePRNumber extracts a pull request number from a commit message, using
+// either the GitHub squash subject suffix or a PR/Pull-Request trailer.
+func ParsePRNumber(message string) string {
+	subject := strings.SplitN(message, "\n", 2)[0]
+	if m := prSubjectPattern.FindStringSubmatch(subject); m != nil {
+		return m[1]
+	}
+	if m := prTrailerPattern.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// findPRSource looks for a fetched pull request head ref for the given PR number
+func findPRSource(commitSHA, parent, pr string) (*SquashSource, error) {
+	refs, err := ListRefs(
+		"refs/pull/"+pr+"/head",
+		"refs/remotes/*/pull/"+pr+"/head",
+		"refs/remotes/*/pr/"+pr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedRefNames(refs) {
+		tip := refs[name]
+		if tip == commitSHA || IsAncestor(tip, commitSHA) {
+			continue
+		}
+		base, err := MergeBase(parent, tip)
+		if err != nil {
+			continue
+		}
+		return &SquashSource{Range: base + ".." + tip, Ref: name, Method: "pr-number"}, nil
+	}
+	return nil, nil
+}
+
+// findPatchIDSource compares the squash commit's patch ID against each
+// unmerged branch, measured from where that branch forked from the parent.
+func findPatchIDSource(commitSHA, parent string) (*SquashSource, error) {
+	squashID, err := PatchID(parent, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute patch ID: %w", err)
+	}
+	if squashID == "" {
+		return nil, nil
+	}
+
+	refs, err := ListRefs("refs/heads", "refs/remotes", "refs/pull")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedRefNames(refs) {
+		tip := refs[name]
+		if tip == commitSHA || IsAncestor(tip, commitSHA) {
+			continue
+		}
+		base, err := MergeBase(parent, tip)
+		if err != nil || base == tip {
+			continue
+		}
+		id, err := PatchID(base, tip)
+		if err != nil || id != squashID {
+			continue
+		}
+		return &SquashSource{Range: base + ".." + tip, Ref: name, Method: "patch-id"}, nil
+	}
+	return nil, nil
+}
+
+// sortedRefNames returns ref names in a stable order so detection is deterministic
+func sortedRefNames(refs map[string]string) []string {
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
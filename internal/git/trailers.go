@@ -0,0 +1,93 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Trailer keys linking a commit to the Claude session it was made in.
+// Unlike notes, trailers are part of the commit itself, so they are visible
+// to reviewers on hosting platforms and survive rebases that lose notes.
+const (
+	TrailerSession  = "Claude-Session"
+	TrailerChecksum = "Claude-Transcript-Checksum"
+)
+
+// GetTrailers returns the trailers of a commit message as a map of key to value.
+// If a key appears more than once, the last value wins.
+func GetTrailers(commitSHA string) (map[string]string, error) {
+	output, err := RunGitCommand("log", "-1", "--format=%(trailers:only,unfold)", commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	return parseTrailers(output), nil
+}
+
+// ListCommitsWithTrailer returns all commits reachable from any ref whose
+// message carries the given trailer, as a map of commit SHA to trailer value.
+func ListCommitsWithTrailer(key string) (map[string]string, error) {
+	output, err := RunGitCommand("log", "--all", trailerFormat(key))
+	if err != nil {
+		return nil, err
+	}
+	return parseTrailerValues(output), nil
+}
+
+// CommitsWithTrailerOf returns which of the given commits carry the given
+// trailer, as ListCommitsWithTrailer does. The SHAs are passed on stdin.
+func CommitsWithTrailerOf(shas []string, key string) (map[string]string, error) {
+	cmd := exec.Command("git", "log", "--no-walk", trailerFormat(key), "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseTrailerValues(strings.TrimSpace(string(output))), nil
+}
+
+// trailerFormat is the git log format printing a commit's SHA and the
+// values of a trailer
+func trailerFormat(key string) string {
+	return fmt.Sprintf("--format=%%H%%x00%%(trailers:key=%s,valueonly,separator=%%x2C)", key)
+}
+
+// parseTrailerValues parses git log output in trailerFormat, skipping
+// commits without the trailer
+func parseTrailerValues(output string) map[string]string {
+	commits := make(map[string]string)
+	for _, line := range splitLines(output) {
+		parts := strings.SplitN(line, "\x00", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+		// Keep the last value if the trailer was repeated
+		values := strings.Split(parts[1], ",")
+		commits[parts[0]] = strings.TrimSpace(values[len(values)-1])
+	}
+	return commits
+}
+
+// AddTrailers adds "Key: value" trailers to a commit message file in place,
+// replacing any existing trailers with the same key
+func AddTrailers(messageFile string, trailers ...string) error {
+	args := []string{"interpret-trailers", "--in-place", "--if-exists", "replace"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer)
+	}
+	args = append(args, messageFile)
+	return exec.Command("git", args...).Run()
+}
+
+// parseTrailers parses "Key: value" lines as printed by %(trailers)
+func parseTrailers(output string) map[string]string {
+	trailers := make(map[string]string)
+	for _, line := range splitLines(output) {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		trailers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return trailers
+}
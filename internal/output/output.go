@@ -0,0 +1,194 @@
+// Package output writes command results for scripts: as JSON, JSON Lines,
+// YAML or a Go template, all following the schemas in schema.go.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats accepted by --format
+const (
+	Text     = "text"
+	JSON     = "json"
+	JSONL    = "jsonl"
+	YAML     = "yaml"
+	Template = "template"
+)
+
+// Formats lists the accepted formats, in the order they are documented
+var Formats = []string{Text, JSON, JSONL, YAML, Template}
+
+// Writer writes results in the format selected with --format. Results are
+// encoded through their JSON representation, so every format uses the same
+// field names.
+type Writer struct {
+	out      io.Writer
+	format   string
+	template *template.Template
+}
+
+// New returns a writer for a format. tmpl is required for the template
+// format and is parsed as a text/template executed once per record.
+func New(out io.Writer, format, tmpl string) (*Writer, error) {
+	w := &Writer{out: out, format: format}
+	switch format {
+	case Text, JSON, JSONL, YAML:
+		if tmpl != "" {
+			return nil, fmt.Errorf("--template requires --format template")
+		}
+	case Template:
+		if tmpl == "" {
+			return nil, fmt.Errorf("--format template requires --template")
+		}
+		t, err := template.New("format").Funcs(templateFuncs).Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		w.template = t
+	default:
+		return nil, fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(Formats, ", "))
+	}
+	return w, nil
+}
+
+// Format returns the selected format
+func (w *Writer) Format() string {
+	return w.format
+}
+
+// IsText returns true if results should be printed for people rather than
+// encoded
+func (w *Writer) IsText() bool {
+	return w.format == Text
+}
+
+// Write encodes a result. If v is a slice, JSON Lines and template output
+// write one line per element; JSON and YAML write a single document.
+func (w *Writer) Write(v interface{}) error {
+	switch w.format {
+	case JSON:
+		encoder := json.NewEncoder(w.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case JSONL:
+		encoder := json.NewEncoder(w.out)
+		for _, record := range records(v) {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case YAML:
+		return writeYAML(w.out, v)
+	case Template:
+		for _, record := range records(v) {
+			data, err := toTemplateData(record)
+			if err != nil {
+				return err
+			}
+			if err := w.template.Execute(w.out, data); err != nil {
+				return fmt.Errorf("could not execute template: %w", err)
+			}
+			if _, err := fmt.Fprintln(w.out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("format %q does not encode results", w.format)
+}
+
+// records splits a slice into its elements, or returns v as the only record
+func records(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result
+}
+
+// writeYAML encodes v as YAML with the same keys, in the same order, as its
+// JSON encoding
+func writeYAML(out io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so decoding it gives a node tree in field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle switches a node tree decoded from JSON from flow style to the
+// usual block style
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		// Let the encoder decide whether strings need quotes
+		node.Style &^= yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// toTemplateData converts a record to the maps and values of its JSON
+// encoding, so templates use the documented field names, e.g. {{.sha}}
+func toTemplateData(record interface{}) (interface{}, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// templateFuncs are the functions available to --template
+var templateFuncs = template.FuncMap{
+	// short abbreviates a SHA or session ID to 7 characters
+	"short": func(s string) string {
+		if len(s) > 7 {
+			return s[:7]
+		}
+		return s
+	},
+	// json encodes a value as compact JSON
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// trunc truncates a string to n characters
+	"trunc": func(n int, s string) string {
+		if len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+}
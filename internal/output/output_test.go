@@ -0,0 +1,86 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	SHA   string `json:"sha"`
+	Count int    `json:"message_count"`
+	Label string `json:"label,omitempty"`
+}
+
+var testRecords = []record{
+	{SHA: "abc1234567", Count: 42, Label: "true"},
+	{SHA: "def4567890", Count: 7},
+}
+
+func write(t *testing.T, format, tmpl string, v interface{}) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := New(&buf, format, tmpl)
+	if err != nil {
+		t.Fatalf("New(%q) failed: %v", format, err)
+	}
+	if err := w.Write(v); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriteJSONL(t *testing.T) {
+	got := write(t, JSONL, "", testRecords)
+	want := `{"sha":"abc1234567","message_count":42,"label":"true"}
+{"sha":"def4567890","message_count":7}
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	got := write(t, YAML, "", testRecords)
+	// Keys keep their JSON names and order; strings that look like other
+	// types stay quoted
+	want := `- sha: abc1234567
+  message_count: 42
+  label: "true"
+- sha: def4567890
+  message_count: 7
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	got := write(t, Template, "{{short .sha}} ({{.message_count}} messages)", testRecords)
+	want := "abc1234 (42 messages)\ndef4567 (7 messages)\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = write(t, Template, "{{.sha}}", testRecords[0])
+	if got != "abc1234567\n" {
+		t.Errorf("single record: got %q", got)
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		format, tmpl, wantErr string
+	}{
+		{"xml", "", "unknown format"},
+		{Template, "", "requires --template"},
+		{JSON, "{{.sha}}", "requires --format template"},
+		{Template, "{{.sha", "invalid template"},
+	}
+	for _, tc := range tests {
+		_, err := New(&bytes.Buffer{}, tc.format, tc.tmpl)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("New(%q, %q) error = %v, want %q", tc.format, tc.tmpl, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package output
+
+import (
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+)
+
+// The types below are the records written by --format. Their JSON field
+// names are a stable interface for scripts: fields may be added, but are not
+// renamed or removed.
+
+// Checksum statuses of a stored transcript
+const (
+	// ChecksumValid means the transcript matches the checksum stored with it
+	ChecksumValid = "valid"
+	// ChecksumInvalid means the transcript does not match its checksum
+	ChecksumInvalid = "invalid"
+	// ChecksumUnverified means the transcript was recovered through the
+	// commit's trailers without matching its Claude-Transcript-Checksum
+	ChecksumUnverified = "unverified"
+	// ChecksumMissing means the commit has a Claude-Session trailer but no note
+	ChecksumMissing = "missing"
+	// ChecksumNone means the commit has no conversation at all
+	ChecksumNone = "none"
+)
+
+// Commit is a commit with a conversation, one per line of 'claudit list'
+type Commit struct {
+	SHA string `json:"sha"`
+	// Date is the author date in RFC 3339 format
+	Date        string `json:"date"`
+	Author      string `json:"author"`
+	AuthorEmail string `json:"author_email"`
+	// Message is the subject line of the commit message
+	Message   string `json:"message"`
+	SessionID string `json:"session_id"`
+	Branch    string `json:"branch,omitempty"`
+	// Provider is the assistant the conversation was with, e.g. claude
+	Provider string `json:"provider,omitempty"`
+	// MessageCount is the number of messages in the whole session when the
+	// commit was made
+	MessageCount int `json:"message_count"`
+	// Checksum is one of valid, invalid, unverified, missing or none
+	Checksum string `json:"checksum"`
+	// Aggregate is true if the conversation combines those of SourceCommits
+	Aggregate bool `json:"aggregate,omitempty"`
+	// Summary is the one-line summary stored with the conversation
+	Summary string `json:"summary,omitempty"`
+	// Labels are the labels given with 'claudit tag'
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Conversation is a commit's conversation, as printed by 'claudit show'
+type Conversation struct {
+	Commit
+	// Incremental is true if Entries only holds the entries since the
+	// conversation of ParentSHA, the previous commit in the same session
+	Incremental bool                     `json:"incremental"`
+	ParentSHA   string                   `json:"parent_sha,omitempty"`
+	Entries     []claude.TranscriptEntry `json:"entries"`
+	// SourceCommits lists the original commits of an aggregate conversation
+	SourceCommits []storage.SourceCommit `json:"source_commits,omitempty"`
+	// Diff is set with --diff
+	Diff *attribution.DiffAnnotation `json:"diff,omitempty"`
+	// Timeline is set with --timeline
+	Timeline *claude.Timeline `json:"timeline,omitempty"`
+	// Branches holds the abandoned branches with --tree
+	Branches []claude.Branch `json:"branches,omitempty"`
+}
+
+// ConversationDiff compares the conversations of two commits, as printed by
+// 'claudit diff-conversation'
+type ConversationDiff struct {
+	CommitA Commit `json:"commit_a"`
+	CommitB Commit `json:"commit_b"`
+	claude.Divergence
+}
+
+// LogEntry is a commit in 'claudit log', with a digest of its conversation
+type LogEntry struct {
+	Commit
+	// ParentSHA is the previous commit of the same session; the digest only
+	// covers the conversation since then
+	ParentSHA string `json:"parent_sha,omitempty"`
+	// Digest is set for commits with a conversation
+	Digest *claude.Digest `json:"digest,omitempty"`
+}
+
+// Origin is the exchange that last changed a line, as printed by 'claudit why'
+type Origin struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Commit
+	// Tool is the tool call that wrote the line, e.g. Edit
+	Tool      string `json:"tool"`
+	ToolUseID string `json:"tool_use_id"`
+	// Entries run from the user message that led to the change through the
+	// result of the tool call
+	Entries []claude.TranscriptEntry `json:"entries"`
+}
+
+// Check statuses of 'claudit doctor'
+const (
+	CheckOK   = "ok"
+	CheckFail = "fail"
+	CheckSkip = "skip"
+)
+
+// Check is the result of one 'claudit doctor' check
+type Check struct {
+	Name string `json:"name"`
+	// Status is one of ok, fail or skip
+	Status string `json:"status"`
+	// Messages describe what was found, or how to fix a failure
+	Messages []string `json:"messages,omitempty"`
+	// Warnings are problems that don't fail the check
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Diagnosis is the report of 'claudit doctor'
+type Diagnosis struct {
+	// OK is true if no check failed
+	OK     bool    `json:"ok"`
+	Checks []Check `json:"checks"`
+}
+
+// Sync is the result of 'claudit sync push' or 'claudit sync pull'
+type Sync struct {
+	// Action is push or pull
+	Action string `json:"action"`
+	Remote string `json:"remote"`
+	OK     bool   `json:"ok"`
+	// Error explains why notes could not be synced
+	Error string `json:"error,omitempty"`
+}
+
+// Import is a commit whose conversation 'claudit import' found in a Claude
+// session file
+type Import struct {
+	Commit
+	// Match is how the commit was matched to the session: "commit output"
+	// or "timestamp"
+	Match       string `json:"match"`
+	SessionFile string `json:"session_file"`
+	// Imported is false for a dry run
+	Imported bool `json:"imported"`
+}
+
+// Labels are the labels of a commit's conversation, as printed by
+// 'claudit tag' and 'claudit untag'
+type Labels struct {
+	SHA    string   `json:"sha"`
+	Labels []string `json:"labels"`
+}
@@ -0,0 +1,104 @@
+// Package pager pages long output the way git does: through core.pager or
+// $PAGER when writing to a terminal.
+package pager
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// defaultPager is used when neither git nor the environment names one
+const defaultPager = "less"
+
+// Width returns the width of the terminal on stdout, from $COLUMNS if the
+// terminal can't say, or 0 if stdout is not a terminal
+func Width() int {
+	width, ok := terminalWidth(os.Stdout)
+	if !ok {
+		return 0
+	}
+	if width > 0 {
+		return width
+	}
+	columns, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return columns
+}
+
+// Command returns the pager git would use: $GIT_PAGER, core.pager, $PAGER
+// or less. An empty command or "cat" means not to page.
+func Command() string {
+	output, err := exec.Command("git", "var", "GIT_PAGER").Output()
+	if err != nil {
+		if pager, ok := os.LookupEnv("PAGER"); ok {
+			return pager
+		}
+		return defaultPager
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Enabled returns true if Start would page: stdout is a terminal and a
+// pager is configured
+func Enabled() bool {
+	_, ok := pagerCommand()
+	return ok
+}
+
+// pagerCommand returns the pager to use, if output should be paged
+func pagerCommand() (string, bool) {
+	if _, ok := terminalWidth(os.Stdout); !ok {
+		return "", false
+	}
+	command := Command()
+	return command, command != "" && command != "cat"
+}
+
+// Start sends what is written to os.Stdout to the pager until the returned
+// function is called, which waits for the user to quit the pager. Output is
+// left alone unless stdout is a terminal and a pager is configured.
+func Start() (stop func()) {
+	stop = func() {}
+	command, ok := pagerCommand()
+	if !ok {
+		return stop
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return stop
+	}
+	pager := exec.Command("sh", "-c", command)
+	pager.Stdin = reader
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	pager.Env = pagerEnv()
+	if err := pager.Start(); err != nil {
+		_ = reader.Close()
+		_ = writer.Close()
+		return stop
+	}
+	_ = reader.Close()
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	return func() {
+		os.Stdout = stdout
+		_ = writer.Close()
+		_ = pager.Wait()
+	}
+}
+
+// pagerEnv sets the defaults git gives less and lv: quit if the output fits
+// on one screen, keep colors, and don't clear the screen on exit
+func pagerEnv() []string {
+	env := os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		env = append(env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		env = append(env, "LV=-c")
+	}
+	return env
+}
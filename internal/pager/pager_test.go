@@ -0,0 +1,44 @@
+package pager
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	t.Setenv("GIT_PAGER", "")
+	os.Unsetenv("GIT_PAGER")
+	t.Setenv("PAGER", "most")
+	// Keep git from reading core.pager from the user's config
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(t.TempDir())
+
+	if got := Command(); got != "most" {
+		t.Errorf("Command() = %q, want $PAGER", got)
+	}
+
+	t.Setenv("GIT_PAGER", "cat")
+	if got := Command(); got != "cat" {
+		t.Errorf("Command() = %q, want $GIT_PAGER", got)
+	}
+}
+
+func TestStartWithoutTerminal(t *testing.T) {
+	stdout := os.Stdout
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+
+	stop := Start()
+	if os.Stdout != f {
+		t.Errorf("output to a file should not be paged")
+	}
+	stop()
+	if Width() != 0 {
+		t.Errorf("Width() = %d for a file, want 0", Width())
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package pager
+
+import "os"
+
+// terminalWidth can't ask the terminal for its width, so only says whether f
+// looks like one
+func terminalWidth(f *os.File) (int, bool) {
+	info, err := f.Stat()
+	return 0, err == nil && info.Mode()&os.ModeCharDevice != 0
+}
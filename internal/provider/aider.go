@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// aiderProvider reads Aider's chat history (.aider.chat.history.md in the
+// repository), which appends every session to the same Markdown file:
+//
+//	# aider chat started at 2025-05-01 10:00:00
+//	#### the user's message
+//	the assistant's reply
+//	> output of aider itself, e.g. applied edits and commits
+type aiderProvider struct{}
+
+func (aiderProvider) Name() string { return Aider }
+
+var aiderStartPattern = regexp.MustCompile(`(?m)^# aider chat started at (.+)$`)
+
+func (aiderProvider) Detect(data []byte) bool {
+	return aiderStartPattern.Match(data)
+}
+
+// Parse turns user messages, replies and aider's output into user, assistant
+// and system entries. Entries are timestamped with the start of their session,
+// the only time the history records.
+func (aiderProvider) Parse(data []byte) (*claude.Transcript, error) {
+	b := &builder{prefix: Aider}
+
+	var (
+		kind      claude.MessageType
+		lines     []string
+		timestamp string
+		inFence   bool
+	)
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(lines, "\n")); text != "" && kind != "" {
+			b.add(kind, timestamp, textBlock(text))
+		}
+		lines = nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		// Replies quote code and edits in fences, whose lines are kept as is
+		if kind == claude.MessageTypeAssistant && inFence {
+			if strings.HasPrefix(line, "```") {
+				inFence = false
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		var lineKind claude.MessageType
+		switch {
+		case aiderStartPattern.MatchString(line):
+			flush()
+			kind = ""
+			timestamp = ""
+			started := aiderStartPattern.FindStringSubmatch(line)[1]
+			if t, err := time.ParseInLocation("2006-01-02 15:04:05", started, time.Local); err == nil {
+				timestamp = t.Format(time.RFC3339)
+			}
+			continue
+		case strings.HasPrefix(line, "####"):
+			lineKind = claude.MessageTypeUser
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "####"), " ")
+		case strings.HasPrefix(line, ">"):
+			lineKind = claude.MessageTypeSystem
+			line = strings.TrimPrefix(strings.TrimPrefix(line, ">"), " ")
+		case strings.TrimSpace(line) == "":
+			// Blank lines belong to whatever they are in
+			lines = append(lines, line)
+			continue
+		default:
+			lineKind = claude.MessageTypeAssistant
+			inFence = strings.HasPrefix(line, "```")
+		}
+
+		if lineKind != kind {
+			flush()
+			kind = lineKind
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return b.transcript(), nil
+}
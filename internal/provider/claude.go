@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// claudeProvider reads Claude Code session files, whose entries need no
+// normalization
+type claudeProvider struct{}
+
+func (claudeProvider) Name() string { return Claude }
+
+// Detect looks for a JSONL line with an entry UUID
+func (claudeProvider) Detect(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry struct {
+			UUID string `json:"uuid"`
+		}
+		if json.Unmarshal(line, &entry) != nil {
+			return false
+		}
+		if entry.UUID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (claudeProvider) Parse(data []byte) (*claude.Transcript, error) {
+	return claude.ParseTranscript(bytes.NewReader(data))
+}
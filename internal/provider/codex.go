@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// codexProvider reads Codex CLI session rollouts
+// (~/.codex/sessions/YYYY/MM/DD/rollout-*.jsonl)
+type codexProvider struct{}
+
+func (codexProvider) Name() string { return Codex }
+
+// codexLine is a line of a rollout. Current versions wrap each item in a
+// payload; older ones wrote the items themselves after a line of metadata.
+type codexLine struct {
+	Timestamp    string          `json:"timestamp"`
+	Type         string          `json:"type"`
+	Payload      json.RawMessage `json:"payload"`
+	Instructions json.RawMessage `json:"instructions"`
+}
+
+// codexItem is a conversation item of the OpenAI Responses API
+type codexItem struct {
+	Type    string `json:"type"`
+	Role    string `json:"role"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Summary []struct {
+		Text string `json:"text"`
+	} `json:"summary"`
+	Name      string          `json:"name"`
+	Arguments string          `json:"arguments"`
+	Input     string          `json:"input"`
+	CallID    string          `json:"call_id"`
+	Output    json.RawMessage `json:"output"`
+}
+
+// Detect looks at the first line for a payload or the older metadata
+func (codexProvider) Detect(data []byte) bool {
+	line, _, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	var first codexLine
+	if json.Unmarshal(line, &first) != nil {
+		return false
+	}
+	return len(first.Payload) > 0 || len(first.Instructions) > 0
+}
+
+func (codexProvider) Parse(data []byte) (*claude.Transcript, error) {
+	b := &builder{prefix: Codex}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		var line codexLine
+		if len(raw) == 0 || json.Unmarshal(raw, &line) != nil {
+			continue
+		}
+
+		var item codexItem
+		switch {
+		case line.Type == "response_item":
+			if json.Unmarshal(line.Payload, &item) != nil {
+				continue
+			}
+		case len(line.Payload) == 0:
+			// Older rollouts; metadata and state lines have no known type
+			if json.Unmarshal(raw, &item) != nil {
+				continue
+			}
+		default:
+			// Session metadata, events and turn context
+			continue
+		}
+
+		switch item.Type {
+		case "message":
+			var blocks []claude.ContentBlock
+			for _, part := range item.Content {
+				if part.Text != "" && !isCodexContext(part.Text) {
+					blocks = append(blocks, textBlock(part.Text))
+				}
+			}
+			switch item.Role {
+			case "user":
+				b.add(claude.MessageTypeUser, line.Timestamp, blocks...)
+			case "assistant":
+				b.add(claude.MessageTypeAssistant, line.Timestamp, blocks...)
+			}
+		case "reasoning":
+			var blocks []claude.ContentBlock
+			for _, summary := range item.Summary {
+				if summary.Text != "" {
+					blocks = append(blocks, thinkingBlock(summary.Text))
+				}
+			}
+			b.add(claude.MessageTypeAssistant, line.Timestamp, blocks...)
+		case "function_call":
+			b.add(claude.MessageTypeAssistant, line.Timestamp, codexToolUse(item))
+		case "custom_tool_call":
+			b.add(claude.MessageTypeAssistant, line.Timestamp, toolUseBlock(item.CallID, item.Name, "input", item.Input))
+		case "function_call_output", "custom_tool_call_output":
+			output, isError := codexOutput(item.Output)
+			b.add(claude.MessageTypeUser, line.Timestamp, toolResultBlock(item.CallID, output, isError))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b.transcript(), nil
+}
+
+// isCodexContext returns true for the environment and instructions Codex
+// sends as user messages
+func isCodexContext(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(text, "<environment_context>") || strings.HasPrefix(text, "<user_instructions>")
+}
+
+// codexToolUse normalizes a function call, turning shell commands into Bash
+// calls
+func codexToolUse(item codexItem) claude.ContentBlock {
+	switch item.Name {
+	case "shell", "shell_command", "exec_command":
+		var args struct {
+			Command json.RawMessage `json:"command"`
+		}
+		if json.Unmarshal([]byte(item.Arguments), &args) != nil {
+			break
+		}
+		var command string
+		var argv []string
+		if json.Unmarshal(args.Command, &argv) == nil {
+			// ["bash", "-lc", "<script>"] runs the script
+			if len(argv) == 3 && (argv[1] == "-lc" || argv[1] == "-c") {
+				command = argv[2]
+			} else {
+				command = strings.Join(argv, " ")
+			}
+		} else if json.Unmarshal(args.Command, &command) != nil {
+			break
+		}
+		input, _ := json.Marshal(map[string]string{"command": command})
+		return toolUseBlock(item.CallID, "Bash", "command", string(input))
+	}
+	return toolUseBlock(item.CallID, item.Name, "arguments", item.Arguments)
+}
+
+// codexOutput returns the text of a tool call's output and whether it failed.
+// Shell output is a JSON string holding the output and its exit code.
+func codexOutput(raw json.RawMessage) (string, bool) {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		var shell struct {
+			Output   *string `json:"output"`
+			Metadata struct {
+				ExitCode int `json:"exit_code"`
+			} `json:"metadata"`
+		}
+		if json.Unmarshal([]byte(text), &shell) == nil && shell.Output != nil {
+			return *shell.Output, shell.Metadata.ExitCode != 0
+		}
+		return text, false
+	}
+
+	var result struct {
+		Content string `json:"content"`
+		Success *bool  `json:"success"`
+	}
+	if json.Unmarshal(raw, &result) == nil {
+		return result.Content, result.Success != nil && !*result.Success
+	}
+	return string(raw), false
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// continueProvider reads Continue session files
+// (~/.continue/sessions/<session-id>.json)
+type continueProvider struct{}
+
+func (continueProvider) Name() string { return Continue }
+
+// continueSession is a Continue session file
+type continueSession struct {
+	History []struct {
+		Message struct {
+			Role string `json:"role"`
+			// Content is a string or a list of parts
+			Content   json.RawMessage `json:"content"`
+			ToolCalls []struct {
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"toolCalls"`
+			ToolCallID string `json:"toolCallId"`
+		} `json:"message"`
+	} `json:"history"`
+}
+
+// Detect looks for a JSON object with a chat history
+func (continueProvider) Detect(data []byte) bool {
+	var session struct {
+		History json.RawMessage `json:"history"`
+	}
+	return json.Unmarshal(data, &session) == nil && len(session.History) > 0
+}
+
+func (continueProvider) Parse(data []byte) (*claude.Transcript, error) {
+	var session continueSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("could not parse Continue session: %w", err)
+	}
+
+	b := &builder{prefix: Continue}
+	for _, item := range session.History {
+		message := item.Message
+		text := continueText(message.Content)
+
+		switch message.Role {
+		case "user":
+			if text != "" {
+				b.add(claude.MessageTypeUser, "", textBlock(text))
+			}
+		case "assistant":
+			var blocks []claude.ContentBlock
+			if text != "" {
+				blocks = append(blocks, textBlock(text))
+			}
+			for _, call := range message.ToolCalls {
+				blocks = append(blocks, toolUseBlock(call.ID, call.Function.Name, "arguments", call.Function.Arguments))
+			}
+			b.add(claude.MessageTypeAssistant, "", blocks...)
+		case "thinking":
+			if text != "" {
+				b.add(claude.MessageTypeAssistant, "", thinkingBlock(text))
+			}
+		case "tool":
+			b.add(claude.MessageTypeUser, "", toolResultBlock(message.ToolCallID, text, false))
+		}
+	}
+	return b.transcript(), nil
+}
+
+// continueText returns the text of message content, which is a string or a
+// list of parts
+func continueText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &parts) != nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range parts {
+		if part.Type == "text" && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n\n"))
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// cursorProvider reads chats exported from Cursor as Markdown, where each
+// message follows a line naming its speaker and messages are separated by
+// rules:
+//
+//	**User**
+//
+//	the user's message
+//
+//	---
+//
+//	**Cursor**
+//
+//	the assistant's reply
+type cursorProvider struct{}
+
+func (cursorProvider) Name() string { return Cursor }
+
+// cursorSpeakerPattern matches the speaker lines of Cursor's own exports
+// and of those made with the SpecStory extension, e.g. _**Assistant**_
+var cursorSpeakerPattern = regexp.MustCompile(`(?m)^_?\*\*(User|Cursor|Assistant)(?: \([^)]*\))?\*\*_?[ \t\r]*$`)
+
+func (cursorProvider) Detect(data []byte) bool {
+	for _, m := range cursorSpeakerPattern.FindAllSubmatch(data, -1) {
+		if string(m[1]) == "User" {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse turns each message into a text entry; the title and export details
+// before the first message are left out
+func (cursorProvider) Parse(data []byte) (*claude.Transcript, error) {
+	b := &builder{prefix: Cursor}
+
+	var (
+		kind  claude.MessageType
+		lines []string
+	)
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(lines, "\n"))
+		text = strings.TrimSpace(strings.TrimSuffix(text, "---"))
+		if text != "" && kind != "" {
+			b.add(kind, "", textBlock(text))
+		}
+		lines = nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := cursorSpeakerPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			kind = claude.MessageTypeAssistant
+			if m[1] == "User" {
+				kind = claude.MessageTypeUser
+			}
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	flush()
+
+	return b.transcript(), nil
+}
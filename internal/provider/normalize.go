@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// builder collects the normalized entries of a transcript
+type builder struct {
+	// prefix starts the UUIDs of the entries
+	prefix string
+	// session is a hash of the first entry, which tells the entries of
+	// different sessions apart
+	session string
+	entries []claude.TranscriptEntry
+}
+
+// add appends an entry made of the given blocks. Entries are numbered in
+// order after a hash of the first one, so a transcript that grows keeps the
+// UUIDs of its earlier entries and later commits can show only what is new,
+// while other sessions of the same provider get UUIDs of their own.
+func (b *builder) add(entryType claude.MessageType, timestamp string, blocks ...claude.ContentBlock) {
+	if len(blocks) == 0 {
+		return
+	}
+	content, err := json.Marshal(blocks)
+	if err != nil {
+		return
+	}
+
+	if len(b.entries) == 0 {
+		sum := sha256.Sum256(append([]byte(string(entryType)+"\x00"+timestamp+"\x00"), content...))
+		b.session = fmt.Sprintf("%x", sum[:6])
+	}
+
+	entry := claude.TranscriptEntry{
+		UUID:      fmt.Sprintf("%s-%s-%d", b.prefix, b.session, len(b.entries)+1),
+		Type:      entryType,
+		Timestamp: timestamp,
+		Message: &claude.Message{
+			Role:       string(entryType),
+			Content:    blocks,
+			RawContent: content,
+		},
+	}
+	if n := len(b.entries); n > 0 {
+		entry.ParentUUID = b.entries[n-1].UUID
+	}
+	// The normalized entry stands in for the original line, e.g. in exports
+	if entry.Raw, err = json.Marshal(entry); err != nil {
+		return
+	}
+	b.entries = append(b.entries, entry)
+}
+
+// transcript returns the entries added so far
+func (b *builder) transcript() *claude.Transcript {
+	return &claude.Transcript{Entries: b.entries}
+}
+
+// textBlock is a block of plain text
+func textBlock(text string) claude.ContentBlock {
+	return claude.ContentBlock{Type: "text", Text: text}
+}
+
+// thinkingBlock is a block of the assistant's reasoning
+func thinkingBlock(text string) claude.ContentBlock {
+	return claude.ContentBlock{Type: "thinking", Thinking: text}
+}
+
+// toolUseBlock is a tool call. Arguments that are not a JSON object are kept
+// under the given key.
+func toolUseBlock(id, name, key, arguments string) claude.ContentBlock {
+	input := json.RawMessage(arguments)
+	var object map[string]json.RawMessage
+	if json.Unmarshal(input, &object) != nil {
+		input, _ = json.Marshal(map[string]string{key: arguments})
+	}
+	return claude.ContentBlock{Type: "tool_use", ID: id, Name: name, Input: input}
+}
+
+// toolResultBlock is the output of a tool call
+func toolResultBlock(id, output string, isError bool) claude.ContentBlock {
+	content, _ := json.Marshal(output)
+	return claude.ContentBlock{Type: "tool_result", ToolUseID: id, Content: content, IsError: isError}
+}
@@ -0,0 +1,85 @@
+// Package provider reads the transcripts of AI coding assistants and
+// normalizes them into the entry model the rest of claudit works with: user
+// and assistant messages made of text, thinking, tool_use and tool_result
+// blocks, as in Claude Code transcripts.
+//
+// Notes keep each transcript in its provider's own format; it is normalized
+// whenever it is read, so parsing improvements apply to existing notes.
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// Names of the supported providers, as recorded in stored conversations
+const (
+	Claude   = "claude"
+	Aider    = "aider"
+	Codex    = "codex"
+	Cursor   = "cursor"
+	Continue = "continue"
+)
+
+// Provider reads the transcripts of one assistant
+type Provider interface {
+	// Name is the name recorded in stored conversations
+	Name() string
+	// Detect returns true if data looks like a transcript of this provider
+	Detect(data []byte) bool
+	// Parse normalizes a transcript into transcript entries
+	Parse(data []byte) (*claude.Transcript, error)
+}
+
+// providers are in detection order, the most distinctive formats first
+var providers = []Provider{
+	continueProvider{},
+	codexProvider{},
+	claudeProvider{},
+	aiderProvider{},
+	cursorProvider{},
+}
+
+// Names returns the names of the supported providers
+func Names() []string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Get returns the named provider. An empty name is Claude, the provider of
+// conversations stored before providers were recorded.
+func Get(name string) (Provider, error) {
+	if name == "" {
+		name = Claude
+	}
+	for _, p := range providers {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown provider %q (supported: %s)", name, strings.Join(Names(), ", "))
+}
+
+// Detect returns the provider whose format data is in
+func Detect(data []byte) (Provider, error) {
+	for _, p := range providers {
+		if p.Detect(data) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("could not detect the transcript format (supported: %s)", strings.Join(Names(), ", "))
+}
+
+// Parse normalizes a transcript of the named provider
+func Parse(name string, data []byte) (*claude.Transcript, error) {
+	p, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(data)
+}
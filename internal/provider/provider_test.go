@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+const claudeTranscript = `{"type":"summary","summary":"Parser"}
+{"uuid":"u1","type":"user","message":{"role":"user","content":"Add a parser"}}
+{"uuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Done."}]}}
+`
+
+const aiderHistory = "# aider chat started at 2025-05-01 10:00:00\n" +
+	"\n" +
+	"> Aider v0.82.0\n" +
+	"> Model: sonnet\n" +
+	"\n" +
+	"#### add a hello function\n" +
+	"#### that greets the world\n" +
+	"\n" +
+	"Here is the change:\n" +
+	"\n" +
+	"hello.py\n" +
+	"```python\n" +
+	"<<<<<<< SEARCH\n" +
+	"=======\n" +
+	"def hello():\n" +
+	"    print('hello, world')\n" +
+	">>>>>>> REPLACE\n" +
+	"```\n" +
+	"\n" +
+	"> Applied edit to hello.py\n" +
+	"> Commit 1a2b3c4 feat: Add hello function\n" +
+	"\n" +
+	"# aider chat started at 2025-05-02 09:30:00\n" +
+	"\n" +
+	"#### /exit\n"
+
+const codexRollout = `{"timestamp":"2025-05-01T10:00:00Z","type":"session_meta","payload":{"id":"5f0c2a1e","cwd":"/repo"}}
+{"timestamp":"2025-05-01T10:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context>\n  <cwd>/repo</cwd>\n</environment_context>"}]}}
+{"timestamp":"2025-05-01T10:00:02Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"Run the tests"}]}}
+{"timestamp":"2025-05-01T10:00:03Z","type":"response_item","payload":{"type":"reasoning","summary":[{"type":"summary_text","text":"Running go test"}]}}
+{"timestamp":"2025-05-01T10:00:04Z","type":"response_item","payload":{"type":"function_call","name":"shell","arguments":"{\"command\":[\"bash\",\"-lc\",\"go test ./...\"]}","call_id":"call_1"}}
+{"timestamp":"2025-05-01T10:00:05Z","type":"response_item","payload":{"type":"function_call_output","call_id":"call_1","output":"{\"output\":\"FAIL\",\"metadata\":{\"exit_code\":1}}"}}
+{"timestamp":"2025-05-01T10:00:06Z","type":"event_msg","payload":{"type":"agent_message","message":"The tests fail."}}
+{"timestamp":"2025-05-01T10:00:06Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"The tests fail."}]}}
+`
+
+const cursorExport = `# Parser refactor
+_Exported on 5/1/2025 at 10:00:00 GMT+1 from Cursor (1.0.0)_
+
+---
+
+**User**
+
+Split the parser
+
+---
+
+**Cursor**
+
+I split it into two files:
+
+---
+
+- lexer.go
+- parser.go
+`
+
+const continueFile = `{
+  "sessionId": "9d47b6e0",
+  "title": "Parser",
+  "workspaceDirectory": "file:///repo",
+  "history": [
+    {"message": {"role": "user", "content": [{"type": "text", "text": "Read the parser"}]}, "contextItems": []},
+    {"message": {"role": "assistant", "content": "Reading it.", "toolCalls": [{"id": "tc1", "type": "function", "function": {"name": "read_file", "arguments": "{\"filepath\":\"parser.go\"}"}}]}, "contextItems": []},
+    {"message": {"role": "tool", "content": "package parser", "toolCallId": "tc1"}, "contextItems": []},
+    {"message": {"role": "assistant", "content": ""}, "contextItems": []}
+  ]
+}`
+
+// summarize describes each entry as "type: block, block", e.g. "user: text"
+func summarize(transcript *claude.Transcript) string {
+	var entries []string
+	for _, entry := range transcript.Entries {
+		var blocks []string
+		for _, block := range entry.Message.Content {
+			blocks = append(blocks, block.Type)
+		}
+		entries = append(entries, string(entry.Type)+": "+strings.Join(blocks, ", "))
+	}
+	return strings.Join(entries, "; ")
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"claude", claudeTranscript, Claude},
+		{"aider", aiderHistory, Aider},
+		{"codex", codexRollout, Codex},
+		{"codex before payloads", `{"id":"x","timestamp":"2025-05-01T10:00:00Z","instructions":null}` + "\n", Codex},
+		{"cursor", cursorExport, Cursor},
+		{"continue", continueFile, Continue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Detect([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Detect() error: %v", err)
+			}
+			if p.Name() != tt.want {
+				t.Errorf("Detect() = %s, want %s", p.Name(), tt.want)
+			}
+		})
+	}
+
+	if _, err := Detect([]byte("just some notes\n")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestGet(t *testing.T) {
+	p, err := Get("")
+	if err != nil || p.Name() != Claude {
+		t.Errorf("Get(\"\") = %v, %v; want claude", p, err)
+	}
+	if _, err := Get("copilot"); err == nil || !strings.Contains(err.Error(), "supported: continue, codex, claude, aider, cursor") {
+		t.Errorf("Get(unknown) error = %v", err)
+	}
+}
+
+func TestParseClaude(t *testing.T) {
+	transcript, err := Parse(Claude, []byte(claudeTranscript))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	// Claude transcripts are kept as recorded
+	if len(transcript.Entries) != 3 || string(transcript.Entries[1].Raw) != `{"uuid":"u1","type":"user","message":{"role":"user","content":"Add a parser"}}` {
+		t.Errorf("entries = %+v", transcript.Entries)
+	}
+}
+
+func TestParseAider(t *testing.T) {
+	transcript, err := Parse(Aider, []byte(aiderHistory))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got, want := summarize(transcript), "system: text; user: text; assistant: text; system: text; user: text"; got != want {
+		t.Fatalf("entries = %q, want %q", got, want)
+	}
+
+	entries := transcript.Entries
+	if got := entries[1].Message.Content[0].Text; got != "add a hello function\nthat greets the world" {
+		t.Errorf("user message = %q", got)
+	}
+	// Fenced edits stay in the reply, even lines starting with >
+	if got := entries[2].Message.Content[0].Text; !strings.HasSuffix(got, ">>>>>>> REPLACE\n```") {
+		t.Errorf("reply = %q", got)
+	}
+	if got := entries[3].Message.Content[0].Text; got != "Applied edit to hello.py\nCommit 1a2b3c4 feat: Add hello function" {
+		t.Errorf("aider output = %q", got)
+	}
+	if entries[0].Timestamp == "" || entries[0].Timestamp == entries[4].Timestamp {
+		t.Errorf("timestamps = %q, %q; want the start of each session", entries[0].Timestamp, entries[4].Timestamp)
+	}
+}
+
+func TestParseCodex(t *testing.T) {
+	transcript, err := Parse(Codex, []byte(codexRollout))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	// The environment context and events are left out
+	if got, want := summarize(transcript), "user: text; assistant: thinking; assistant: tool_use; user: tool_result; assistant: text"; got != want {
+		t.Fatalf("entries = %q, want %q", got, want)
+	}
+
+	call := transcript.Entries[2].Message.Content[0]
+	if call.Name != "Bash" || string(call.Input) != `{"command":"go test ./..."}` {
+		t.Errorf("shell call = %s %s, want a Bash call", call.Name, call.Input)
+	}
+	result := transcript.Entries[3].Message.Content[0]
+	if result.ToolUseID != "call_1" || !result.IsError || claude.ResultText(result) != "FAIL" {
+		t.Errorf("shell result = %+v", result)
+	}
+	if transcript.Entries[0].Timestamp != "2025-05-01T10:00:02Z" {
+		t.Errorf("timestamp = %q", transcript.Entries[0].Timestamp)
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	transcript, err := Parse(Cursor, []byte(cursorExport))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got, want := summarize(transcript), "user: text; assistant: text"; got != want {
+		t.Fatalf("entries = %q, want %q", got, want)
+	}
+	if got := transcript.Entries[0].Message.Content[0].Text; got != "Split the parser" {
+		t.Errorf("user message = %q", got)
+	}
+	// Rules within a message are kept
+	if got := transcript.Entries[1].Message.Content[0].Text; got != "I split it into two files:\n\n---\n\n- lexer.go\n- parser.go" {
+		t.Errorf("reply = %q", got)
+	}
+}
+
+func TestParseContinue(t *testing.T) {
+	transcript, err := Parse(Continue, []byte(continueFile))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got, want := summarize(transcript), "user: text; assistant: text, tool_use; user: tool_result"; got != want {
+		t.Fatalf("entries = %q, want %q", got, want)
+	}
+	call := transcript.Entries[1].Message.Content[1]
+	if call.ID != "tc1" || call.Name != "read_file" || string(call.Input) != `{"filepath":"parser.go"}` {
+		t.Errorf("tool call = %+v", call)
+	}
+}
+
+func TestNormalizedEntries(t *testing.T) {
+	transcript, err := Parse(Continue, []byte(continueFile))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	// UUIDs are numbered in order and chained to their parents
+	first, second := transcript.Entries[0], transcript.Entries[1]
+	if !strings.HasPrefix(first.UUID, "continue-") || !strings.HasSuffix(first.UUID, "-1") {
+		t.Errorf("first uuid = %q", first.UUID)
+	}
+	if second.UUID != strings.TrimSuffix(first.UUID, "1")+"2" || second.ParentUUID != first.UUID {
+		t.Errorf("uuid = %q, parent = %q", second.UUID, second.ParentUUID)
+	}
+
+	// Raw holds the entry in Claude's format, which parses back the same
+	reparsed, err := claude.ParseTranscript(strings.NewReader(string(second.Raw)))
+	if err != nil {
+		t.Fatalf("ParseTranscript() error: %v", err)
+	}
+	got, _ := json.Marshal(reparsed.Entries[0].Message.Content)
+	want, _ := json.Marshal(second.Message.Content)
+	if string(got) != string(want) {
+		t.Errorf("reparsed content = %s, want %s", got, want)
+	}
+}
+
+func TestNormalizedUUIDsPerSession(t *testing.T) {
+	parse := func(export string) *claude.Transcript {
+		t.Helper()
+		transcript, err := Parse(Cursor, []byte(export))
+		if err != nil {
+			t.Fatalf("Parse() error: %v", err)
+		}
+		return transcript
+	}
+
+	session := parse(cursorExport)
+	grown := parse(cursorExport + "\n---\n\n**User**\n\nNow add tests\n")
+	other := parse(strings.Replace(cursorExport, "Split the parser", "Split the lexer", 1))
+
+	// A session keeps its UUIDs as it grows
+	for i, entry := range session.Entries {
+		if grown.Entries[i].UUID != entry.UUID {
+			t.Errorf("entry %d: uuid %q became %q", i, entry.UUID, grown.Entries[i].UUID)
+		}
+	}
+	// Another session of the same provider doesn't share them
+	if other.Entries[0].UUID == session.Entries[0].UUID {
+		t.Errorf("sessions share uuid %q", other.Entries[0].UUID)
+	}
+}
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+)
+
+// SessionSummary is the timeline of a session and the commits made during it
+type SessionSummary struct {
+	SessionID string           `json:"session_id"`
+	Branch    string           `json:"branch"`
+	Timeline  *claude.Timeline `json:"timeline"`
+}
+
+// Sessions groups the conversations of the given commits by session. Each
+// session's timeline is built from the longest transcript stored for it, and
+// marks every given commit made in it. Sessions are ordered by start time;
+// sessions whose entries have no timestamps are left out.
+func Sessions(commits []git.CommitMeta, idleThreshold time.Duration) ([]SessionSummary, error) {
+	type session struct {
+		stored  *storage.StoredConversation
+		count   int
+		commits []claude.CommitMark
+	}
+	sessions := make(map[string]*session)
+
+	for _, meta := range commits {
+		stored, err := storage.GetStoredConversation(meta.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.SHA[:7], err)
+		}
+		if stored == nil || stored.IsAggregate() {
+			continue
+		}
+
+		s := sessions[stored.SessionID]
+		if s == nil {
+			s = &session{}
+			sessions[stored.SessionID] = s
+		}
+		s.commits = append(s.commits, claude.CommitMark{SHA: meta.SHA, Time: meta.Date})
+		if stored.MessageCount >= s.count {
+			s.stored = stored
+			s.count = stored.MessageCount
+		}
+	}
+
+	var summaries []SessionSummary
+	for id, s := range sessions {
+		transcript, err := s.stored.ParseTranscript()
+		if err != nil {
+			return nil, fmt.Errorf("session %s: %w", id, err)
+		}
+		timeline := claude.NewTimeline(transcript.Entries, idleThreshold)
+		if timeline == nil {
+			continue
+		}
+		timeline.AddCommits(s.commits...)
+		summaries = append(summaries, SessionSummary{SessionID: id, Branch: s.stored.GitBranch, Timeline: timeline})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Timeline.Start.Before(summaries[j].Timeline.Start)
+	})
+	return summaries, nil
+}
+
+// SessionCommits returns the commits whose conversation belongs to a session
+func SessionCommits(sessionID string) ([]claude.CommitMark, error) {
+	commits, err := CommitsWithConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	var marks []claude.CommitMark
+	for _, meta := range commits {
+		stored, err := storage.GetStoredConversation(meta.SHA)
+		if err != nil || stored == nil || stored.SessionID != sessionID || stored.IsAggregate() {
+			continue
+		}
+		marks = append(marks, claude.CommitMark{SHA: meta.SHA, Time: meta.Date})
+	}
+	return marks, nil
+}
+
+// CommitsWithConversations returns every commit with a stored conversation,
+// newest first
+func CommitsWithConversations() ([]git.CommitMeta, error) {
+	shas, err := git.ListCommitsWithNotes()
+	if err != nil {
+		return nil, err
+	}
+	if len(shas) == 0 {
+		return nil, nil
+	}
+	return git.LogCommitsOf(shas, "--no-walk")
+}
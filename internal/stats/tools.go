@@ -0,0 +1,245 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+// CommitTools is the tool activity in one commit's conversation
+type CommitTools struct {
+	SHA   string
+	Date  time.Time
+	Calls []claude.ToolCall
+}
+
+// ToolActivity returns the tool calls made in the conversation of each
+// commit that has one. Only each commit's own entries are included.
+func ToolActivity(commits []git.CommitMeta) ([]CommitTools, error) {
+	// List the noted commits once, rather than asking git about each commit
+	noted, err := git.CommitsWithNotesSet()
+	if err != nil {
+		return nil, fmt.Errorf("could not list conversations: %w", err)
+	}
+
+	var activity []CommitTools
+	for _, meta := range commits {
+		if !noted[meta.SHA] {
+			continue
+		}
+		conv, err := attribution.LoadCommitConversation(meta.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.SHA[:7], err)
+		}
+		if conv == nil {
+			continue
+		}
+		activity = append(activity, CommitTools{SHA: meta.SHA, Date: meta.Date, Calls: claude.ToolCalls(conv.Entries)})
+	}
+	return activity, nil
+}
+
+// ToolCount counts the calls of a tool or command and how many failed
+type ToolCount struct {
+	Name      string  `json:"name"`
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// ToolPeriod is the tool activity of the commits in a period of time
+type ToolPeriod struct {
+	// Period is the first day of the period, formatted as YYYY-MM-DD
+	Period         string  `json:"period"`
+	Commits        int     `json:"commits"`
+	ToolCalls      int     `json:"tool_calls"`
+	Errors         int     `json:"errors"`
+	TestedCommits  int     `json:"tested_commits"`
+	CallsPerCommit float64 `json:"calls_per_commit"`
+}
+
+// ToolReport summarizes how tools were used across commits
+type ToolReport struct {
+	// Commits counts the commits with a conversation
+	Commits        int     `json:"commits"`
+	ToolCalls      int     `json:"tool_calls"`
+	Errors         int     `json:"errors"`
+	CallsPerCommit float64 `json:"calls_per_commit"`
+	// TestedCommits counts commits whose conversation ran tests before committing
+	TestedCommits int     `json:"tested_commits"`
+	TestedShare   float64 `json:"tested_share"`
+	// Tools counts calls per tool, most used first
+	Tools []ToolCount `json:"tools"`
+	// BashCommands counts Bash calls per program, e.g. "go test" or "ls"
+	BashCommands []ToolCount `json:"bash_commands"`
+	// FailedCommands lists the Bash commands that failed most often
+	FailedCommands []ToolCount `json:"failed_commands"`
+	// Trend breaks the activity down by period, oldest first
+	Trend []ToolPeriod `json:"trend"`
+}
+
+// maxFailedCommands limits the failing commands listed in a report
+const maxFailedCommands = 10
+
+// NewToolReport summarizes tool activity, with a trend by day, week or month
+func NewToolReport(activity []CommitTools, interval string) (*ToolReport, error) {
+	report := &ToolReport{Commits: len(activity)}
+	tools := newCounter()
+	programs := newCounter()
+	failed := newCounter()
+	periods := make(map[string]*ToolPeriod)
+
+	for _, commit := range activity {
+		start, err := util.PeriodStart(commit.Date, interval)
+		if err != nil {
+			return nil, err
+		}
+		key := start.Format("2006-01-02")
+		if periods[key] == nil {
+			periods[key] = &ToolPeriod{Period: key}
+		}
+		period := periods[key]
+		period.Commits++
+
+		tested := false
+		for _, call := range commit.Calls {
+			failedCall := call.Failed()
+			report.ToolCalls++
+			period.ToolCalls++
+			if failedCall {
+				report.Errors++
+				period.Errors++
+			}
+			tools.add(call.Name, failedCall)
+
+			command := call.Command()
+			if command == "" {
+				continue
+			}
+			programs.add(CommandProgram(command), failedCall)
+			if failedCall {
+				failed.add(firstCommandLine(command), true)
+			}
+			if IsTestCommand(command) {
+				tested = true
+			}
+		}
+		if tested {
+			report.TestedCommits++
+			period.TestedCommits++
+		}
+	}
+
+	report.CallsPerCommit = ratio(report.ToolCalls, report.Commits)
+	report.TestedShare = ratio(report.TestedCommits, report.Commits)
+	report.Tools = tools.sorted(func(c ToolCount) int { return c.Calls })
+	report.BashCommands = programs.sorted(func(c ToolCount) int { return c.Calls })
+	report.FailedCommands = failed.sorted(func(c ToolCount) int { return c.Errors })
+	if len(report.FailedCommands) > maxFailedCommands {
+		report.FailedCommands = report.FailedCommands[:maxFailedCommands]
+	}
+
+	report.Trend = make([]ToolPeriod, 0, len(periods))
+	for _, period := range periods {
+		period.CallsPerCommit = ratio(period.ToolCalls, period.Commits)
+		report.Trend = append(report.Trend, *period)
+	}
+	sort.Slice(report.Trend, func(i, j int) bool { return report.Trend[i].Period < report.Trend[j].Period })
+
+	return report, nil
+}
+
+// testCommand matches commands that run a test suite
+var testCommand = regexp.MustCompile(`(^|[\s;&|(])(go test|ginkgo|(npm|yarn|pnpm|bun)( run)? test|npx (jest|vitest)|jest|vitest|pytest|python3? -m (pytest|unittest)|cargo test|make (test|check)|rspec|bundle exec rspec|mvn test|gradle test|./gradlew test|dotnet test|mix test|phpunit)\b`)
+
+// IsTestCommand returns true if a shell command runs tests
+func IsTestCommand(command string) bool {
+	return testCommand.MatchString(command)
+}
+
+// subcommandPrograms are programs whose first argument names what they do
+var subcommandPrograms = map[string]bool{
+	"go": true, "git": true, "npm": true, "yarn": true, "pnpm": true, "bun": true,
+	"cargo": true, "make": true, "docker": true, "kubectl": true, "gh": true,
+	"bundle": true, "mix": true, "dotnet": true, "uv": true, "poetry": true,
+}
+
+// commandSeparator splits a shell command line into simple commands
+var commandSeparator = regexp.MustCompile(`&&|\|\||[;|\n]`)
+
+// CommandProgram returns the program a shell command runs, with its
+// subcommand for tools like go and git, e.g. "go test" for
+// "cd app && go test ./...". Environment assignments, sudo and leading cd
+// commands are skipped.
+func CommandProgram(command string) string {
+	for _, segment := range commandSeparator.Split(command, -1) {
+		fields := strings.Fields(segment)
+		for len(fields) > 0 && (strings.Contains(fields[0], "=") || fields[0] == "sudo" || fields[0] == "env") {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 || fields[0] == "cd" {
+			continue
+		}
+		if subcommandPrograms[fields[0]] && len(fields) > 1 && !strings.HasPrefix(fields[1], "-") {
+			return fields[0] + " " + fields[1]
+		}
+		return fields[0]
+	}
+	return strings.TrimSpace(command)
+}
+
+// firstCommandLine returns the first line of a command, truncated for display
+func firstCommandLine(command string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(command), "\n", 2)[0])
+	if len(line) > 80 {
+		line = line[:77] + "..."
+	}
+	return line
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// counter tallies calls and errors by name
+type counter map[string]*ToolCount
+
+func newCounter() counter {
+	return make(counter)
+}
+
+func (c counter) add(name string, failed bool) {
+	if c[name] == nil {
+		c[name] = &ToolCount{Name: name}
+	}
+	c[name].Calls++
+	if failed {
+		c[name].Errors++
+	}
+}
+
+// sorted returns the counts in descending order of key, then by name
+func (c counter) sorted(key func(ToolCount) int) []ToolCount {
+	result := make([]ToolCount, 0, len(c))
+	for _, count := range c {
+		count.ErrorRate = ratio(count.Errors, count.Calls)
+		result = append(result, *count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if key(result[i]) != key(result[j]) {
+			return key(result[i]) > key(result[j])
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
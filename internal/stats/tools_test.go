@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+const toolsTranscript = `{"uuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"cd app && go test ./..."}},{"type":"tool_use","id":"t2","name":"Read","input":{"file_path":"main.go"}}]}}
+{"uuid":"r1","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true},{"type":"tool_result","tool_use_id":"t2","content":"package main"}]}}
+{"uuid":"a2","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"uuid":"r2","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"ok"}]}}`
+
+func TestNewToolReport(t *testing.T) {
+	transcript, err := claude.ParseTranscript(strings.NewReader(toolsTranscript))
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	date := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	activity := []CommitTools{
+		{SHA: "c1", Date: date, Calls: claude.ToolCalls(transcript.Entries)},
+		{SHA: "c2", Date: date.AddDate(0, 1, 0)},
+	}
+
+	report, err := NewToolReport(activity, "month")
+	if err != nil {
+		t.Fatalf("NewToolReport failed: %v", err)
+	}
+
+	if report.Commits != 2 || report.ToolCalls != 3 || report.Errors != 1 || report.CallsPerCommit != 1.5 {
+		t.Errorf("summary = %+v", report)
+	}
+	if report.TestedCommits != 1 || report.TestedShare != 0.5 {
+		t.Errorf("TestedCommits = %d (%v), want 1 (0.5)", report.TestedCommits, report.TestedShare)
+	}
+	if len(report.Tools) != 2 || report.Tools[0].Name != "Bash" || report.Tools[0].Calls != 2 || report.Tools[0].Errors != 1 {
+		t.Errorf("Tools = %+v, want Bash first with 2 calls and 1 error", report.Tools)
+	}
+	if len(report.BashCommands) != 1 || report.BashCommands[0].Name != "go test" {
+		t.Errorf("BashCommands = %+v, want go test", report.BashCommands)
+	}
+	if len(report.FailedCommands) != 1 || report.FailedCommands[0].Name != "cd app && go test ./..." {
+		t.Errorf("FailedCommands = %+v", report.FailedCommands)
+	}
+	if len(report.Trend) != 2 || report.Trend[0].Period != "2024-03-01" || report.Trend[0].ToolCalls != 3 {
+		t.Errorf("Trend = %+v", report.Trend)
+	}
+}
+
+func TestCommandProgram(t *testing.T) {
+	tests := map[string]string{
+		"ls -la":                       "ls",
+		"git status":                   "git status",
+		"go -C app build":              "go",
+		"cd web && npm test":           "npm test",
+		"CGO_ENABLED=0 go build ./...": "go build",
+		"sudo apt-get install jq":      "apt-get",
+		"grep foo file | head -5":      "grep",
+		"python -m pytest tests/":      "python",
+		"make":                         "make",
+	}
+	for command, want := range tests {
+		if got := CommandProgram(command); got != want {
+			t.Errorf("CommandProgram(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestIsTestCommand(t *testing.T) {
+	for _, command := range []string{"go test ./...", "cd web && npm run test", "python3 -m pytest", "make test", "npx vitest run"} {
+		if !IsTestCommand(command) {
+			t.Errorf("IsTestCommand(%q) = false, want true", command)
+		}
+	}
+	for _, command := range []string{"go build ./...", "git commit -m 'test'", "cat latest.txt", "ls testdata"} {
+		if IsTestCommand(command) {
+			t.Errorf("IsTestCommand(%q) = true, want false", command)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package stats aggregates statistics over the conversations stored in a repository.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/attribution"
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// UsageRecord is the token usage of one API response, along with what it
+// can be grouped by
+type UsageRecord struct {
+	CommitSHA string
+	Author    string
+	SessionID string
+	Branch    string
+	// Day is the date of the response, or of the commit if the entry has no timestamp
+	Day   string
+	Model string
+	Usage claude.Usage
+}
+
+// UsageRecords returns the usage of every API response in the conversations
+// of the given commits. Only each commit's own entries are counted, so a
+// session spanning several commits is not counted more than once.
+func UsageRecords(commits []git.CommitMeta) ([]UsageRecord, error) {
+	// List the noted commits once, rather than asking git about each commit
+	noted, err := git.CommitsWithNotesSet()
+	if err != nil {
+		return nil, fmt.Errorf("could not list conversations: %w", err)
+	}
+
+	var records []UsageRecord
+	for _, meta := range commits {
+		if !noted[meta.SHA] {
+			continue
+		}
+		conv, err := attribution.LoadCommitConversation(meta.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.SHA[:7], err)
+		}
+		if conv == nil {
+			continue
+		}
+
+		for _, response := range claude.ResponseUsage(conv.Entries) {
+			day := meta.Date.Format("2006-01-02")
+			if ts, err := time.Parse(time.RFC3339, conv.Entries[response.Index].Timestamp); err == nil {
+				day = ts.Local().Format("2006-01-02")
+			}
+			records = append(records, UsageRecord{
+				CommitSHA: meta.SHA,
+				Author:    meta.Author,
+				SessionID: conv.Stored.SessionID,
+				Branch:    conv.Stored.GitBranch,
+				Day:       day,
+				Model:     response.Model,
+				Usage:     response.Usage,
+			})
+		}
+	}
+	return records, nil
+}
+
+// UsageGroup is the total usage and estimated cost of a group of responses
+type UsageGroup struct {
+	Key       string `json:"key"`
+	Responses int    `json:"responses"`
+	claude.Usage
+	// CostUSD is the estimated cost; responses from unpriced models count as free
+	CostUSD float64 `json:"cost_usd"`
+	// UnpricedModels lists models with no entry in the price table
+	UnpricedModels []string `json:"unpriced_models,omitempty"`
+}
+
+func (g *UsageGroup) add(record UsageRecord, prices claude.PriceTable) {
+	g.Responses++
+	g.Usage.Add(record.Usage)
+	if price, ok := prices.Lookup(record.Model); ok {
+		g.CostUSD += price.Cost(record.Usage)
+	} else if record.Usage.Total() > 0 {
+		g.UnpricedModels = appendModel(g.UnpricedModels, record.Model)
+	}
+}
+
+// GroupUsage totals usage records by commit, session, branch, author, day or
+// model. Groups are sorted by key, except commits, which keep the order of
+// the records.
+func GroupUsage(records []UsageRecord, by string, prices claude.PriceTable) ([]UsageGroup, error) {
+	keyOf, err := usageKey(by)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*UsageGroup)
+	var order []string
+	for _, record := range records {
+		key := keyOf(record)
+		if groups[key] == nil {
+			groups[key] = &UsageGroup{Key: key}
+			order = append(order, key)
+		}
+		groups[key].add(record, prices)
+	}
+
+	if by != "commit" {
+		sort.Strings(order)
+	}
+	result := make([]UsageGroup, 0, len(order))
+	for _, key := range order {
+		result = append(result, *groups[key])
+	}
+	return result, nil
+}
+
+// TotalUsage totals all usage records
+func TotalUsage(records []UsageRecord, prices claude.PriceTable) UsageGroup {
+	total := UsageGroup{Key: "total"}
+	for _, record := range records {
+		total.add(record, prices)
+	}
+	return total
+}
+
+// usageKey returns the function that picks the grouping key of a record
+func usageKey(by string) (func(UsageRecord) string, error) {
+	switch by {
+	case "commit":
+		return func(r UsageRecord) string { return r.CommitSHA }, nil
+	case "session":
+		return func(r UsageRecord) string { return r.SessionID }, nil
+	case "branch":
+		return func(r UsageRecord) string { return r.Branch }, nil
+	case "author":
+		return func(r UsageRecord) string { return r.Author }, nil
+	case "day":
+		return func(r UsageRecord) string { return r.Day }, nil
+	case "model":
+		return func(r UsageRecord) string { return r.Model }, nil
+	}
+	return nil, fmt.Errorf("unknown grouping %q (expected commit, session, branch, author, day or model)", by)
+}
+
+func appendModel(models []string, model string) []string {
+	for _, m := range models {
+		if m == model {
+			return models
+		}
+	}
+	return append(models, model)
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+func TestGroupUsage(t *testing.T) {
+	prices := claude.PriceTable{"model-a": {Input: 1, Output: 2}}
+	records := []UsageRecord{
+		{CommitSHA: "c2", SessionID: "s1", Day: "2024-03-02", Model: "model-a", Usage: claude.Usage{InputTokens: 1_000_000}},
+		{CommitSHA: "c1", SessionID: "s1", Day: "2024-03-01", Model: "model-a", Usage: claude.Usage{OutputTokens: 1_000_000}},
+		{CommitSHA: "c1", SessionID: "s2", Day: "2024-03-01", Model: "model-b", Usage: claude.Usage{InputTokens: 10}},
+	}
+
+	byCommit, err := GroupUsage(records, "commit", prices)
+	if err != nil {
+		t.Fatalf("GroupUsage failed: %v", err)
+	}
+	if len(byCommit) != 2 || byCommit[0].Key != "c2" || byCommit[1].Key != "c1" {
+		t.Fatalf("commits should keep record order, got %+v", byCommit)
+	}
+	if byCommit[1].Responses != 2 || math.Abs(byCommit[1].CostUSD-2) > 1e-9 {
+		t.Errorf("c1 = %+v, want 2 responses costing $2", byCommit[1])
+	}
+	if len(byCommit[1].UnpricedModels) != 1 || byCommit[1].UnpricedModels[0] != "model-b" {
+		t.Errorf("c1 UnpricedModels = %v, want [model-b]", byCommit[1].UnpricedModels)
+	}
+
+	byDay, err := GroupUsage(records, "day", prices)
+	if err != nil {
+		t.Fatalf("GroupUsage failed: %v", err)
+	}
+	if len(byDay) != 2 || byDay[0].Key != "2024-03-01" {
+		t.Errorf("days should be sorted, got %+v", byDay)
+	}
+
+	total := TotalUsage(records, prices)
+	if total.InputTokens != 1_000_010 || math.Abs(total.CostUSD-3) > 1e-9 {
+		t.Errorf("TotalUsage() = %+v", total)
+	}
+
+	if _, err := GroupUsage(records, "planet", prices); err == nil {
+		t.Error("GroupUsage() should reject unknown groupings")
+	}
+}
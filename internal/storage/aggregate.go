@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// SourceCommit records an original commit whose conversation was folded into
+// an aggregate note, e.g. one of the commits of a squash-merged branch.
+// The entries between FirstEntryUUID and LastEntryUUID (inclusive) of the
+// aggregate transcript belong to this commit.
+type SourceCommit struct {
+	SHA            string `json:"sha"`
+	Message        string `json:"message,omitempty"`
+	SessionID      string `json:"session_id"`
+	FirstEntryUUID string `json:"first_entry_uuid,omitempty"`
+	LastEntryUUID  string `json:"last_entry_uuid,omitempty"`
+}
+
+// AggregateSource is a commit and its stored conversation, used as input
+// to Aggregate.
+type AggregateSource struct {
+	SHA     string
+	Message string
+	Stored  *StoredConversation
+}
+
+// Segment is the part of an aggregate transcript that belongs to one source commit
+type Segment struct {
+	Commit  SourceCommit
+	Entries []claude.TranscriptEntry
+}
+
+// IsAggregate returns true if the conversation combines several original commits
+func (sc *StoredConversation) IsAggregate() bool {
+	return len(sc.SourceCommits) > 0
+}
+
+// Segments splits an aggregate transcript into per-source-commit slices.
+// Returns nil for conversations that are not aggregates.
+func (sc *StoredConversation) Segments(t *claude.Transcript) []Segment {
+	if !sc.IsAggregate() {
+		return nil
+	}
+
+	segments := make([]Segment, 0, len(sc.SourceCommits))
+	for _, source := range sc.SourceCommits {
+		segment := Segment{Commit: source}
+		first := t.FindEntryIndex(source.FirstEntryUUID)
+		last := t.FindEntryIndex(source.LastEntryUUID)
+		if first != -1 && last >= first {
+			segment.Entries = t.Entries[first : last+1]
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// Aggregate combines the conversations of several commits, given oldest first,
+// into a single multi-session conversation. Each commit contributes only the
+// entries added since the previous commit of the same session, so the
+// combined transcript contains every entry exactly once while the source
+// commit boundaries are kept for rendering. Sources that are themselves
+// aggregates are flattened.
+func Aggregate(sources []AggregateSource) (*StoredConversation, error) {
+	var combined []claude.TranscriptEntry
+	var sourceCommits []SourceCommit
+	seen := make(map[string]bool)
+	lastSeen := make(map[string]string) // session ID -> last entry UUID
+
+	appendSlice := func(commit SourceCommit, entries []claude.TranscriptEntry) {
+		for _, entry := range entries {
+			key := entry.UUID
+			if key == "" {
+				key = string(entry.Raw)
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			combined = append(combined, entry)
+
+			if entry.UUID == "" {
+				continue
+			}
+			if commit.FirstEntryUUID == "" {
+				commit.FirstEntryUUID = entry.UUID
+			}
+			commit.LastEntryUUID = entry.UUID
+			lastSeen[commit.SessionID] = entry.UUID
+		}
+		sourceCommits = append(sourceCommits, commit)
+	}
+
+	var last *StoredConversation
+	for _, source := range sources {
+		if source.Stored == nil {
+			continue
+		}
+
+		transcript, err := source.Stored.ParseTranscript()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse transcript for %s: %w", source.SHA, err)
+		}
+
+		if source.Stored.IsAggregate() {
+			for _, segment := range source.Stored.Segments(transcript) {
+				appendSlice(SourceCommit{
+					SHA:       segment.Commit.SHA,
+					Message:   segment.Commit.Message,
+					SessionID: segment.Commit.SessionID,
+				}, segment.Entries)
+			}
+		} else {
+			entries := transcript.GetEntriesSince(lastSeen[source.Stored.SessionID])
+			appendSlice(SourceCommit{
+				SHA:       source.SHA,
+				Message:   source.Message,
+				SessionID: source.Stored.SessionID,
+			}, entries)
+		}
+		last = source.Stored
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("no conversations to aggregate")
+	}
+
+	transcript := &claude.Transcript{Entries: combined}
+	data, err := transcript.ToJSONL()
+	if err != nil {
+		return nil, err
+	}
+
+	// The most recent session is the one worth resuming
+	stored, err := NewStoredConversation(last.SessionID, last.ProjectPath, last.GitBranch, transcript.MessageCount(), data)
+	if err != nil {
+		return nil, err
+	}
+	stored.SourceCommits = sourceCommits
+	return stored, nil
+}
+
+// AggregateCommits reads the conversations stored on the given commits
+// (oldest first) and combines them with Aggregate. Commits without a
+// conversation are skipped.
+func AggregateCommits(commits []string) (*StoredConversation, error) {
+	var sources []AggregateSource
+	for _, sha := range commits {
+		stored, err := GetStoredConversation(sha)
+		if err != nil {
+			return nil, err
+		}
+		if stored == nil {
+			continue
+		}
+		message, _, _ := git.GetCommitInfo(sha)
+		sources = append(sources, AggregateSource{SHA: sha, Message: message, Stored: stored})
+	}
+	return Aggregate(sources)
+}
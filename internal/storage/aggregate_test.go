@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DanielJonesEB/claudit/internal/provider"
+)
+
+func jsonlEntries(uuids ...string) []byte {
+	var lines []string
+	for _, uuid := range uuids {
+		lines = append(lines, `{"uuid":"`+uuid+`","type":"user"}`)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func mustStored(t *testing.T, sessionID string, data []byte) *StoredConversation {
+	t.Helper()
+	sc, err := NewStoredConversation(sessionID, "/test", "feature", strings.Count(string(data), "\n")+1, data)
+	if err != nil {
+		t.Fatalf("NewStoredConversation() error: %v", err)
+	}
+	return sc
+}
+
+func segmentUUIDs(segment Segment) string {
+	var uuids []string
+	for _, e := range segment.Entries {
+		uuids = append(uuids, e.UUID)
+	}
+	return strings.Join(uuids, ",")
+}
+
+func TestAggregateSingleSession(t *testing.T) {
+	sources := []AggregateSource{
+		{SHA: "aaa", Message: "first", Stored: mustStored(t, "s1", jsonlEntries("1", "2"))},
+		{SHA: "bbb", Message: "second", Stored: mustStored(t, "s1", jsonlEntries("1", "2", "3", "4"))},
+	}
+
+	agg, err := Aggregate(sources)
+	if err != nil {
+		t.Fatalf("Aggregate() error: %v", err)
+	}
+
+	if agg.MessageCount != 4 {
+		t.Errorf("MessageCount = %d, want 4", agg.MessageCount)
+	}
+	if len(agg.SourceCommits) != 2 {
+		t.Fatalf("SourceCommits = %d, want 2", len(agg.SourceCommits))
+	}
+
+	transcript, err := agg.ParseTranscript()
+	if err != nil {
+		t.Fatalf("ParseTranscript() error: %v", err)
+	}
+	segments := agg.Segments(transcript)
+	if got := segmentUUIDs(segments[0]); got != "1,2" {
+		t.Errorf("segment 0 = %q, want %q", got, "1,2")
+	}
+	if got := segmentUUIDs(segments[1]); got != "3,4" {
+		t.Errorf("segment 1 = %q, want %q", got, "3,4")
+	}
+	if segments[1].Commit.Message != "second" {
+		t.Errorf("segment 1 message = %q, want %q", segments[1].Commit.Message, "second")
+	}
+}
+
+func TestAggregateMultipleSessions(t *testing.T) {
+	sources := []AggregateSource{
+		{SHA: "aaa", Stored: mustStored(t, "s1", jsonlEntries("a1", "a2"))},
+		{SHA: "bbb", Stored: mustStored(t, "s2", jsonlEntries("b1"))},
+		{SHA: "ccc", Stored: mustStored(t, "s1", jsonlEntries("a1", "a2", "a3"))},
+	}
+
+	agg, err := Aggregate(sources)
+	if err != nil {
+		t.Fatalf("Aggregate() error: %v", err)
+	}
+
+	// The latest session is kept for resuming
+	if agg.SessionID != "s1" {
+		t.Errorf("SessionID = %q, want %q", agg.SessionID, "s1")
+	}
+
+	transcript, err := agg.ParseTranscript()
+	if err != nil {
+		t.Fatalf("ParseTranscript() error: %v", err)
+	}
+	segments := agg.Segments(transcript)
+	want := []string{"a1,a2", "b1", "a3"}
+	for i, w := range want {
+		if got := segmentUUIDs(segments[i]); got != w {
+			t.Errorf("segment %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestAggregateFlattensNestedAggregates(t *testing.T) {
+	inner, err := Aggregate([]AggregateSource{
+		{SHA: "aaa", Stored: mustStored(t, "s1", jsonlEntries("1"))},
+		{SHA: "bbb", Stored: mustStored(t, "s1", jsonlEntries("1", "2"))},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate() error: %v", err)
+	}
+
+	outer, err := Aggregate([]AggregateSource{
+		{SHA: "squash", Stored: inner},
+		{SHA: "ccc", Stored: mustStored(t, "s2", jsonlEntries("x"))},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate() error: %v", err)
+	}
+
+	var shas []string
+	for _, sc := range outer.SourceCommits {
+		shas = append(shas, sc.SHA)
+	}
+	if got := strings.Join(shas, ","); got != "aaa,bbb,ccc" {
+		t.Errorf("source commits = %q, want %q", got, "aaa,bbb,ccc")
+	}
+}
+
+func TestAggregateSessionsOfSameProvider(t *testing.T) {
+	cursorExport := func(prompt string) []byte {
+		return []byte("# Chat\n_Exported on 5/1/2025 at 10:00:00 GMT+1 from Cursor (1.0.0)_\n\n---\n\n**User**\n\n" +
+			prompt + "\n\n---\n\n**Cursor**\n\nDone.\n")
+	}
+	stored := func(sessionID, prompt string) *StoredConversation {
+		sc := mustStored(t, sessionID, cursorExport(prompt))
+		sc.Provider = provider.Cursor
+		return sc
+	}
+
+	agg, err := Aggregate([]AggregateSource{
+		{SHA: "aaa", Stored: stored("parser", "Split the parser")},
+		{SHA: "bbb", Stored: stored("lexer", "Add a lexer")},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate() error: %v", err)
+	}
+
+	// Neither session's entries are mistaken for the other's
+	transcript, err := agg.ParseTranscript()
+	if err != nil {
+		t.Fatalf("ParseTranscript() error: %v", err)
+	}
+	segments := agg.Segments(transcript)
+	for i, segment := range segments {
+		if len(segment.Entries) != 2 {
+			t.Errorf("segment %d has %d entries, want 2", i, len(segment.Entries))
+		}
+	}
+}
+
+func TestAggregateNoConversations(t *testing.T) {
+	if _, err := Aggregate(nil); err == nil {
+		t.Error("Aggregate() should fail with no conversations")
+	}
+}
+
+func TestSegmentsNonAggregate(t *testing.T) {
+	sc := mustStored(t, "s1", jsonlEntries("1"))
+	transcript, err := sc.ParseTranscript()
+	if err != nil {
+		t.Fatalf("ParseTranscript() error: %v", err)
+	}
+	if sc.Segments(transcript) != nil {
+		t.Error("Segments() should be nil for a non-aggregate conversation")
+	}
+}
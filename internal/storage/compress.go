@@ -61,6 +61,37 @@ func VerifyChecksum(data []byte, expected string) bool {
 	return Checksum(data) == expected
 }
 
+// MatchChecksumPrefix finds the prefix of data, ending at a line boundary, whose
+// checksum matches expected. Transcripts only ever grow by appending lines, so
+// this recovers the state of a transcript at the time a checksum was taken.
+// Returns the prefix length and true if a match was found.
+func MatchChecksumPrefix(data []byte, expected string) (int, bool) {
+	h := sha256.New()
+	matches := func() bool { return fmt.Sprintf("sha256:%x", h.Sum(nil)) == expected }
+
+	start := 0
+	for i, b := range data {
+		if b != '\n' {
+			continue
+		}
+		// Check the prefix both without and with the line's trailing newline
+		h.Write(data[start:i])
+		if matches() {
+			return i, true
+		}
+		h.Write(data[i : i+1])
+		if matches() {
+			return i + 1, true
+		}
+		start = i + 1
+	}
+	h.Write(data[start:])
+	if matches() {
+		return len(data), true
+	}
+	return 0, false
+}
+
 // CompressAndEncode compresses and base64 encodes data
 func CompressAndEncode(data []byte) (string, error) {
 	compressed, err := Compress(data)
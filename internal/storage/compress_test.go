@@ -131,3 +131,42 @@ func TestDecodeAndDecompressValidBase64InvalidGzip(t *testing.T) {
 		t.Error("DecodeAndDecompress() should fail when base64 decodes to non-gzip data")
 	}
 }
+
+func TestMatchChecksumPrefix(t *testing.T) {
+	data := []byte("line1\nline2\nline3\n")
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   int
+	}{
+		{"first line with newline", "line1\n", 6},
+		{"first line without newline", "line1", 5},
+		{"two lines", "line1\nline2\n", 12},
+		{"whole data", "line1\nline2\nline3\n", len(data)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			n, ok := MatchChecksumPrefix(data, Checksum([]byte(tc.prefix)))
+			if !ok {
+				t.Fatal("MatchChecksumPrefix() found no match")
+			}
+			if n != tc.want {
+				t.Errorf("MatchChecksumPrefix() = %d, want %d", n, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchChecksumPrefixNoMatch(t *testing.T) {
+	data := []byte("line1\nline2\n")
+
+	// Prefixes that end mid-line are never matched
+	if _, ok := MatchChecksumPrefix(data, Checksum([]byte("line"))); ok {
+		t.Error("MatchChecksumPrefix() matched a prefix ending mid-line")
+	}
+	if _, ok := MatchChecksumPrefix(data, Checksum([]byte("other\n"))); ok {
+		t.Error("MatchChecksumPrefix() matched unrelated data")
+	}
+}
@@ -2,10 +2,10 @@ package storage
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/DanielJonesEB/claudit/internal/claude"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/provider"
 )
 
 // GetStoredConversation retrieves and parses a stored conversation from a commit's git note.
@@ -28,13 +28,14 @@ func GetStoredConversation(commitSHA string) (*StoredConversation, error) {
 	return stored, nil
 }
 
-// ParseTranscript decompresses the stored transcript and parses it into a Transcript.
+// ParseTranscript decompresses the stored transcript and parses it into a
+// Transcript, normalizing the format of its provider.
 func (sc *StoredConversation) ParseTranscript() (*claude.Transcript, error) {
 	data, err := sc.GetTranscript()
 	if err != nil {
 		return nil, err
 	}
-	return claude.ParseTranscript(strings.NewReader(string(data)))
+	return provider.Parse(sc.Provider, data)
 }
 
 // FindParentConversationBoundary finds the most recent parent commit with a conversation
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// noteIndent is how git indents the lines of notes in git log and git show
+const noteIndent = "    "
+
+// colorCode matches the color codes git log adds with --color
+var colorCode = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// RewriteNotes copies the output of git log or git show from r to w with
+// each conversation note replaced by its readable header. Notes that can't
+// be parsed are copied as they are.
+func RewriteNotes(r io.Reader, w io.Writer) error {
+	label := "Notes (" + strings.TrimPrefix(git.NotesRef, "refs/notes/") + "):"
+	reader := bufio.NewReader(r)
+	var note []string
+	inNote := false
+
+	for {
+		// Notes are a single line of JSON, too long for a bufio.Scanner
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			plain := colorCode.ReplaceAllString(strings.TrimRight(line, "\n"), "")
+			if inNote && strings.HasPrefix(plain, noteIndent) {
+				note = append(note, line)
+			} else {
+				if inNote {
+					if werr := writeNote(w, note); werr != nil {
+						return werr
+					}
+					note, inNote = nil, false
+				}
+				if _, werr := io.WriteString(w, line); werr != nil {
+					return werr
+				}
+				inNote = strings.TrimSpace(plain) == label
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if inNote {
+		return writeNote(w, note)
+	}
+	return nil
+}
+
+// writeNote writes the header of the note shown in lines: the lines stored
+// before its payload, or for notes stored without one the header it would
+// have. Notes that aren't conversations are written as they are.
+func writeNote(w io.Writer, lines []string) error {
+	var content strings.Builder
+	var header []string
+	inHeader := true
+	for _, line := range lines {
+		text := strings.TrimPrefix(colorCode.ReplaceAllString(line, ""), noteIndent)
+		content.WriteString(text)
+		if inHeader = inHeader && strings.TrimSpace(text) != ""; inHeader {
+			header = append(header, line)
+		}
+	}
+	stored, err := UnmarshalStoredConversation([]byte(content.String()))
+	if err != nil {
+		_, err = io.WriteString(w, strings.Join(lines, ""))
+		return err
+	}
+	if trimmed := strings.TrimSpace(content.String()); strings.HasPrefix(trimmed, "{") {
+		header = nil
+		for _, line := range stored.Header() {
+			header = append(header, noteIndent+line+"\n")
+		}
+	}
+	_, err = io.WriteString(w, strings.Join(header, ""))
+	return err
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRewriteNotes(t *testing.T) {
+	sc, err := NewStoredConversation("session-1", "/test", "main", 1, []byte(`{"uuid":"1","type":"user","message":{"role":"user","content":"Fix the test"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := sc.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := json.MarshalIndent(sc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indent := func(s string) string {
+		return "    " + strings.ReplaceAll(strings.TrimSuffix(s, "\n"), "\n", "\n    ") + "\n"
+	}
+
+	// The header stored in the note is shown, not one made from its payload
+	stored := "claudit: claude session session-1, 1 messages\n> Fix the old test\n"
+	_, payload, _ := strings.Cut(string(note), "\n\n")
+	edited := stored + "\n" + payload
+
+	log := "commit abc\n\n    Fix it\n\nNotes (claude-conversations):\n" + indent(string(note)) +
+		"\ncommit xyz\n\nNotes (claude-conversations):\n" + indent(edited) +
+		"\ncommit def\n\n    Older\n\nNotes (claude-conversations):\n" + indent(string(old)) +
+		"\ncommit ghi\n\nNotes (claude-conversations):\n    not a conversation\n"
+	header := "    claudit: claude session session-1, 1 messages\n    > Fix the test\n"
+	want := "commit abc\n\n    Fix it\n\nNotes (claude-conversations):\n" + header +
+		"\ncommit xyz\n\nNotes (claude-conversations):\n" + indent(stored) +
+		"\ncommit def\n\n    Older\n\nNotes (claude-conversations):\n" + header +
+		"\ncommit ghi\n\nNotes (claude-conversations):\n    not a conversation\n"
+
+	var out strings.Builder
+	if err := RewriteNotes(strings.NewReader(log), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("RewriteNotes() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
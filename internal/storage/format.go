@@ -1,10 +1,28 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/provider"
+	"github.com/DanielJonesEB/claudit/internal/util"
 )
 
+// FormatVersion is the version of the notes Marshal writes. Version 1 notes
+// are plain JSON; from version 2 the JSON follows a readable header, which
+// claudit releases before it can't read.
+const FormatVersion = 2
+
+// headerPrefix starts the first line of a note's readable header
+const headerPrefix = "claudit: "
+
+// headerPromptLength is the longest the first prompt is quoted in a note's
+// header, in characters
+const headerPromptLength = 72
+
 // StoredConversation represents the format stored in git notes
 type StoredConversation struct {
 	Version      int    `json:"version"`
@@ -14,7 +32,19 @@ type StoredConversation struct {
 	GitBranch    string `json:"git_branch"`
 	MessageCount int    `json:"message_count"`
 	Checksum     string `json:"checksum"`
-	Transcript   string `json:"transcript"` // base64-encoded gzipped JSONL
+	Transcript   string `json:"transcript"` // base64-encoded gzipped transcript
+
+	// Provider is the assistant whose format the transcript is in; notes
+	// stored before it was recorded are from Claude Code
+	Provider string `json:"provider,omitempty"`
+
+	// SourceCommits is set when the note aggregates the conversations of
+	// several original commits, e.g. on a squash-merge commit
+	SourceCommits []SourceCommit `json:"source_commits,omitempty"`
+
+	// Summary is a one-line summary of the conversation; notes stored
+	// before summaries were have none
+	Summary string `json:"summary,omitempty"`
 }
 
 // NewStoredConversation creates a new StoredConversation from transcript data
@@ -27,7 +57,7 @@ func NewStoredConversation(sessionID, projectPath, gitBranch string, messageCoun
 	}
 
 	return &StoredConversation{
-		Version:      1,
+		Version:      FormatVersion,
 		SessionID:    sessionID,
 		Timestamp:    time.Now().UTC().Format(time.RFC3339),
 		ProjectPath:  projectPath,
@@ -35,23 +65,95 @@ func NewStoredConversation(sessionID, projectPath, gitBranch string, messageCoun
 		MessageCount: messageCount,
 		Checksum:     checksum,
 		Transcript:   encoded,
+		Provider:     provider.Claude,
 	}, nil
 }
 
-// Marshal serializes the stored conversation to JSON
+// ProviderName returns the name of the transcript's provider
+func (sc *StoredConversation) ProviderName() string {
+	if sc.Provider == "" {
+		return provider.Claude
+	}
+	return sc.Provider
+}
+
+// Marshal serializes the stored conversation for a note: a readable header,
+// a blank line, then the conversation as JSON on a single line, so that
+// 'git log' shows something sensible before the payload
 func (sc *StoredConversation) Marshal() ([]byte, error) {
-	return json.MarshalIndent(sc, "", "  ")
+	payload, err := json.Marshal(sc)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	for _, line := range sc.Header() {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.Write(payload)
+	b.WriteString("\n")
+	return b.Bytes(), nil
 }
 
-// UnmarshalStoredConversation deserializes a stored conversation from JSON
+// UnmarshalStoredConversation deserializes a stored conversation from a
+// note: plain JSON for version 1, a header and JSON from version 2
 func UnmarshalStoredConversation(data []byte) (*StoredConversation, error) {
 	var sc StoredConversation
-	if err := json.Unmarshal(data, &sc); err != nil {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &sc); err != nil {
+			return nil, err
+		}
+		return &sc, nil
+	}
+
+	// The header ends at the first blank line; its lines are never empty
+	_, payload, ok := bytes.Cut(data, []byte("\n\n"))
+	if !ok {
+		return nil, fmt.Errorf("note has no header")
+	}
+	if err := json.Unmarshal(payload, &sc); err != nil {
 		return nil, err
 	}
+	if sc.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported note version %d", sc.Version)
+	}
 	return &sc, nil
 }
 
+// Header returns the readable lines written at the top of the note: who the
+// conversation was with, its session and size, and its first prompt
+func (sc *StoredConversation) Header() []string {
+	line := fmt.Sprintf("%s%s session %s, %d messages", headerPrefix, sc.ProviderName(), sc.SessionID, sc.MessageCount)
+	if sc.IsAggregate() {
+		line += fmt.Sprintf(", aggregated from %d commits", len(sc.SourceCommits))
+	}
+	header := []string{line}
+	if prompt := sc.firstPrompt(); prompt != "" {
+		header = append(header, "> "+prompt)
+	}
+	return header
+}
+
+// firstPrompt returns the first line of the first prompt, shortened to fit
+// a line of git log
+func (sc *StoredConversation) firstPrompt() string {
+	transcript, err := sc.ParseTranscript()
+	if err != nil {
+		return ""
+	}
+	for i := range transcript.Entries {
+		entry := &transcript.Entries[i]
+		if !claude.IsPrompt(entry) {
+			continue
+		}
+		if line := util.OneLine(claude.EntryText(entry), headerPromptLength); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
 // GetTranscript decompresses and returns the original transcript data
 func (sc *StoredConversation) GetTranscript() ([]byte, error) {
 	return DecodeAndDecompress(sc.Transcript)
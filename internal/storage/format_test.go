@@ -2,6 +2,7 @@ package storage
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -14,8 +15,8 @@ func TestNewStoredConversation(t *testing.T) {
 		t.Fatalf("NewStoredConversation() error: %v", err)
 	}
 
-	if sc.Version != 1 {
-		t.Errorf("Version = %d, want 1", sc.Version)
+	if sc.Version != FormatVersion {
+		t.Errorf("Version = %d, want %d", sc.Version, FormatVersion)
 	}
 	if sc.SessionID != "session-1" {
 		t.Errorf("SessionID = %q, want %q", sc.SessionID, "session-1")
@@ -122,9 +123,13 @@ func TestMarshalUnmarshalRoundTrip(t *testing.T) {
 		t.Fatalf("Marshal() error: %v", err)
 	}
 
-	// Verify it's valid JSON
-	if !json.Valid(data) {
-		t.Fatal("Marshal() produced invalid JSON")
+	// A readable header comes before the JSON
+	header, payload, ok := strings.Cut(string(data), "\n\n")
+	if !ok || !strings.HasPrefix(header, "claudit: claude session session-1, 1 messages") {
+		t.Fatalf("Marshal() should start with a header, got %q", header)
+	}
+	if !json.Valid([]byte(payload)) {
+		t.Fatal("Marshal() produced invalid JSON after the header")
 	}
 
 	restored, err := UnmarshalStoredConversation(data)
@@ -158,3 +163,65 @@ func TestUnmarshalInvalidJSON(t *testing.T) {
 		t.Error("UnmarshalStoredConversation() should fail on invalid JSON")
 	}
 }
+
+func TestUnmarshalWithoutHeader(t *testing.T) {
+	// Version 1 notes are plain, indented JSON
+	original, err := NewStoredConversation("session-1", "/test", "main", 1, []byte(`{"uuid":"1","type":"user"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original.Version = 1
+	data, err := json.MarshalIndent(original, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := UnmarshalStoredConversation(data)
+	if err != nil {
+		t.Fatalf("UnmarshalStoredConversation() error: %v", err)
+	}
+	if restored.Checksum != original.Checksum {
+		t.Errorf("Checksum mismatch")
+	}
+}
+
+func TestUnmarshalUnsupportedVersion(t *testing.T) {
+	sc, err := NewStoredConversation("session-1", "/test", "main", 1, []byte(`{"uuid":"1","type":"user"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.Version = FormatVersion + 1
+	data, err := sc.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalStoredConversation(data); err == nil || !strings.Contains(err.Error(), "unsupported note version") {
+		t.Errorf("UnmarshalStoredConversation() error = %v, want unsupported note version", err)
+	}
+
+	// A header with no payload after it isn't a note
+	if _, err := UnmarshalStoredConversation([]byte("claudit: claude session s, 1 messages\n")); err == nil {
+		t.Error("UnmarshalStoredConversation() should fail without a blank line after the header")
+	}
+}
+
+func TestHeader(t *testing.T) {
+	transcript := []byte(`{"uuid":"1","type":"user","message":{"role":"user","content":"\n  Fix the {flaky} test\nIt fails on CI"}}`)
+	sc, err := NewStoredConversation("session-1", "/test", "main", 1, transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"claudit: claude session session-1, 1 messages", "> Fix the {flaky} test"}
+	if got := sc.Header(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Header() = %q, want %q", got, want)
+	}
+
+	// Braces in the prompt don't confuse finding the JSON
+	data, err := sc.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalStoredConversation(data); err != nil {
+		t.Errorf("UnmarshalStoredConversation() error: %v", err)
+	}
+}
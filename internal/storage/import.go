@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// Ways FindImports matches a commit to a session
+const (
+	// MatchCommitOutput means the session ran git commit and the output named the commit
+	MatchCommitOutput = "commit output"
+	// MatchTimestamp means the commit was made in the repository while the session was active
+	MatchTimestamp = "timestamp"
+)
+
+// ImportMatch is a commit whose conversation was found in a session file
+type ImportMatch struct {
+	CommitSHA string
+	// SessionFile is the path of the session file
+	SessionFile string
+	// Method is how the commit was matched to the session
+	Method string
+	// Stored is the session up to the commit, ready to be written as a note
+	Stored *StoredConversation
+}
+
+// ImportAmbiguity is a commit made while more than one session was active,
+// which is left for the user to store by hand
+type ImportAmbiguity struct {
+	CommitSHA  string
+	SessionIDs []string
+}
+
+// ImportResult is what FindImports found
+type ImportResult struct {
+	// Matches are in commit order, oldest first
+	Matches   []ImportMatch
+	Ambiguous []ImportAmbiguity
+	// Sessions is the number of session files scanned
+	Sessions int
+}
+
+// commitOutputPattern matches the summary git commit prints, e.g.
+// "[main 1a2b3c4] Subject" or "[main (root-commit) 1a2b3c4] Subject"
+var commitOutputPattern = regexp.MustCompile(`\[[^\]\s]+(?: \([^)]*\))? ([0-9a-f]{7,40})\]`)
+
+// sessionFile is a Claude session file split into lines
+type sessionFile struct {
+	id    string
+	path  string
+	data  []byte
+	lines []sessionLine
+	// commits maps commits named by git commit output to the line of the result
+	commits map[string]int
+}
+
+// sessionLine is the position and context of one line of a session file
+type sessionLine struct {
+	// end is the offset just after the line, including its newline
+	end    int
+	time   time.Time
+	cwd    string
+	branch string
+}
+
+// FindImports scans the Claude session files in sessionDir for the
+// conversations of commits in the repository at projectPath.
+//
+// A commit is matched to a session when the session ran git commit and the
+// output names the commit. Otherwise, a commit is matched by time when it
+// was made within claude.DefaultIdleThreshold of the session's activity in
+// the repository, on the branch the session was on. Each match holds the
+// session up to the commit, as it would have been stored at the time.
+// Commits in skip, e.g. those that already have notes, are left out.
+func FindImports(sessionDir, projectPath string, skip map[string]bool) (*ImportResult, error) {
+	paths, err := filepath.Glob(filepath.Join(sessionDir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	var sessions []*sessionFile
+	for _, path := range paths {
+		session, err := readSessionFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read session file %s: %w", path, err)
+		}
+		sessions = append(sessions, session)
+	}
+	result.Sessions = len(sessions)
+	if len(sessions) == 0 {
+		return result, nil
+	}
+
+	// Every commit since the first session started is a candidate
+	var since time.Time
+	for _, s := range sessions {
+		if start, ok := s.start(); ok && (since.IsZero() || start.Before(since)) {
+			since = start
+		}
+	}
+	if since.IsZero() {
+		return result, nil
+	}
+	commits, err := git.LogCommits("--all", "--reverse", "--since="+since.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("could not read history: %w", err)
+	}
+
+	for _, commit := range commits {
+		if skip[commit.SHA] {
+			continue
+		}
+
+		// The session that printed the commit made it
+		var matched *ImportMatch
+		for _, s := range sessions {
+			if line, ok := s.commits[commit.SHA]; ok {
+				matched = s.match(commit.SHA, line, MatchCommitOutput, projectPath)
+				break
+			}
+		}
+
+		if matched == nil {
+			var active []*sessionFile
+			var lines []int
+			for _, s := range sessions {
+				if line, ok := s.activeAt(commit, projectPath); ok {
+					active = append(active, s)
+					lines = append(lines, line)
+				}
+			}
+			switch len(active) {
+			case 0:
+				continue
+			case 1:
+				matched = active[0].match(commit.SHA, lines[0], MatchTimestamp, projectPath)
+			default:
+				ambiguity := ImportAmbiguity{CommitSHA: commit.SHA}
+				for _, s := range active {
+					ambiguity.SessionIDs = append(ambiguity.SessionIDs, s.id)
+				}
+				result.Ambiguous = append(result.Ambiguous, ambiguity)
+				continue
+			}
+		}
+
+		if matched != nil {
+			result.Matches = append(result.Matches, *matched)
+		}
+	}
+	return result, nil
+}
+
+// readSessionFile reads a session file, noting where each line ends and the
+// commits its git commit calls made
+func readSessionFile(path string) (*sessionFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &sessionFile{
+		id:      strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		path:    path,
+		data:    data,
+		commits: make(map[string]int),
+	}
+
+	commitCalls := make(map[string]bool)
+	start := 0
+	for start < len(data) {
+		end := len(data)
+		if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+			end = start + i + 1
+		}
+		raw := bytes.TrimSpace(data[start:end])
+		start = end
+
+		var meta struct {
+			SessionID string `json:"sessionId"`
+			Timestamp string `json:"timestamp"`
+			Cwd       string `json:"cwd"`
+			GitBranch string `json:"gitBranch"`
+		}
+		var entry claude.TranscriptEntry
+		if len(raw) == 0 || json.Unmarshal(raw, &meta) != nil || json.Unmarshal(raw, &entry) != nil {
+			continue
+		}
+		line := sessionLine{end: end, cwd: meta.Cwd, branch: meta.GitBranch}
+		line.time, _ = time.Parse(time.RFC3339Nano, meta.Timestamp)
+		s.lines = append(s.lines, line)
+
+		// Resumed sessions copy earlier sessions' entries; only this
+		// session's own commits count
+		if entry.Message == nil || (meta.SessionID != "" && meta.SessionID != s.id) {
+			continue
+		}
+		for _, block := range entry.Message.Content {
+			switch block.Type {
+			case "tool_use":
+				var input struct {
+					Command string `json:"command"`
+				}
+				if block.Name == "Bash" && json.Unmarshal(block.Input, &input) == nil && git.IsCommitCommand(input.Command) {
+					commitCalls[block.ID] = true
+				}
+			case "tool_result":
+				if !commitCalls[block.ToolUseID] || block.IsError {
+					continue
+				}
+				for _, m := range commitOutputPattern.FindAllStringSubmatch(claude.ResultText(block), -1) {
+					if sha, err := git.ResolveRef(m[1] + "^{commit}"); err == nil {
+						s.commits[sha] = len(s.lines) - 1
+					}
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// start returns the time of the session's first timestamped line
+func (s *sessionFile) start() (time.Time, bool) {
+	for _, line := range s.lines {
+		if !line.time.IsZero() {
+			return line.time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// activeAt returns the session's last line before the commit was made, if
+// the session was then active in the repository on a branch the commit is on
+func (s *sessionFile) activeAt(commit git.CommitMeta, projectPath string) (int, bool) {
+	last := -1
+	for i, line := range s.lines {
+		if line.time.IsZero() {
+			continue
+		}
+		if line.time.After(commit.Date) {
+			break
+		}
+		last = i
+	}
+	if last < 0 || commit.Date.Sub(s.lines[last].time) > claude.DefaultIdleThreshold {
+		return 0, false
+	}
+
+	line := s.lines[last]
+	if line.cwd != "" && !isInside(line.cwd, projectPath) {
+		return 0, false
+	}
+	// A branch that still exists must contain the commit
+	if line.branch != "" {
+		if _, err := git.ResolveRef("refs/heads/" + line.branch); err == nil && !git.IsAncestor(commit.SHA, "refs/heads/"+line.branch) {
+			return 0, false
+		}
+	}
+	return last, true
+}
+
+// match builds the conversation stored for a commit: the session up to and
+// including the given line
+func (s *sessionFile) match(commitSHA string, line int, method, projectPath string) *ImportMatch {
+	data := s.data[:s.lines[line].end]
+	transcript, err := claude.ParseTranscript(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	stored, err := NewStoredConversation(s.id, projectPath, s.branchAt(line), transcript.MessageCount(), data)
+	if err != nil {
+		return nil
+	}
+	return &ImportMatch{CommitSHA: commitSHA, SessionFile: s.path, Method: method, Stored: stored}
+}
+
+// branchAt returns the branch the session was last on at the given line
+func (s *sessionFile) branchAt(line int) string {
+	for i := line; i >= 0; i-- {
+		if s.lines[i].branch != "" {
+			return s.lines[i].branch
+		}
+	}
+	return ""
+}
+
+// isInside returns true if path is dir or inside it
+func isInside(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+		return true
+	}
+	// Compare resolved paths too, e.g. /tmp and /private/tmp on macOS
+	resolvedPath, errPath := filepath.EvalSymlinks(path)
+	resolvedDir, errDir := filepath.EvalSymlinks(dir)
+	if errPath != nil || errDir != nil || (resolvedPath == path && resolvedDir == dir) {
+		return false
+	}
+	return isInside(resolvedPath, resolvedDir)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import "testing"
+
+func TestCommitOutputPattern(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"[main 1a2b3c4] Add parser\n 1 file changed", "1a2b3c4"},
+		{"[master (root-commit) abcdef0] Initial commit", "abcdef0"},
+		{"[feature/x 0123456789abcdef0123456789abcdef01234567] Long", "0123456789abcdef0123456789abcdef01234567"},
+		{"nothing to commit, working tree clean", ""},
+		{"[main] not a commit", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if m := commitOutputPattern.FindStringSubmatch(tt.output); m != nil {
+			got = m[1]
+		}
+		if got != tt.want {
+			t.Errorf("commit in %q = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestIsInside(t *testing.T) {
+	tests := []struct {
+		path, dir string
+		want      bool
+	}{
+		{"/repo", "/repo", true},
+		{"/repo/sub/dir", "/repo", true},
+		{"/repository", "/repo", false},
+		{"/other", "/repo", false},
+		{"/repo/../other", "/repo", false},
+	}
+	for _, tt := range tests {
+		if got := isInside(tt.path, tt.dir); got != tt.want {
+			t.Errorf("isInside(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+)
+
+// ValidateLabel checks that a label can be stored: labels are stored one per
+// line, so they can't be empty or span lines
+func ValidateLabel(label string) error {
+	if strings.TrimSpace(label) == "" {
+		return fmt.Errorf("labels can't be empty")
+	}
+	if strings.ContainsAny(label, "\r\n") {
+		return fmt.Errorf("label %q spans lines", label)
+	}
+	return nil
+}
+
+// GetLabels returns the labels of a commit's conversation, sorted
+func GetLabels(commitSHA string) ([]string, error) {
+	note, err := git.GetLabelsNote(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	return parseLabels(note), nil
+}
+
+// AllLabels returns the labels of every labelled conversation, keyed by
+// commit SHA
+func AllLabels() (map[string][]string, error) {
+	notes, err := git.ListLabelsNotes()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string][]string, len(notes))
+	for sha, note := range notes {
+		if parsed := parseLabels(note); len(parsed) > 0 {
+			labels[sha] = parsed
+		}
+	}
+	return labels, nil
+}
+
+// AddLabels adds labels to a commit's conversation and returns all of its
+// labels
+func AddLabels(commitSHA string, add []string) ([]string, error) {
+	labels, err := GetLabels(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range add {
+		if err := ValidateLabel(label); err != nil {
+			return nil, err
+		}
+		labels = append(labels, strings.TrimSpace(label))
+	}
+	labels = normalizeLabels(labels)
+	return labels, writeLabels(commitSHA, labels)
+}
+
+// RemoveLabels removes labels from a commit's conversation, or all of them
+// if none are given, and returns those left
+func RemoveLabels(commitSHA string, remove []string) ([]string, error) {
+	labels, err := GetLabels(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	var kept []string
+	if len(remove) > 0 {
+		for _, label := range labels {
+			if !HasLabel(remove, label) {
+				kept = append(kept, label)
+			}
+		}
+	}
+	return kept, writeLabels(commitSHA, kept)
+}
+
+// HasLabel returns true if labels contains label
+func HasLabel(labels []string, label string) bool {
+	label = strings.TrimSpace(label)
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// writeLabels stores a commit's labels, removing the note when none are left
+func writeLabels(commitSHA string, labels []string) error {
+	if len(labels) == 0 {
+		return git.RemoveLabelsNote(commitSHA)
+	}
+	return git.SetLabelsNote(commitSHA, []byte(strings.Join(labels, "\n")+"\n"))
+}
+
+// parseLabels reads a labels note: one label per line
+func parseLabels(note []byte) []string {
+	var labels []string
+	for _, line := range strings.Split(string(note), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			labels = append(labels, line)
+		}
+	}
+	return normalizeLabels(labels)
+}
+
+// normalizeLabels sorts labels and drops duplicates, as git's cat_sort_uniq
+// merge strategy leaves them
+func normalizeLabels(labels []string) []string {
+	sort.Strings(labels)
+	var unique []string
+	for i, label := range labels {
+		if i == 0 || label != labels[i-1] {
+			unique = append(unique, label)
+		}
+	}
+	return unique
+}
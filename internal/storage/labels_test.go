@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateLabel(t *testing.T) {
+	tests := []struct {
+		label   string
+		wantErr bool
+	}{
+		{"incident", false},
+		{"good example", false},
+		{"", true},
+		{"  ", true},
+		{"two\nlines", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateLabel(tt.label); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateLabel(%q) error = %v, wantErr %v", tt.label, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	// Notes merged with cat_sort_uniq from two clones
+	note := []byte("incident\nneeds review\n\nbug\nincident\n")
+	want := []string{"bug", "incident", "needs review"}
+	if got := parseLabels(note); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLabels() = %q, want %q", got, want)
+	}
+	if got := parseLabels(nil); got != nil {
+		t.Errorf("parseLabels(nil) = %q, want nil", got)
+	}
+}
+
+func TestHasLabel(t *testing.T) {
+	labels := []string{"bug", "needs review"}
+	if !HasLabel(labels, "needs review") {
+		t.Error("expected HasLabel to find \"needs review\"")
+	}
+	if !HasLabel(labels, " bug ") {
+		t.Error("expected HasLabel to ignore surrounding space")
+	}
+	if HasLabel(labels, "incident") {
+		t.Error("expected HasLabel not to find \"incident\"")
+	}
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/provider"
+)
+
+// RecoveredConversation is a conversation found for a commit via its
+// Claude-Session trailer rather than its own note
+type RecoveredConversation struct {
+	Stored *StoredConversation
+	// Source describes where the transcript was found, e.g. "note on abc1234"
+	Source string
+	// Verified is true if the transcript matched the Claude-Transcript-Checksum trailer
+	Verified bool
+}
+
+// RecoverConversation looks for a commit's conversation using its trailers,
+// for commits whose notes were lost (e.g. after a rebase without
+// notes.rewriteRef, or when notes were never pushed).
+//
+// Candidate transcripts are the notes of other commits made in the same
+// session and the session file in Claude's project directory. When the commit
+// carries a checksum trailer, the transcript is truncated to the exact state
+// it was in when the commit was made. Returns nil if the commit has no
+// session trailer or no candidate was found.
+func RecoverConversation(commitSHA string) (*RecoveredConversation, error) {
+	trailers, err := git.GetTrailers(commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	sessionID := trailers[git.TrailerSession]
+	if sessionID == "" {
+		return nil, nil
+	}
+	checksum := trailers[git.TrailerChecksum]
+
+	candidates, err := findSessionCandidates(commitSHA, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallback *RecoveredConversation
+	for _, c := range candidates {
+		data := c.data
+		verified := false
+		if checksum != "" {
+			n, ok := MatchChecksumPrefix(data, checksum)
+			if !ok {
+				if fallback == nil {
+					fallback = c.recovered(sessionID, data, false)
+				}
+				continue
+			}
+			data = data[:n]
+			verified = true
+		}
+		return c.recovered(sessionID, data, verified), nil
+	}
+
+	return fallback, nil
+}
+
+// sessionCandidate is a transcript that may contain a commit's conversation
+type sessionCandidate struct {
+	source      string
+	projectPath string
+	gitBranch   string
+	provider    string
+	data        []byte
+}
+
+func (c sessionCandidate) recovered(sessionID string, data []byte, verified bool) *RecoveredConversation {
+	transcript, err := provider.Parse(c.provider, data)
+	messageCount := 0
+	if err == nil {
+		messageCount = transcript.MessageCount()
+	}
+	stored, err := NewStoredConversation(sessionID, c.projectPath, c.gitBranch, messageCount, data)
+	if err != nil {
+		return nil
+	}
+	if c.provider != "" {
+		stored.Provider = c.provider
+	}
+	return &RecoveredConversation{Stored: stored, Source: c.source, Verified: verified}
+}
+
+// findSessionCandidates collects transcripts of the given session from other
+// commits' notes and from the local Claude session file
+func findSessionCandidates(commitSHA, sessionID string) ([]sessionCandidate, error) {
+	var candidates []sessionCandidate
+
+	commits, err := git.ListCommitsWithNotes()
+	if err != nil {
+		return nil, fmt.Errorf("could not list conversations: %w", err)
+	}
+	for _, sha := range commits {
+		if sha == commitSHA {
+			continue
+		}
+		stored, err := GetStoredConversation(sha)
+		if err != nil || stored == nil || stored.SessionID != sessionID {
+			continue
+		}
+		data, err := stored.GetTranscript()
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, sessionCandidate{
+			source:      "note on " + sha[:7],
+			projectPath: stored.ProjectPath,
+			gitBranch:   stored.GitBranch,
+			provider:    stored.Provider,
+			data:        data,
+		})
+	}
+
+	projectPath, err := git.GetRepoRoot()
+	if err != nil {
+		return candidates, nil
+	}
+	sessionPath, err := claude.GetSessionFilePath(projectPath, sessionID)
+	if err != nil {
+		return candidates, nil
+	}
+	if data, err := os.ReadFile(sessionPath); err == nil {
+		branch, _ := git.GetCurrentBranch()
+		candidates = append(candidates, sessionCandidate{
+			source:      "session file " + sessionPath,
+			projectPath: projectPath,
+			gitBranch:   branch,
+			data:        data,
+		})
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package summary
+
+import "os/exec"
+
+// killGroupOnCancel leaves killing cmd to its context; WaitDelay stops
+// waiting for any commands the shell started
+func killGroupOnCancel(cmd *exec.Cmd) {}
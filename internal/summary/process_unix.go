@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package summary
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killGroupOnCancel runs cmd in a process group of its own, and kills the
+// whole group when its context is done, so that commands the shell started
+// can't keep it running
+func killGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
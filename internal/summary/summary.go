@@ -0,0 +1,150 @@
+// Package summary writes the one-line summaries stored with conversations
+package summary
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+const (
+	// maxLength is the longest summary kept, in characters
+	maxLength = 200
+	// partLength is the longest the prompt and reply are quoted in an
+	// offline summary
+	partLength = 60
+	// maxNamed is how many files and tools an offline summary names
+	maxNamed = 3
+)
+
+// commandTimeout bounds how long a summarizer command may run, leaving the
+// rest of the store hook's 30 seconds to store the conversation
+var commandTimeout = 20 * time.Second
+
+// Summarizer writes a one-line summary of a stored conversation
+type Summarizer interface {
+	Summarize(stored *storage.StoredConversation) (string, error)
+}
+
+// New returns the summarizer for the given command, or the offline one if
+// there is none
+func New(command string) Summarizer {
+	if command == "" {
+		return Offline{}
+	}
+	return Command{Command: command}
+}
+
+// Of returns the summary stored with a conversation, or an offline one for
+// conversations stored before summaries were
+func Of(stored *storage.StoredConversation) string {
+	if stored.Summary != "" {
+		return stored.Summary
+	}
+	s, err := Offline{}.Summarize(stored)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// OrDefault returns the summary of a conversation as Of does, or fallback
+// if there is none
+func OrDefault(stored *storage.StoredConversation, fallback string) string {
+	if s := Of(stored); s != "" {
+		return s
+	}
+	return fallback
+}
+
+// Offline summarizes a conversation from its digest: the first prompt, the
+// files edited, the tools used most and the assistant's final message
+type Offline struct{}
+
+// Summarize implements Summarizer
+func (Offline) Summarize(stored *storage.StoredConversation) (string, error) {
+	transcript, err := stored.ParseTranscript()
+	if err != nil {
+		return "", fmt.Errorf("could not parse transcript: %w", err)
+	}
+	digest := claude.NewDigest(transcript.Entries)
+	digest.RelativizeFiles(stored.ProjectPath)
+
+	var parts []string
+	if len(digest.Prompts) > 0 {
+		parts = append(parts, util.OneLine(digest.Prompts[0], partLength))
+	}
+	if len(digest.Files) > 0 {
+		parts = append(parts, "edited "+named(digest.Files))
+	}
+	if len(digest.Tools) > 0 {
+		var tools []string
+		for _, tool := range digest.Tools {
+			tools = append(tools, fmt.Sprintf("%s ×%d", tool.Name, tool.Count))
+		}
+		parts = append(parts, named(tools))
+	}
+	if digest.Summary != "" {
+		parts = append(parts, util.OneLine(digest.Summary, partLength))
+	}
+	return util.Truncate(strings.Join(parts, " · "), maxLength), nil
+}
+
+// Command summarizes a conversation with a shell command, which is given
+// the transcript on stdin and the session's ID and provider in
+// $CLAUDIT_SESSION_ID and $CLAUDIT_PROVIDER, and prints the summary
+type Command struct {
+	Command string
+}
+
+// Summarize implements Summarizer
+func (c Command) Summarize(stored *storage.StoredConversation) (string, error) {
+	data, err := stored.GetTranscript()
+	if err != nil {
+		return "", fmt.Errorf("could not decompress transcript: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	killGroupOnCancel(cmd)
+	// Don't wait on output held open by anything that survived the kill
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(),
+		"CLAUDIT_SESSION_ID="+stored.SessionID,
+		"CLAUDIT_PROVIDER="+stored.ProviderName(),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("summarizer %q failed: %w: %s", c.Command, err, util.FirstLine(msg))
+		}
+		return "", fmt.Errorf("summarizer %q failed: %w", c.Command, err)
+	}
+
+	summary := strings.Join(strings.Fields(string(output)), " ")
+	if summary == "" {
+		return "", errors.New("summarizer printed nothing")
+	}
+	return util.Truncate(summary, maxLength), nil
+}
+
+// named lists up to maxNamed names, counting the rest
+func named(names []string) string {
+	if len(names) <= maxNamed {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxNamed], ", "), len(names)-maxNamed)
+}
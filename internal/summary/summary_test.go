@@ -0,0 +1,104 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/storage"
+)
+
+const transcript = `{"uuid":"u1","type":"user","message":{"role":"user","content":"Fix the flaky test\nIt fails on CI"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/repo/clock.go","old_string":"a","new_string":"b"}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test"}},{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"uuid":"a2","parentUuid":"a1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Mocked the clock.\n\nThe test passes now."}]}}
+`
+
+func newStored(t *testing.T) *storage.StoredConversation {
+	t.Helper()
+	stored, err := storage.NewStoredConversation("session-1", "/repo", "main", 3, []byte(transcript))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+func TestOffline(t *testing.T) {
+	got, err := Offline{}.Summarize(newStored(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Fix the flaky test · edited clock.go · Bash ×2, Edit ×1 · Mocked the clock."
+	if got != want {
+		t.Errorf("Summarize() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr string
+	}{
+		{name: "reads the transcript", command: `echo "$CLAUDIT_SESSION_ID: $(wc -l)"`, want: "session-1: 3"},
+		{name: "joins lines", command: "printf 'Fixed\\n  the test\\n'", want: "Fixed the test"},
+		{name: "fails", command: "echo broken >&2; exit 1", wantErr: "broken"},
+		{name: "prints nothing", command: "true", wantErr: "printed nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.command).Summarize(newStored(t))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Summarize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { commandTimeout = timeout }(commandTimeout)
+	commandTimeout = 200 * time.Millisecond
+
+	// The sleep holds the shell's output open after the shell is killed
+	start := time.Now()
+	_, err := New("sleep 5 | cat").Summarize(newStored(t))
+	if err == nil {
+		t.Error("want an error when the summarizer times out")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Summarize() took %s, want it stopped at the timeout", elapsed)
+	}
+}
+
+func TestOf(t *testing.T) {
+	stored := newStored(t)
+	if got := Of(stored); !strings.HasPrefix(got, "Fix the flaky test") {
+		t.Errorf("without a stored summary, want an offline one, got %q", got)
+	}
+	stored.Summary = "Stored"
+	if got := Of(stored); got != "Stored" {
+		t.Errorf("Of() = %q, want the stored summary", got)
+	}
+}
+
+func TestOrDefault(t *testing.T) {
+	if got := OrDefault(newStored(t), "Restored"); !strings.HasPrefix(got, "Fix the flaky test") {
+		t.Errorf("OrDefault() = %q, want the conversation's summary", got)
+	}
+	empty, err := storage.NewStoredConversation("session-2", "/repo", "main", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := OrDefault(empty, "Restored"); got != "Restored" {
+		t.Errorf("OrDefault() = %q, want the fallback", got)
+	}
+}
@@ -0,0 +1,96 @@
+package termimage
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// levels is the number of levels of each of red, green and blue in the
+// palette images are reduced to for sixel
+const levels = 6
+
+// writeSixel draws an image as sixel graphics, in a palette of
+// levels×levels×levels colors. Transparent pixels are left unpainted.
+func writeSixel(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// pixels holds each pixel's palette index, or -1 if it's transparent
+	pixels := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if a < 0x8000 {
+				pixels[y*width+x] = -1
+				continue
+			}
+			pixels[y*width+x] = (level(r)*levels+level(g))*levels + level(b)
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	// P2=1 keeps unpainted pixels transparent; the raster attributes set a
+	// 1:1 aspect ratio and the image's size
+	_, _ = fmt.Fprintf(out, "\033P0;1;0q\"1;1;%d;%d", width, height)
+	for i := 0; i < levels*levels*levels; i++ {
+		r, g, b := i/(levels*levels), i/levels%levels, i%levels
+		_, _ = fmt.Fprintf(out, "#%d;2;%d;%d;%d", i, r*100/(levels-1), g*100/(levels-1), b*100/(levels-1))
+	}
+
+	row := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		// Each band of six rows is drawn one color at a time
+		used := make(map[int]bool)
+		for y := top; y < top+6 && y < height; y++ {
+			for x := 0; x < width; x++ {
+				if c := pixels[y*width+x]; c >= 0 {
+					used[c] = true
+				}
+			}
+		}
+		for c := 0; c < levels*levels*levels; c++ {
+			if !used[c] {
+				continue
+			}
+			for x := 0; x < width; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if pixels[(top+dy)*width+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = byte('?' + bits)
+			}
+			_, _ = fmt.Fprintf(out, "#%d%s$", c, runLength(row))
+		}
+		_, _ = out.WriteString("-")
+	}
+	_, _ = out.WriteString("\033\\\n")
+	return out.Flush()
+}
+
+// level maps a 16-bit color component to one of the palette's levels
+func level(component uint32) int {
+	return int((component*(levels-1) + 0x7fff) / 0xffff)
+}
+
+// runLength compresses repeated sixels as "!<count><sixel>"
+func runLength(row []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			_, _ = fmt.Fprintf(&b, "!%d%c", n, row[i])
+		} else {
+			b.WriteString(strings.Repeat(string(row[i]), n))
+		}
+		i = j
+	}
+	return b.String()
+}
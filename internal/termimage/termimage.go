@@ -0,0 +1,138 @@
+// Package termimage draws images inline in terminals that support the kitty
+// graphics protocol or sixel.
+package termimage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for decoding
+	_ "image/jpeg" // register JPEG for decoding
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// Protocol is a way of drawing images in a terminal
+type Protocol int
+
+const (
+	// None means images can't be drawn
+	None Protocol = iota
+	Kitty
+	Sixel
+)
+
+// cellWidth is the assumed width of a terminal cell in pixels, used to size
+// images in columns
+const cellWidth = 10
+
+// kittyChunk is the most base64 data kitty accepts in one escape sequence
+const kittyChunk = 4096
+
+// maxPixels bounds the size of the images drawn. Decoding allocates memory
+// for every pixel an image declares, however small its file.
+const maxPixels = 25_000_000
+
+// Fits returns true if an image of the given size is small enough to draw
+func Fits(width, height int) bool {
+	return width > 0 && height > 0 && int64(width)*int64(height) <= maxPixels
+}
+
+// Detect returns the protocol the terminal supports, going by the variables
+// terminals set. Terminal multiplexers don't pass images through, so none is
+// used inside tmux or screen.
+func Detect() Protocol {
+	term := os.Getenv("TERM")
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") {
+		return None
+	}
+	switch {
+	case term == "xterm-kitty" || os.Getenv("KITTY_WINDOW_ID") != "":
+		return Kitty
+	case os.Getenv("TERM_PROGRAM") == "WezTerm" || os.Getenv("TERM_PROGRAM") == "ghostty":
+		return Kitty
+	case strings.Contains(term, "sixel") || term == "foot" || strings.HasPrefix(term, "mlterm"):
+		return Sixel
+	}
+	return None
+}
+
+// Write draws an image, scaled down to at most maxColumns wide, followed by
+// a newline
+func Write(w io.Writer, p Protocol, data []byte, maxColumns int) error {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("could not decode image: %w", err)
+	}
+	if !Fits(config.Width, config.Height) {
+		return fmt.Errorf("image is too large to draw (%d×%d)", config.Width, config.Height)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("could not decode image: %w", err)
+	}
+	columns := (img.Bounds().Dx() + cellWidth - 1) / cellWidth
+	if maxColumns > 0 && columns > maxColumns {
+		columns = maxColumns
+	}
+
+	switch p {
+	case Kitty:
+		if format != "png" {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, img); err != nil {
+				return fmt.Errorf("could not encode image: %w", err)
+			}
+			data = buf.Bytes()
+		}
+		return writeKitty(w, data, columns)
+	case Sixel:
+		return writeSixel(w, scale(img, columns*cellWidth))
+	}
+	return nil
+}
+
+// writeKitty sends a PNG to kitty in chunks, to be shown columns wide
+func writeKitty(w io.Writer, data []byte, columns int) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for first := true; first || encoded != ""; first = false {
+		chunk := encoded
+		if len(chunk) > kittyChunk {
+			chunk = chunk[:kittyChunk]
+		}
+		encoded = encoded[len(chunk):]
+
+		more := 0
+		if encoded != "" {
+			more = 1
+		}
+		control := fmt.Sprintf("m=%d", more)
+		if first {
+			control = fmt.Sprintf("a=T,f=100,c=%d,%s", columns, control)
+		}
+		if _, err := fmt.Fprintf(w, "\033_G%s;%s\033\\", control, chunk); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// scale shrinks an image to at most width pixels wide, keeping its aspect
+func scale(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width <= 0 || bounds.Dx() <= width {
+		return img
+	}
+	height := max(bounds.Dy()*width/bounds.Dx(), 1)
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			scaled.Set(x, y, img.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+	return scaled
+}
@@ -0,0 +1,103 @@
+package termimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	for _, tt := range []struct {
+		term, termProgram, tmux string
+		want                    Protocol
+	}{
+		{term: "xterm-kitty", want: Kitty},
+		{term: "xterm-256color", termProgram: "WezTerm", want: Kitty},
+		{term: "foot", want: Sixel},
+		{term: "xterm-sixel", want: Sixel},
+		{term: "xterm-256color", want: None},
+		{term: "xterm-kitty", tmux: "/tmp/tmux-0/default", want: None},
+	} {
+		t.Setenv("TERM", tt.term)
+		t.Setenv("TERM_PROGRAM", tt.termProgram)
+		t.Setenv("TMUX", tt.tmux)
+		t.Setenv("KITTY_WINDOW_ID", "")
+		if got := Detect(); got != tt.want {
+			t.Errorf("Detect() with TERM=%s TERM_PROGRAM=%s TMUX=%s = %v, want %v", tt.term, tt.termProgram, tt.tmux, got, tt.want)
+		}
+	}
+}
+
+func TestWriteKitty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Kitty, testPNG(t, 200, 100), 10); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\033_Ga=T,f=100,c=10,m=0;") || !strings.HasSuffix(out, "\033\\\n") {
+		t.Errorf("unexpected kitty output %q", out)
+	}
+}
+
+func TestWriteSixel(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Sixel, testPNG(t, 8, 7), 0); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\033P0;1;0q\"1;1;8;7") || !strings.HasSuffix(out, "\033\\\n") {
+		t.Errorf("unexpected sixel output %q", out)
+	}
+	// The red top row is the first sixel of the first band, in color 180
+	// (red at full level, no green or blue)
+	if !strings.Contains(out, "#180!8@$") {
+		t.Errorf("sixel output should draw the red row, got %q", out)
+	}
+	if strings.Count(out, "-") != 2 {
+		t.Errorf("7 rows should take two bands, got %q", out)
+	}
+}
+
+func TestWriteInvalid(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, Kitty, []byte("not an image"), 0); err == nil {
+		t.Error("want an error for data that isn't an image")
+	}
+}
+
+func TestWriteTooLarge(t *testing.T) {
+	// A tiny PNG whose header declares 100000×100000 pixels
+	data := testPNG(t, 1, 1)
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	var buf bytes.Buffer
+	err := Write(&buf, Kitty, data, 0)
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Write() error = %v, want too large", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Write() wrote %q", buf.String())
+	}
+	if Fits(100000, 100000) {
+		t.Error("Fits(100000, 100000) = true")
+	}
+}
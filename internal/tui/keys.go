@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// escapeKeys names the escape sequences of special keys, in both the normal
+// and application cursor modes
+var escapeKeys = map[string]string{
+	"\033[A":  "up",
+	"\033[B":  "down",
+	"\033[C":  "right",
+	"\033[D":  "left",
+	"\033OA":  "up",
+	"\033OB":  "down",
+	"\033OC":  "right",
+	"\033OD":  "left",
+	"\033[H":  "home",
+	"\033[F":  "end",
+	"\033OH":  "home",
+	"\033OF":  "end",
+	"\033[1~": "home",
+	"\033[4~": "end",
+	"\033[5~": "pgup",
+	"\033[6~": "pgdown",
+	"\033[Z":  "shift+tab",
+}
+
+// controlKeys names the control characters the TUI uses
+var controlKeys = map[byte]string{
+	'\r':   "enter",
+	'\n':   "enter",
+	'\t':   "tab",
+	0x7f:   "backspace",
+	0x08:   "backspace",
+	0x03:   "ctrl+c",
+	0x04:   "ctrl+d",
+	0x15:   "ctrl+u",
+	0x06:   "ctrl+f",
+	0x02:   "ctrl+b",
+	'\033': "esc",
+}
+
+// parseKeys splits terminal input into key names: "up", "enter", "ctrl+c"
+// and so on for special keys, and the character itself for others
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		if input[0] == '\033' && len(input) > 1 {
+			if key, n := parseEscape(input); n > 0 {
+				if key != "" {
+					keys = append(keys, key)
+				}
+				input = input[n:]
+				continue
+			}
+		}
+		if key, ok := controlKeys[input[0]]; ok {
+			keys = append(keys, key)
+			input = input[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(input)
+		if r != utf8.RuneError && r >= ' ' {
+			keys = append(keys, string(r))
+		}
+		input = input[size:]
+	}
+	return keys
+}
+
+// parseEscape parses the escape sequence at the start of input, returning
+// its name and length. Unknown sequences are skipped with an empty name.
+func parseEscape(input []byte) (string, int) {
+	s := string(input)
+	for seq, key := range escapeKeys {
+		if strings.HasPrefix(s, seq) {
+			return key, len(seq)
+		}
+	}
+	if input[1] != '[' && input[1] != 'O' {
+		return "", 0
+	}
+	// Skip to the final byte of an unknown sequence
+	for i := 2; i < len(input); i++ {
+		if input[i] >= 0x40 && input[i] <= 0x7e {
+			return "", i + 1
+		}
+	}
+	return "", len(input)
+}
@@ -0,0 +1,491 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+	"github.com/DanielJonesEB/claudit/internal/provider"
+)
+
+// pane is a part of the screen that takes keys
+type pane int
+
+const (
+	commitPane pane = iota
+	conversationPane
+)
+
+// Layout limits
+const (
+	maxCommitPaneWidth = 48
+	minCommitPaneWidth = 20
+	// gutterWidth holds the cursor and the expand/collapse marker
+	gutterWidth = 2
+)
+
+// item is a part of a conversation laid out as a unit: the label of an
+// entry, a content block, or the blank line between entries
+type item struct {
+	entry *claude.TranscriptEntry
+	// block is nil for labels and blank lines
+	block *claude.ContentBlock
+	// collapsible items are shown as one line until expanded
+	collapsible bool
+	// text is everything the item shows when expanded, for searching
+	text string
+}
+
+// line is a line of the laid out conversation
+type line struct {
+	text string
+	item int
+}
+
+// model is the state of the TUI. It is driven by key names and draws into
+// lines of text, so it can be tested without a terminal.
+type model struct {
+	commits []Commit
+	load    Loader
+
+	// selected is the commit shown; commitTop the first one on screen
+	selected, commitTop int
+	focus               pane
+	full                bool
+
+	conv     *Conversation
+	loadErr  error
+	items    []item
+	expanded map[int]bool
+	lines    []line
+	// cursor is the line the cursor is on; top the first one on screen
+	cursor, top int
+
+	// query is the search; input the one being typed while searching
+	query     string
+	input     string
+	searching bool
+	// confirming is set while asking whether to resume the session
+	confirming bool
+	status     string
+
+	width, height int
+
+	quit   bool
+	action Action
+	// diff is a commit whose diff was asked for, shown outside the TUI
+	diff string
+}
+
+func newModel(commits []Commit, load Loader) *model {
+	m := &model{commits: commits, load: load, width: 80, height: 24}
+	m.loadConversation()
+	return m
+}
+
+// resize lays the conversation out again for a new terminal size
+func (m *model) resize(width, height int) {
+	if width == m.width && height == m.height {
+		return
+	}
+	m.width, m.height = width, height
+	m.layout()
+}
+
+func (m *model) commitPaneWidth() int {
+	w := m.width / 3
+	if w > maxCommitPaneWidth {
+		w = maxCommitPaneWidth
+	}
+	if w < minCommitPaneWidth {
+		w = minCommitPaneWidth
+	}
+	return w
+}
+
+// conversationWidth is the width of the conversation pane, after the
+// separator between the panes
+func (m *model) conversationWidth() int {
+	return m.width - m.commitPaneWidth() - 1
+}
+
+// bodyHeight is the height of the panes, between the title and status bars
+func (m *model) bodyHeight() int {
+	if m.height < 3 {
+		return 1
+	}
+	return m.height - 2
+}
+
+// loadConversation loads the selected commit's conversation
+func (m *model) loadConversation() {
+	m.conv, m.loadErr = nil, nil
+	m.items, m.lines = nil, nil
+	m.expanded = make(map[int]bool)
+	m.cursor, m.top = 0, 0
+	if len(m.commits) == 0 {
+		return
+	}
+
+	m.conv, m.loadErr = m.load(m.commits[m.selected].SHA, m.full)
+	if m.loadErr != nil {
+		return
+	}
+	m.buildItems()
+	m.layout()
+}
+
+// buildItems splits the conversation into items
+func (m *model) buildItems() {
+	var buf bytes.Buffer
+	r := claude.NewRenderer(&buf)
+	r.SetFull(true)
+
+	for i := range m.conv.Entries {
+		entry := &m.conv.Entries[i]
+		switch entry.Type {
+		case claude.MessageTypeUser, claude.MessageTypeAssistant, claude.MessageTypeSystem:
+		default:
+			continue
+		}
+		if len(m.items) > 0 {
+			m.items = append(m.items, item{entry: entry})
+		}
+
+		buf.Reset()
+		r.RenderLabel(entry)
+		m.items = append(m.items, item{entry: entry, text: stripANSI(buf.String())})
+		if entry.Message == nil {
+			continue
+		}
+		for j := range entry.Message.Content {
+			block := &entry.Message.Content[j]
+			buf.Reset()
+			r.RenderBlock(entry, *block)
+			if buf.Len() == 0 {
+				continue
+			}
+			m.items = append(m.items, item{
+				entry:       entry,
+				block:       block,
+				collapsible: block.Type != "text",
+				text:        stripANSI(buf.String()),
+			})
+		}
+	}
+}
+
+// layout renders the items into lines for the width of the conversation
+// pane, keeping the cursor on the same line of the same item
+func (m *model) layout() {
+	anchor, offset := -1, 0
+	if m.cursor < len(m.lines) {
+		anchor = m.lines[m.cursor].item
+		offset = m.cursor - m.firstLine(anchor)
+	}
+
+	var buf bytes.Buffer
+	r := claude.NewRenderer(&buf)
+	r.SetFull(true)
+	width := m.conversationWidth() - gutterWidth
+
+	m.lines = nil
+	for i, it := range m.items {
+		buf.Reset()
+		switch {
+		case it.block == nil && it.text == "":
+			buf.WriteString("\n")
+		case it.block == nil:
+			r.RenderLabel(it.entry)
+		case it.collapsible && !m.expanded[i]:
+			r.RenderCollapsed(*it.block)
+		default:
+			r.RenderBlock(it.entry, *it.block)
+		}
+		for _, text := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			for _, wrapped := range wrap(sanitize(text), width) {
+				m.lines = append(m.lines, line{text: wrapped, item: i})
+			}
+		}
+	}
+
+	m.cursor = 0
+	if anchor >= 0 {
+		first := m.firstLine(anchor)
+		m.cursor = first
+		for m.cursor < len(m.lines)-1 && m.cursor-first < offset && m.lines[m.cursor+1].item == anchor {
+			m.cursor++
+		}
+	}
+	m.scrollToCursor()
+}
+
+// firstLine returns the first line of an item
+func (m *model) firstLine(item int) int {
+	for i, l := range m.lines {
+		if l.item == item {
+			return i
+		}
+	}
+	return 0
+}
+
+// scrollToCursor scrolls the conversation so the cursor is on screen
+func (m *model) scrollToCursor() {
+	height := m.bodyHeight()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+height {
+		m.top = m.cursor - height + 1
+	}
+	if max := len(m.lines) - height; m.top > max {
+		m.top = max
+	}
+	if m.top < 0 {
+		m.top = 0
+	}
+}
+
+// handleKey updates the model for a key press
+func (m *model) handleKey(key string) {
+	switch {
+	case m.searching:
+		m.handleSearchKey(key)
+		return
+	case m.confirming:
+		m.confirming = false
+		m.status = ""
+		if key == "y" || key == "Y" {
+			m.action = Action{Resume: m.commits[m.selected].SHA}
+			m.quit = true
+		}
+		return
+	}
+	m.status = ""
+
+	switch key {
+	case "q", "ctrl+c":
+		m.quit = true
+	case "tab", "shift+tab":
+		if m.focus == commitPane {
+			m.focus = conversationPane
+		} else {
+			m.focus = commitPane
+		}
+	case "i":
+		m.full = !m.full
+		m.loadConversation()
+	case "e":
+		m.toggleAll()
+	case "/":
+		m.searching = true
+		m.input = ""
+	case "n":
+		m.findNext(1)
+	case "N":
+		m.findNext(-1)
+	case "d":
+		if len(m.commits) > 0 {
+			m.diff = m.commits[m.selected].SHA
+		}
+	case "r":
+		if m.conv != nil && m.conv.Provider != "" && m.conv.Provider != provider.Claude {
+			m.status = "only Claude Code sessions can be resumed"
+		} else if len(m.commits) > 0 {
+			m.confirming = true
+		}
+	default:
+		if m.focus == commitPane {
+			m.handleCommitKey(key)
+		} else {
+			m.handleConversationKey(key)
+		}
+	}
+}
+
+func (m *model) handleCommitKey(key string) {
+	selected := m.selected
+	switch key {
+	case "j", "down":
+		selected++
+	case "k", "up":
+		selected--
+	case "pgdown", "ctrl+d", "ctrl+f", " ":
+		selected += m.bodyHeight()
+	case "pgup", "ctrl+u", "ctrl+b":
+		selected -= m.bodyHeight()
+	case "g", "home":
+		selected = 0
+	case "G", "end":
+		selected = len(m.commits) - 1
+	case "enter", "l", "right":
+		m.focus = conversationPane
+		return
+	}
+	if selected >= len(m.commits) {
+		selected = len(m.commits) - 1
+	}
+	if selected < 0 {
+		selected = 0
+	}
+	if selected == m.selected {
+		return
+	}
+
+	m.selected = selected
+	if m.selected < m.commitTop {
+		m.commitTop = m.selected
+	}
+	if m.selected >= m.commitTop+m.bodyHeight() {
+		m.commitTop = m.selected - m.bodyHeight() + 1
+	}
+	m.loadConversation()
+}
+
+func (m *model) handleConversationKey(key string) {
+	switch key {
+	case "j", "down":
+		m.cursor++
+	case "k", "up":
+		m.cursor--
+	case "pgdown", "ctrl+f", " ":
+		m.cursor += m.bodyHeight()
+		m.top += m.bodyHeight()
+	case "pgup", "ctrl+b":
+		m.cursor -= m.bodyHeight()
+		m.top -= m.bodyHeight()
+	case "ctrl+d":
+		m.cursor += m.bodyHeight() / 2
+		m.top += m.bodyHeight() / 2
+	case "ctrl+u":
+		m.cursor -= m.bodyHeight() / 2
+		m.top -= m.bodyHeight() / 2
+	case "g", "home":
+		m.cursor = 0
+	case "G", "end":
+		m.cursor = len(m.lines) - 1
+	case "enter":
+		m.toggle()
+	case "h", "left", "esc":
+		m.focus = commitPane
+	}
+	if m.cursor >= len(m.lines) {
+		m.cursor = len(m.lines) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.scrollToCursor()
+}
+
+// toggle expands or collapses the item under the cursor
+func (m *model) toggle() {
+	if m.cursor >= len(m.lines) {
+		return
+	}
+	i := m.lines[m.cursor].item
+	if !m.items[i].collapsible {
+		return
+	}
+	m.expanded[i] = !m.expanded[i]
+	// Collapsing moves the cursor to the item's only line
+	m.cursor = m.firstLine(i)
+	m.layout()
+}
+
+// toggleAll expands every item, or collapses them all if they already are
+func (m *model) toggleAll() {
+	all := true
+	for i, it := range m.items {
+		if it.collapsible && !m.expanded[i] {
+			all = false
+		}
+	}
+	for i, it := range m.items {
+		if it.collapsible {
+			m.expanded[i] = !all
+		}
+	}
+	m.layout()
+}
+
+func (m *model) handleSearchKey(key string) {
+	switch key {
+	case "enter":
+		m.searching = false
+		m.query = m.input
+		if m.query != "" {
+			m.findNext(1)
+		}
+	case "esc", "ctrl+c":
+		m.searching = false
+	case "backspace":
+		if runes := []rune(m.input); len(runes) > 0 {
+			m.input = string(runes[:len(runes)-1])
+		}
+	default:
+		if len([]rune(key)) == 1 {
+			m.input += key
+		}
+	}
+}
+
+// findNext moves the cursor to the next item matching the search in the
+// given direction, expanding it if needed
+func (m *model) findNext(dir int) {
+	if m.query == "" {
+		m.status = "no search; press / to search"
+		return
+	}
+	var matches []int
+	for i, it := range m.items {
+		if containsFold(it.text, m.query) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		m.status = fmt.Sprintf("no matches for %q", m.query)
+		return
+	}
+
+	current := -1
+	if m.cursor < len(m.lines) {
+		current = m.lines[m.cursor].item
+	}
+	next := 0
+	if dir > 0 {
+		next = 0
+		for j, match := range matches {
+			if match > current {
+				next = j
+				break
+			}
+		}
+	} else {
+		next = len(matches) - 1
+		for j := len(matches) - 1; j >= 0; j-- {
+			if matches[j] < current {
+				next = j
+				break
+			}
+		}
+	}
+
+	target := matches[next]
+	if m.items[target].collapsible && !m.expanded[target] {
+		m.expanded[target] = true
+		m.layout()
+	}
+	m.cursor = m.firstLine(target)
+	for i := m.cursor; i < len(m.lines) && m.lines[i].item == target; i++ {
+		if containsFold(stripANSI(m.lines[i].text), m.query) {
+			m.cursor = i
+			break
+		}
+	}
+	m.focus = conversationPane
+	m.scrollToCursor()
+	m.status = fmt.Sprintf("match %d of %d for %q", next+1, len(matches), m.query)
+}
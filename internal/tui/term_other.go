@@ -0,0 +1,28 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package tui
+
+import (
+	"errors"
+	"os"
+)
+
+// terminal is the terminal the TUI draws on
+type terminal struct {
+	in, out *os.File
+}
+
+// openTerminal fails: raw mode is only implemented for Unix terminals
+func openTerminal() (*terminal, error) {
+	return nil, errors.New("claudit tui is not supported on this platform")
+}
+
+func (t *terminal) raw() error { return nil }
+
+func (t *terminal) restore() error { return nil }
+
+func (t *terminal) read(buf []byte) (int, error) { return 0, errors.New("not a terminal") }
+
+func (t *terminal) size() (int, int, error) { return 0, 0, errors.New("not a terminal") }
+
+func notifyResize(ch chan<- os.Signal) {}
@@ -0,0 +1,81 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package tui
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal is the terminal the TUI draws on
+type terminal struct {
+	in, out *os.File
+	// saved is the state to restore when leaving raw mode
+	saved *unix.Termios
+}
+
+// openTerminal returns the terminal on stdin and stdout, failing if either is
+// not a terminal
+func openTerminal() (*terminal, error) {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		if _, err := unix.IoctlGetTermios(int(f.Fd()), ioctlGetTermios); err != nil {
+			return nil, errors.New("claudit tui needs a terminal")
+		}
+	}
+	return &terminal{in: os.Stdin, out: os.Stdout}, nil
+}
+
+// raw puts the terminal in raw mode. Reads return after a tenth of a second
+// without input, so the TUI can notice resizes.
+func (t *terminal) raw() error {
+	fd := int(t.in.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return err
+	}
+	t.saved = saved
+
+	raw := *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 0
+	raw.Cc[unix.VTIME] = 1
+	return unix.IoctlSetTermios(fd, ioctlSetTermios, &raw)
+}
+
+// restore leaves raw mode
+func (t *terminal) restore() error {
+	if t.saved == nil {
+		return nil
+	}
+	return unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, t.saved)
+}
+
+// read reads key presses, returning no bytes when there were none within a
+// tenth of a second
+func (t *terminal) read(buf []byte) (int, error) {
+	n, err := unix.Read(int(t.in.Fd()), buf)
+	if err == unix.EINTR || err == unix.EAGAIN {
+		return 0, nil
+	}
+	return n, err
+}
+
+// size returns the width and height of the terminal
+func (t *terminal) size() (int, int, error) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// notifyResize sends to ch when the terminal is resized
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, unix.SIGWINCH)
+}
@@ -0,0 +1,238 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Escape sequences the TUI draws with
+const (
+	styleReset   = "\033[0m"
+	styleBold    = "\033[1m"
+	styleDim     = "\033[2m"
+	styleReverse = "\033[7m"
+	styleNoRev   = "\033[27m"
+	styleYellow  = "\033[33m"
+	styleRed     = "\033[31m"
+)
+
+// token is an SGR escape sequence or a visible rune of a line
+type token struct {
+	esc string
+	r   rune
+}
+
+// tokenize splits a line into escape sequences and visible runes
+func tokenize(line string) []token {
+	var tokens []token
+	for i := 0; i < len(line); {
+		if line[i] == '\033' && i+1 < len(line) && line[i+1] == '[' {
+			j := i + 2
+			for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+				j++
+			}
+			if j < len(line) {
+				j++
+			}
+			tokens = append(tokens, token{esc: line[i:j]})
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		tokens = append(tokens, token{r: r})
+		i += size
+	}
+	return tokens
+}
+
+// sanitize keeps a line's colors but drops other escape sequences and control
+// characters, e.g. from command output in tool results, and expands tabs
+func sanitize(line string) string {
+	var b strings.Builder
+	for _, t := range tokenize(line) {
+		switch {
+		case t.esc != "":
+			if strings.HasSuffix(t.esc, "m") {
+				b.WriteString(t.esc)
+			}
+		case t.r == '\t':
+			b.WriteString("    ")
+		case t.r == '\033' || unicode.IsControl(t.r):
+		default:
+			b.WriteRune(t.r)
+		}
+	}
+	return b.String()
+}
+
+// stripANSI returns the visible text of a line
+func stripANSI(line string) string {
+	var b strings.Builder
+	for _, t := range tokenize(line) {
+		if t.esc == "" {
+			b.WriteRune(t.r)
+		}
+	}
+	return b.String()
+}
+
+// isReset returns true for escape sequences that turn off all styles
+func isReset(esc string) bool {
+	return esc == styleReset || esc == "\033[m"
+}
+
+// wrap breaks a line into lines of at most width visible runes, at spaces
+// where possible. Continuation lines keep the line's indentation and colors.
+func wrap(line string, width int) []string {
+	tokens := tokenize(line)
+	var visible []rune
+	for _, t := range tokens {
+		if t.esc == "" {
+			visible = append(visible, t.r)
+		}
+	}
+	if width <= 0 || len(visible) <= width {
+		return []string{line}
+	}
+
+	indent := 0
+	for indent < len(visible) && indent < width/2 && visible[indent] == ' ' {
+		indent++
+	}
+
+	// breaks are the visible offsets each wrapped line starts at
+	breaks := []int{0}
+	for start, avail := 0, width; len(visible)-start > avail; avail = width - indent {
+		end := start + avail
+		for i := end; i > start+1; i-- {
+			if visible[i-1] == ' ' {
+				end = i
+				break
+			}
+		}
+		breaks = append(breaks, end)
+		start = end
+	}
+
+	var lines []string
+	var b strings.Builder
+	active := ""
+	pos, next := 0, 1
+	for _, t := range tokens {
+		if t.esc != "" {
+			b.WriteString(t.esc)
+			if isReset(t.esc) {
+				active = ""
+			} else {
+				active += t.esc
+			}
+			continue
+		}
+		if next < len(breaks) && pos == breaks[next] {
+			if active != "" {
+				b.WriteString(styleReset)
+			}
+			lines = append(lines, b.String())
+			b.Reset()
+			b.WriteString(strings.Repeat(" ", indent))
+			b.WriteString(active)
+			next++
+		}
+		b.WriteRune(t.r)
+		pos++
+	}
+	return append(lines, b.String())
+}
+
+// fit truncates or pads a line to exactly width visible runes
+func fit(line string, width int) string {
+	var b strings.Builder
+	n := 0
+	styled := false
+	for _, t := range tokenize(line) {
+		if t.esc != "" {
+			b.WriteString(t.esc)
+			styled = !isReset(t.esc)
+			continue
+		}
+		if n == width {
+			break
+		}
+		b.WriteRune(t.r)
+		n++
+	}
+	if styled {
+		b.WriteString(styleReset)
+	}
+	if n < width {
+		b.WriteString(strings.Repeat(" ", width-n))
+	}
+	return b.String()
+}
+
+// containsFold returns true if text contains query, ignoring case
+func containsFold(text, query string) bool {
+	return query != "" && strings.Contains(strings.ToLower(text), strings.ToLower(query))
+}
+
+// highlight shows the occurrences of query in a line in reverse video,
+// ignoring case
+func highlight(line, query string) string {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return line
+	}
+	tokens := tokenize(line)
+	var visible []rune
+	for _, t := range tokens {
+		if t.esc == "" {
+			visible = append(visible, unicode.ToLower(t.r))
+		}
+	}
+
+	// matched marks the visible runes inside an occurrence
+	matched := make([]bool, len(visible))
+	found := false
+	for i := 0; i+len(q) <= len(visible); {
+		if string(visible[i:i+len(q)]) == string(q) {
+			for j := i; j < i+len(q); j++ {
+				matched[j] = true
+			}
+			found = true
+			i += len(q)
+			continue
+		}
+		i++
+	}
+	if !found {
+		return line
+	}
+
+	var b strings.Builder
+	pos := 0
+	inside := false
+	for _, t := range tokens {
+		if t.esc != "" {
+			b.WriteString(t.esc)
+			if inside && isReset(t.esc) {
+				b.WriteString(styleReverse)
+			}
+			continue
+		}
+		if matched[pos] != inside {
+			inside = matched[pos]
+			if inside {
+				b.WriteString(styleReverse)
+			} else {
+				b.WriteString(styleNoRev)
+			}
+		}
+		b.WriteRune(t.r)
+		pos++
+	}
+	if inside {
+		b.WriteString(styleNoRev)
+	}
+	return b.String()
+}
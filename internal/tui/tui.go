@@ -0,0 +1,133 @@
+// Package tui is the full-screen terminal browser of 'claudit tui': a list of
+// commits with conversations next to the selected commit's conversation.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+// Commit is a commit with a conversation, as listed in the commit pane
+type Commit struct {
+	SHA     string
+	Subject string
+	Date    time.Time
+}
+
+// Conversation is what the conversation pane shows for a commit
+type Conversation struct {
+	SessionID string
+	// Provider is the assistant the conversation was with
+	Provider string
+	Entries  []claude.TranscriptEntry
+	// ParentSHA is set when Entries only holds the conversation since this
+	// earlier commit of the same session
+	ParentSHA string
+}
+
+// Loader loads a commit's conversation, in full or since the previous commit
+// of its session
+type Loader func(sha string, full bool) (*Conversation, error)
+
+// Action is what the user asked for when leaving the TUI
+type Action struct {
+	// Resume is the commit whose session to resume
+	Resume string
+}
+
+// Run shows the TUI until the user quits, or asks for something that needs
+// the terminal, like resuming a session. showDiff is called with the
+// terminal restored to show a commit's diff, e.g. with git show.
+func Run(commits []Commit, load Loader, showDiff func(sha string) error) (Action, error) {
+	term, err := openTerminal()
+	if err != nil {
+		return Action{}, err
+	}
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	if err := term.enter(); err != nil {
+		return Action{}, err
+	}
+	defer func() { _ = term.leave() }()
+
+	m := newModel(commits, load)
+	input := make([]byte, 256)
+	draw := true
+	for !m.quit {
+		if draw {
+			if width, height, err := term.size(); err == nil {
+				m.resize(width, height)
+			}
+			if err := term.draw(m.view()); err != nil {
+				return Action{}, err
+			}
+			draw = false
+		}
+
+		select {
+		case <-resized:
+			draw = true
+			continue
+		default:
+		}
+
+		// Reads time out so that resizes are noticed
+		n, err := term.read(input)
+		if err != nil {
+			return Action{}, fmt.Errorf("could not read the terminal: %w", err)
+		}
+		if n == 0 {
+			continue
+		}
+		for _, key := range parseKeys(input[:n]) {
+			m.handleKey(key)
+		}
+		draw = true
+
+		if m.diff != "" {
+			sha := m.diff
+			m.diff = ""
+			if err := term.leave(); err != nil {
+				return Action{}, err
+			}
+			if err := showDiff(sha); err != nil {
+				m.status = err.Error()
+			}
+			if err := term.enter(); err != nil {
+				return Action{}, err
+			}
+		}
+	}
+	return m.action, nil
+}
+
+// enter switches to raw mode on the alternate screen
+func (t *terminal) enter() error {
+	if err := t.raw(); err != nil {
+		return fmt.Errorf("could not set up the terminal: %w", err)
+	}
+	_, err := t.out.WriteString("\033[?1049h\033[?25l")
+	return err
+}
+
+// leave restores the screen and mode the TUI started in
+func (t *terminal) leave() error {
+	if _, err := t.out.WriteString("\033[?25h\033[?1049l"); err != nil {
+		return err
+	}
+	return t.restore()
+}
+
+// draw redraws the screen with the given rows
+func (t *terminal) draw(rows []string) error {
+	_, err := t.out.WriteString("\033[H" + strings.Join(rows, "\r\n"))
+	return err
+}
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DanielJonesEB/claudit/internal/claude"
+)
+
+func TestWrap(t *testing.T) {
+	got := wrap("  one two three four", 10)
+	want := []string{"  one two ", "  three ", "  four"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrap = %q, want %q", got, want)
+	}
+
+	// Colors carry over to continuation lines
+	got = wrap("\033[33mabcdefgh\033[0m", 4)
+	want = []string{"\033[33mabcd\033[0m", "\033[33mefgh\033[0m"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrap = %q, want %q", got, want)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("abc", 5); got != "abc  " {
+		t.Errorf("fit pads to %q", got)
+	}
+	if got := fit("\033[1mabcdef", 3); got != "\033[1mabc\033[0m" {
+		t.Errorf("fit truncates to %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := highlight("Find the needle", "NEEDLE")
+	want := "Find the " + styleReverse + "needle" + styleNoRev
+	if got != want {
+		t.Errorf("highlight = %q, want %q", got, want)
+	}
+	if got := highlight("no match", "needle"); got != "no match" {
+		t.Errorf("highlight changed a line without matches: %q", got)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	if got := sanitize("a\tb\033[2Kc\r\033[31md"); got != "a    bc\033[31md" {
+		t.Errorf("sanitize = %q", got)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\033[Bq\r\033\x03é\033[5~\033[99x"))
+	want := []string{"j", "down", "q", "enter", "esc", "ctrl+c", "é", "pgup"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys = %q, want %q", got, want)
+	}
+}
+
+// testConversation has a prompt, a thinking block, a tool call and its result,
+// and a reply, with i added to the prompt
+func testConversation(i int) []claude.TranscriptEntry {
+	return []claude.TranscriptEntry{
+		{Type: claude.MessageTypeUser, Message: &claude.Message{Role: "user", Content: []claude.ContentBlock{
+			{Type: "text", Text: fmt.Sprintf("Prompt %d", i)},
+		}}},
+		{Type: claude.MessageTypeAssistant, Message: &claude.Message{Role: "assistant", Content: []claude.ContentBlock{
+			{Type: "thinking", Thinking: "Considering\nthe haystack"},
+			{Type: "tool_use", ID: "t1", Name: "Bash", Input: json.RawMessage(`{"command":"ls"}`)},
+		}}},
+		{Type: claude.MessageTypeUser, Message: &claude.Message{Role: "user", Content: []claude.ContentBlock{
+			{Type: "tool_result", ToolUseID: "t1", Content: json.RawMessage(`"a.go\nneedle.go"`)},
+		}}},
+		{Type: claude.MessageTypeAssistant, Message: &claude.Message{Role: "assistant", Content: []claude.ContentBlock{
+			{Type: "text", Text: "Done"},
+		}}},
+	}
+}
+
+// testModel returns a model of two commits, recording the loads
+func testModel(t *testing.T, loads *[]string) *model {
+	t.Setenv("NO_COLOR", "1")
+	commits := []Commit{{SHA: "aaaaaaaaaa", Subject: "Second"}, {SHA: "bbbbbbbbbb", Subject: "First"}}
+	load := func(sha string, full bool) (*Conversation, error) {
+		*loads = append(*loads, fmt.Sprintf("%s full=%v", sha, full))
+		entries := testConversation(len(*loads))
+		conv := &Conversation{SessionID: "s", Provider: "claude", Entries: entries[2:]}
+		if full {
+			conv.Entries = entries
+		} else if sha == "aaaaaaaaaa" {
+			conv.ParentSHA = "bbbbbbbbbb"
+		}
+		return conv, nil
+	}
+	m := newModel(commits, load)
+	m.resize(100, 20)
+	return m
+}
+
+// screen returns the visible text of the conversation lines
+func screen(m *model) string {
+	var texts []string
+	for _, l := range m.lines {
+		texts = append(texts, stripANSI(l.text))
+	}
+	return strings.Join(texts, "\n")
+}
+
+func press(m *model, keys ...string) {
+	for _, key := range keys {
+		m.handleKey(key)
+	}
+}
+
+func TestModelNavigation(t *testing.T) {
+	var loads []string
+	m := testModel(t, &loads)
+
+	press(m, "j")
+	if m.selected != 1 {
+		t.Fatalf("selected = %d, want 1", m.selected)
+	}
+	press(m, "j")
+	if m.selected != 1 {
+		t.Errorf("selection moved past the last commit: %d", m.selected)
+	}
+	want := []string{"aaaaaaaaaa full=false", "bbbbbbbbbb full=false"}
+	if !reflect.DeepEqual(loads, want) {
+		t.Errorf("loads = %q, want %q", loads, want)
+	}
+
+	press(m, "tab", "G")
+	if m.focus != conversationPane || m.cursor != len(m.lines)-1 {
+		t.Errorf("focus = %v, cursor = %d of %d lines", m.focus, m.cursor, len(m.lines))
+	}
+}
+
+func TestModelIncrementalToggle(t *testing.T) {
+	var loads []string
+	m := testModel(t, &loads)
+	if strings.Contains(screen(m), "Prompt") {
+		t.Errorf("incremental conversation should start at the tool result:\n%s", screen(m))
+	}
+	if title := stripANSI(m.titleBar()); !strings.Contains(title, "since bbbbbbb") {
+		t.Errorf("title = %q", title)
+	}
+
+	press(m, "i")
+	if loads[len(loads)-1] != "aaaaaaaaaa full=true" {
+		t.Errorf("loads = %q", loads)
+	}
+	if !strings.Contains(screen(m), "Prompt 2") {
+		t.Errorf("full conversation should include the prompt:\n%s", screen(m))
+	}
+	if title := stripANSI(m.titleBar()); !strings.Contains(title, "full session") {
+		t.Errorf("title = %q", title)
+	}
+}
+
+func TestModelExpand(t *testing.T) {
+	var loads []string
+	m := testModel(t, &loads)
+	press(m, "i")
+
+	if got := screen(m); !strings.Contains(got, "[thinking] Considering (2 lines)") || strings.Contains(got, "the haystack") {
+		t.Errorf("thinking should be collapsed:\n%s", got)
+	}
+
+	press(m, "e")
+	if got := screen(m); !strings.Contains(got, "the haystack") || !strings.Contains(got, "needle.go") {
+		t.Errorf("e should expand every block:\n%s", got)
+	}
+	press(m, "e")
+	if got := screen(m); strings.Contains(got, "the haystack") {
+		t.Errorf("e again should collapse every block:\n%s", got)
+	}
+
+	// enter toggles the block under the cursor
+	press(m, "tab")
+	for !m.items[m.lines[m.cursor].item].collapsible {
+		press(m, "j")
+	}
+	press(m, "enter")
+	if got := screen(m); !strings.Contains(got, "the haystack") {
+		t.Errorf("enter should expand the thinking block:\n%s", got)
+	}
+}
+
+func TestModelSearch(t *testing.T) {
+	var loads []string
+	m := testModel(t, &loads)
+	press(m, "i", "/", "h", "a", "y", "enter")
+
+	if m.status != `match 1 of 1 for "hay"` {
+		t.Errorf("status = %q", m.status)
+	}
+	if got := stripANSI(m.lines[m.cursor].text); !strings.Contains(got, "the haystack") {
+		t.Errorf("cursor should be on the match, got %q", got)
+	}
+	if m.focus != conversationPane {
+		t.Errorf("search should focus the conversation")
+	}
+
+	press(m, "/", "z", "z", "enter")
+	if m.status != `no matches for "zz"` {
+		t.Errorf("status = %q", m.status)
+	}
+}
+
+func TestModelResume(t *testing.T) {
+	var loads []string
+	m := testModel(t, &loads)
+
+	press(m, "r", "n")
+	if m.quit || m.action.Resume != "" {
+		t.Errorf("answering n should not resume")
+	}
+	press(m, "r", "y")
+	if !m.quit || m.action.Resume != "aaaaaaaaaa" {
+		t.Errorf("quit = %v, action = %+v", m.quit, m.action)
+	}
+}
+
+func TestModelResumeOtherProvider(t *testing.T) {
+	m := newModel([]Commit{{SHA: "aaaaaaaaaa"}}, func(string, bool) (*Conversation, error) {
+		return &Conversation{Provider: "aider"}, nil
+	})
+	press(m, "r")
+	if m.confirming || !strings.Contains(m.status, "only Claude Code sessions") {
+		t.Errorf("confirming = %v, status = %q", m.confirming, m.status)
+	}
+}
+
+func TestView(t *testing.T) {
+	var loads []string
+	m := testModel(t, &loads)
+	rows := m.view()
+	if len(rows) != 20 {
+		t.Fatalf("view has %d rows, want 20", len(rows))
+	}
+	for i, row := range rows {
+		if n := len([]rune(stripANSI(row))); n != 100 {
+			t.Errorf("row %d is %d wide: %q", i, n, stripANSI(row))
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/provider"
+	"github.com/DanielJonesEB/claudit/internal/util"
+)
+
+// keyHints is the status bar when there is nothing else to show
+const keyHints = "↑↓ move  tab pane  enter expand  e all  i full/incremental  / search  n next  d diff  r resume  q quit"
+
+// view draws the screen as one line per row, each exactly as wide as the
+// terminal
+func (m *model) view() []string {
+	rows := make([]string, 0, m.height)
+	rows = append(rows, m.titleBar())
+
+	commitWidth := m.commitPaneWidth()
+	conversationWidth := m.conversationWidth()
+	separator := styleDim + "│" + styleReset
+	for i := 0; i < m.bodyHeight(); i++ {
+		rows = append(rows, m.commitRow(m.commitTop+i, commitWidth)+separator+m.conversationRow(m.top+i, conversationWidth))
+	}
+
+	if m.height > 1 {
+		rows = append(rows, m.statusBar())
+	}
+	return rows
+}
+
+// titleBar describes the selected commit and what of its conversation is shown
+func (m *model) titleBar() string {
+	title := " claudit"
+	if len(m.commits) > 0 {
+		commit := m.commits[m.selected]
+		title += fmt.Sprintf("  %s %s", util.ShortSHA(commit.SHA), commit.Subject)
+	}
+	if m.conv != nil {
+		scope := "full session"
+		if m.conv.ParentSHA != "" {
+			scope = "since " + util.ShortSHA(m.conv.ParentSHA)
+		}
+		title += fmt.Sprintf("  ·  %d entries (%s)", len(m.conv.Entries), scope)
+		if m.conv.Provider != "" && m.conv.Provider != provider.Claude {
+			title += "  ·  " + m.conv.Provider
+		}
+	}
+	return styleReverse + fit(title, m.width) + styleReset
+}
+
+// commitRow draws a row of the commit pane
+func (m *model) commitRow(index, width int) string {
+	if index >= len(m.commits) {
+		return strings.Repeat(" ", width)
+	}
+	commit := m.commits[index]
+	date := commit.Date.Local().Format("2006-01-02")
+
+	if index == m.selected {
+		text := fmt.Sprintf(" %s %s %s", util.ShortSHA(commit.SHA), date, commit.Subject)
+		style := styleBold
+		if m.focus == commitPane {
+			style = styleReverse
+		}
+		return style + fit(text, width) + styleReset
+	}
+	text := fmt.Sprintf(" %s%s%s %s%s%s %s", styleYellow, util.ShortSHA(commit.SHA), styleReset, styleDim, date, styleReset, commit.Subject)
+	return fit(text, width)
+}
+
+// conversationRow draws a row of the conversation pane: the cursor, the
+// expand/collapse marker and the line
+func (m *model) conversationRow(index, width int) string {
+	if index == 0 && len(m.lines) == 0 {
+		switch {
+		case m.loadErr != nil:
+			return fit(" "+styleRed+"could not load conversation: "+m.loadErr.Error()+styleReset, width)
+		case len(m.commits) == 0:
+			return fit(" no conversations", width)
+		default:
+			return fit(" no messages", width)
+		}
+	}
+	if index >= len(m.lines) {
+		return strings.Repeat(" ", width)
+	}
+
+	l := m.lines[index]
+	gutter := []rune("  ")
+	if index == m.cursor && m.focus == conversationPane {
+		gutter[0] = '›'
+	}
+	if m.items[l.item].collapsible && index == m.firstLine(l.item) {
+		gutter[1] = '▸'
+		if m.expanded[l.item] {
+			gutter[1] = '▾'
+		}
+	}
+
+	text := l.text
+	if m.query != "" {
+		text = highlight(text, m.query)
+	}
+	return styleBold + string(gutter) + styleReset + fit(text, width-gutterWidth)
+}
+
+// statusBar shows the search being typed, a question, a message or the keys
+func (m *model) statusBar() string {
+	switch {
+	case m.searching:
+		return fit("/"+m.input+"█", m.width)
+	case m.confirming:
+		return fit(styleYellow+fmt.Sprintf("Resume the session of %s? (y/n)", util.ShortSHA(m.commits[m.selected].SHA))+styleReset, m.width)
+	case m.status != "":
+		return fit(m.status, m.width)
+	}
+	return fit(styleDim+keyHints+styleReset, m.width)
+}
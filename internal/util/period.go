@@ -0,0 +1,23 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodStart returns the first day of the day, week (starting Monday) or
+// month containing t
+func PeriodStart(t time.Time, interval string) (time.Time, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch interval {
+	case "day":
+		return day, nil
+	case "week":
+		// time.Weekday counts from Sunday; weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset), nil
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown interval %q (expected day, week or month)", interval)
+}
@@ -0,0 +1,34 @@
+package util
+
+import "strings"
+
+// ShortSHA abbreviates a SHA or session ID for display, tolerating short input
+func ShortSHA(id string) string {
+	if len(id) > 7 {
+		return id[:7]
+	}
+	return id
+}
+
+// FirstLine returns the first non-empty line of text, trimmed
+func FirstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// OneLine returns the first non-empty line of text, truncated to max runes
+func OneLine(text string, max int) string {
+	return Truncate(FirstLine(text), max)
+}
+
+// Truncate cuts s to at most max runes, ending it with "…" if cut
+func Truncate(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,18 @@
+package util
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("héllo world", 8); got != "héllo w…" {
+		t.Errorf("Truncate() = %q", got)
+	}
+	if got := Truncate("short", 5); got != "short" {
+		t.Errorf("Truncate() = %q", got)
+	}
+}
+
+func TestOneLine(t *testing.T) {
+	if got := OneLine("\n  Fix the flaky test\nIt fails on CI", 12); got != "Fix the fla…" {
+		t.Errorf("OneLine() = %q", got)
+	}
+}
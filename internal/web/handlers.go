@@ -5,22 +5,29 @@ import (
 	"fmt"
 	"net/http"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/DanielJonesEB/claudit/internal/attribution"
 	"github.com/DanielJonesEB/claudit/internal/claude"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/provider"
+	"github.com/DanielJonesEB/claudit/internal/stats"
 	"github.com/DanielJonesEB/claudit/internal/storage"
+	"github.com/DanielJonesEB/claudit/internal/summary"
 )
 
 // CommitInfo represents commit data for the API
 type CommitInfo struct {
-	SHA             string `json:"sha"`
-	Message         string `json:"message"`
-	Author          string `json:"author"`
-	Date            string `json:"date"`
-	HasConversation bool   `json:"has_conversation"`
-	MessageCount    int    `json:"message_count,omitempty"`
+	SHA             string   `json:"sha"`
+	Message         string   `json:"message"`
+	Author          string   `json:"author"`
+	Date            string   `json:"date"`
+	HasConversation bool     `json:"has_conversation"`
+	MessageCount    int      `json:"message_count,omitempty"`
+	Summary         string   `json:"summary,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
 }
 
 // ConversationResponse represents the full conversation data
@@ -28,11 +35,34 @@ type ConversationResponse struct {
 	SHA              string                   `json:"sha"`
 	SessionID        string                   `json:"session_id"`
 	Timestamp        string                   `json:"timestamp"`
+	Provider         string                   `json:"provider"`
 	MessageCount     int                      `json:"message_count"`
 	Transcript       []claude.TranscriptEntry `json:"transcript"`
 	IsIncremental    bool                     `json:"is_incremental"`
 	ParentCommitSHA  string                   `json:"parent_commit_sha,omitempty"`
 	IncrementalCount int                      `json:"incremental_count,omitempty"`
+	SourceCommits    []storage.SourceCommit   `json:"source_commits,omitempty"`
+	// ToolDiffs are the changes the transcript's file edits made
+	ToolDiffs []claude.ToolDiff `json:"tool_diffs,omitempty"`
+	// Diff is set when requested with diff=true
+	Diff *attribution.DiffAnnotation `json:"diff,omitempty"`
+	// Branches holds the abandoned branches when requested with tree=true,
+	// in which case Transcript only holds the path to the commit
+	Branches []claude.Branch `json:"branches,omitempty"`
+}
+
+// ConversationDiffResponse compares the conversations of two commits
+type ConversationDiffResponse struct {
+	CommitA ComparedCommit `json:"commit_a"`
+	CommitB ComparedCommit `json:"commit_b"`
+	claude.Divergence
+}
+
+// ComparedCommit is one side of a ConversationDiffResponse
+type ComparedCommit struct {
+	SHA       string `json:"sha"`
+	Message   string `json:"message"`
+	SessionID string `json:"session_id"`
 }
 
 // GraphNode represents a node in the commit graph
@@ -103,6 +133,7 @@ func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
 	if hc := r.URL.Query().Get("has_conversation"); hc == "true" {
 		hasConversationFilter = true
 	}
+	labelFilter := r.URL.Query().Get("label")
 
 	noteSet, err := buildNoteSet()
 	if err != nil {
@@ -110,6 +141,12 @@ func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	labels, err := storage.AllLabels()
+	if err != nil {
+		http.Error(w, "Failed to list labels", http.StatusInternalServerError)
+		return
+	}
+
 	// Get all commits
 	commits, err := getCommitList(limit+offset, s.repoDir)
 	if err != nil {
@@ -125,6 +162,9 @@ func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
 		if hasConversationFilter && !hasConv {
 			continue
 		}
+		if labelFilter != "" && !storage.HasLabel(labels[commit.SHA], labelFilter) {
+			continue
+		}
 
 		info := CommitInfo{
 			SHA:             commit.SHA,
@@ -132,13 +172,11 @@ func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
 			Author:          commit.Author,
 			Date:            commit.Date,
 			HasConversation: hasConv,
+			Labels:          labels[commit.SHA],
 		}
 
-		// Get message count if has conversation
 		if hasConv {
-			if stored, err := storage.GetStoredConversation(commit.SHA); err == nil && stored != nil {
-				info.MessageCount = stored.MessageCount
-			}
+			addConversation(&info)
 		}
 
 		result = append(result, info)
@@ -157,6 +195,15 @@ func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(result)
 }
 
+// addConversation fills in the message count and summary of the commit's
+// conversation
+func addConversation(info *CommitInfo) {
+	if stored, err := storage.GetStoredConversation(info.SHA); err == nil && stored != nil {
+		info.MessageCount = stored.MessageCount
+		info.Summary = summary.Of(stored)
+	}
+}
+
 // handleCommitDetail returns the full conversation for a specific commit
 func (s *Server) handleCommitDetail(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -173,8 +220,16 @@ func (s *Server) handleCommitDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if incremental mode is requested
+	// Check if incremental mode or the commit diff is requested
 	incremental := r.URL.Query().Get("incremental") == "true"
+	withDiff := r.URL.Query().Get("diff") == "true"
+	withTree := r.URL.Query().Get("tree") == "true"
+
+	filters, err := filterOptions(r).Filters()
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Resolve the reference
 	fullSHA, err := git.ResolveRef(sha)
@@ -198,10 +253,11 @@ func (s *Server) handleCommitDetail(w http.ResponseWriter, r *http.Request) {
 	// Determine which entries to return
 	var entries []claude.TranscriptEntry
 	var parentSHA string
+	var lastEntryUUID string
 	var isIncremental bool
 
-	if incremental {
-		var lastEntryUUID string
+	// Aggregate conversations are always shown whole, split by source commit
+	if incremental && !stored.IsAggregate() {
 		parentSHA, lastEntryUUID = storage.FindParentConversationBoundary(fullSHA, stored.SessionID)
 		if lastEntryUUID != "" {
 			entries = transcript.GetEntriesSince(lastEntryUUID)
@@ -213,21 +269,182 @@ func (s *Server) handleCommitDetail(w http.ResponseWriter, r *http.Request) {
 		entries = transcript.Entries
 	}
 
+	// The diffs are made from every entry, whatever is shown
+	all := entries
+	var tree *claude.Tree
+	var branches []claude.Branch
+	if withTree && !stored.IsAggregate() {
+		tree = claude.NewTree(transcript.Entries)
+		if isIncremental {
+			entries = tree.PathSince(lastEntryUUID)
+		} else {
+			entries = tree.ActivePath()
+		}
+		branches = tree.Branches(entries)
+	}
+	entries = claude.ApplyFilters(entries, filters...)
+	if tree != nil {
+		branches = tree.PlaceBranches(entries, claude.FilterBranches(branches, filters...))
+	}
+
+	// The parent holds the files as they were before the commit's own
+	// entries, but not before earlier commits' ones or an aggregate's
+	var previous func(path string) (string, bool)
+	if incremental && !stored.IsAggregate() {
+		previous = attribution.PreviousContent(fullSHA, stored.ProjectPath)
+	}
+
 	response := ConversationResponse{
 		SHA:              fullSHA,
 		SessionID:        stored.SessionID,
 		Timestamp:        stored.Timestamp,
+		Provider:         stored.ProviderName(),
 		MessageCount:     stored.MessageCount,
 		Transcript:       entries,
 		IsIncremental:    isIncremental,
 		ParentCommitSHA:  parentSHA,
 		IncrementalCount: len(entries),
+		SourceCommits:    stored.SourceCommits,
+		ToolDiffs:        claude.ToolDiffs(all, previous),
+		Branches:         branches,
+	}
+
+	if withDiff {
+		response.Diff, err = attribution.AnnotateCommitDiff(fullSHA, all)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "failed to read commit diff")
+			return
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// handleDiffConversation compares the conversations of the commits given
+// by the a and b query parameters
+func (s *Server) handleDiffConversation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var commits [2]ComparedCommit
+	var transcripts [2]*claude.Transcript
+	for i, key := range []string{"a", "b"} {
+		ref := r.URL.Query().Get(key)
+		if ref == "" {
+			writeJSONError(w, http.StatusBadRequest, "a and b commits required")
+			return
+		}
+		fullSHA, err := git.ResolveRef(ref)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid commit reference")
+			return
+		}
+		stored := getStoredOrWriteError(w, fullSHA)
+		if stored == nil {
+			return
+		}
+		if transcripts[i], err = stored.ParseTranscript(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "failed to parse transcript")
+			return
+		}
+		message, _, _ := git.GetCommitInfo(fullSHA)
+		commits[i] = ComparedCommit{SHA: fullSHA, Message: message, SessionID: stored.SessionID}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ConversationDiffResponse{
+		CommitA:    commits[0],
+		CommitB:    commits[1],
+		Divergence: claude.Diverge(transcripts[0].Entries, transcripts[1].Entries),
+	})
+}
+
+// attachmentTypes are the media types served as themselves; others are
+// served as downloads so they can't run in the UI's origin
+var attachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+// handleAttachment serves an image or document from a commit's
+// conversation, at /api/attachments/<sha>/<attachment id>
+func (s *Server) handleAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sha, id, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/attachments/"), "/")
+	if !ok || sha == "" || id == "" {
+		http.Error(w, "Commit SHA and attachment ID required", http.StatusBadRequest)
+		return
+	}
+	fullSHA, err := git.ResolveRef(sha)
+	if err != nil {
+		http.Error(w, "Invalid commit reference", http.StatusBadRequest)
+		return
+	}
+
+	stored := getStoredOrWriteError(w, fullSHA)
+	if stored == nil {
+		return
+	}
+	transcript, err := stored.ParseTranscript()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to parse transcript")
+		return
+	}
+
+	attachment, ok := claude.FindAttachment(transcript.Entries, id)
+	if !ok || len(attachment.Data) == 0 {
+		writeJSONError(w, http.StatusNotFound, "no attachment found")
+		return
+	}
+	if attachmentTypes[attachment.MediaType] {
+		w.Header().Set("Content-Type", attachment.MediaType)
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName()))
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = w.Write(attachment.Data)
+}
+
+// filterOptions reads the entry filters of a conversation request from its
+// query: only, tools (comma-separated), no_tool_results, thinking,
+// max_result_lines and grep
+func filterOptions(r *http.Request) claude.FilterOptions {
+	query := r.URL.Query()
+	list := func(key string) []string {
+		var values []string
+		for _, v := range strings.Split(query.Get(key), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+
+	options := claude.FilterOptions{
+		NoToolResults: query.Get("no_tool_results") == "true",
+		Tools:         list("tools"),
+		Thinking:      query.Get("thinking"),
+		Grep:          query.Get("grep"),
+	}
+	for _, t := range list("only") {
+		options.Only = append(options.Only, claude.MessageType(t))
+	}
+	options.MaxResultLines, _ = strconv.Atoi(query.Get("max_result_lines"))
+	return options
+}
+
 // handleGraph returns the commit graph data
 func (s *Server) handleGraph(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -296,6 +513,11 @@ func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if stored.ProviderName() != provider.Claude {
+		writeJSONError(w, http.StatusBadRequest, "only Claude Code sessions can be resumed")
+		return
+	}
+
 	// Decompress transcript for restore
 	transcriptData, err := stored.GetTranscript()
 	if err != nil {
@@ -310,7 +532,7 @@ func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
 		stored.GitBranch,
 		transcriptData,
 		stored.MessageCount,
-		"Restored from web UI",
+		summary.OrDefault(stored, "Restored from web UI"),
 	)
 	if err != nil {
 		writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("failed to restore session: %v", err))
@@ -338,6 +560,72 @@ func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleBlame returns per-hunk conversation attribution for a file.
+// Query parameters: file (repository-relative path, required), ref (default HEAD).
+func (s *Server) handleBlame(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	file := r.URL.Query().Get("file")
+	if file == "" {
+		writeJSONError(w, http.StatusBadRequest, "file parameter required")
+		return
+	}
+	// Only allow paths inside the repository
+	clean := filepath.Clean(filepath.FromSlash(file))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		writeJSONError(w, http.StatusBadRequest, "invalid file path")
+		return
+	}
+
+	rev := r.URL.Query().Get("ref")
+	if rev != "" {
+		fullSHA, err := git.ResolveRef(rev)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid commit reference")
+			return
+		}
+		rev = fullSHA
+	}
+
+	hunks, err := attribution.Blame(rev, filepath.Join(s.repoDir, clean))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, "could not blame file")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(hunks)
+}
+
+// handleSessions returns the timeline of every session with stored
+// conversations, oldest first
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	commits, err := stats.CommitsWithConversations()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to list conversations")
+		return
+	}
+	sessions, err := stats.Sessions(commits, claude.DefaultIdleThreshold)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to read sessions")
+		return
+	}
+	if sessions == nil {
+		sessions = []stats.SessionSummary{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(sessions)
+}
+
 // CommitData holds basic commit information
 type CommitData struct {
 	SHA     string
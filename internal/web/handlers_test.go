@@ -10,8 +10,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/DanielJonesEB/claudit/internal/attribution"
 	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/stats"
 	"github.com/DanielJonesEB/claudit/internal/storage"
 )
 
@@ -325,6 +328,10 @@ func TestHandleCommits(t *testing.T) {
 		if withConv.MessageCount != 2 {
 			t.Errorf("MessageCount: want 2, got %d", withConv.MessageCount)
 		}
+		// Notes stored without a summary are summarized offline
+		if !strings.HasPrefix(withConv.Summary, "Hello, can you help?") {
+			t.Errorf("Summary: want the offline summary, got %q", withConv.Summary)
+		}
 	})
 
 	t.Run("filters by has_conversation", func(t *testing.T) {
@@ -393,6 +400,55 @@ func TestHandleCommits(t *testing.T) {
 	})
 }
 
+func TestHandleCommitsLabels(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("a.txt", "a")
+	sha1 := repo.commit("First commit")
+	repo.addConversation(sha1, "session-1", sampleTranscript(), 2)
+
+	repo.writeFile("b.txt", "b")
+	sha2 := repo.commit("Second commit")
+	repo.addConversation(sha2, "session-2", sampleTranscript(), 2)
+
+	if _, err := storage.AddLabels(sha1, []string{"incident", "good example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(0, repo.path)
+
+	t.Run("includes labels", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/commits", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		var commits []CommitInfo
+		decodeJSON(t, w, &commits)
+		for _, c := range commits {
+			want := ""
+			if c.SHA == sha1 {
+				want = "good example,incident"
+			}
+			if got := strings.Join(c.Labels, ","); got != want {
+				t.Errorf("labels of %s: want %q, got %q", c.Message, want, got)
+			}
+		}
+	})
+
+	t.Run("filters by label", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/commits?label=incident", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		var commits []CommitInfo
+		decodeJSON(t, w, &commits)
+		if len(commits) != 1 || commits[0].SHA != sha1 {
+			t.Fatalf("expected only %s, got %+v", sha1, commits)
+		}
+	})
+}
+
 func TestHandleCommitDetail(t *testing.T) {
 	repo := newTestRepo(t)
 	chdir(t, repo.path)
@@ -838,3 +894,488 @@ func TestStaticFileServing(t *testing.T) {
 		}
 	})
 }
+
+// writeTranscript returns a transcript in which the assistant writes a file
+func writeTranscript(filePath, content string) []byte {
+	entries := []map[string]interface{}{
+		{
+			"uuid": "user-1", "type": "user",
+			"message": map[string]interface{}{
+				"role": "user",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": "Please write " + filePath},
+				},
+			},
+		},
+		{
+			"uuid": "assistant-1", "parentUuid": "user-1", "type": "assistant",
+			"message": map[string]interface{}{
+				"role": "assistant",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": "Writing the file now."},
+					{
+						"type": "tool_use", "id": "tool-write", "name": "Write",
+						"input": map[string]interface{}{"file_path": filePath, "content": content},
+					},
+				},
+			},
+		},
+	}
+	return marshalTranscript(entries)
+}
+
+func TestHandleBlame(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("README.md", "# Readme\n")
+	repo.commit("Human commit")
+
+	repo.writeFile("main.go", "package main\n\nfunc main() {}\n")
+	sha := repo.commit("Add main")
+	repo.addConversation(sha, "session-blame", writeTranscript(filepath.Join(repo.path, "main.go"), "package main\n\nfunc main() {}\n"), 2)
+
+	srv := NewServer(0, repo.path)
+
+	t.Run("attributes lines to the tool call", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/blame?file=main.go", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status: want 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var hunks []attribution.BlameHunk
+		decodeJSON(t, w, &hunks)
+		if len(hunks) != 1 {
+			t.Fatalf("hunks: want 1, got %d", len(hunks))
+		}
+		if hunks[0].CommitSHA != sha {
+			t.Errorf("CommitSHA: want %s, got %s", sha, hunks[0].CommitSHA)
+		}
+		if hunks[0].Edit == nil {
+			t.Fatal("Edit: want attribution, got nil")
+		}
+		if hunks[0].Edit.Tool != "Write" {
+			t.Errorf("Tool: want Write, got %q", hunks[0].Edit.Tool)
+		}
+		if hunks[0].Edit.Prompt != "Please write "+filepath.Join(repo.path, "main.go") {
+			t.Errorf("Prompt: got %q", hunks[0].Edit.Prompt)
+		}
+		if hunks[0].Edit.Explanation != "Writing the file now." {
+			t.Errorf("Explanation: got %q", hunks[0].Edit.Explanation)
+		}
+	})
+
+	t.Run("commits without conversations have no attribution", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/blame?file=README.md", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		var hunks []attribution.BlameHunk
+		decodeJSON(t, w, &hunks)
+		if len(hunks) != 1 || hunks[0].Edit != nil || hunks[0].SessionID != "" {
+			t.Errorf("want one unattributed hunk, got %+v", hunks)
+		}
+	})
+
+	t.Run("missing file parameter", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/blame", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status: want 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("rejects paths outside the repository", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/blame?file=../etc/passwd", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status: want 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestHandleCommitDetailDiff(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("main.go", "package main\n")
+	repo.writeFile("notes.txt", "written by hand\n")
+	sha := repo.commit("Add main")
+	repo.addConversation(sha, "session-diff", writeTranscript(filepath.Join(repo.path, "main.go"), "package main\n"), 2)
+
+	srv := NewServer(0, repo.path)
+
+	t.Run("omits the diff by default", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/commits/"+sha, nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		var resp ConversationResponse
+		decodeJSON(t, w, &resp)
+		if resp.Diff != nil {
+			t.Errorf("Diff: want nil, got %+v", resp.Diff)
+		}
+	})
+
+	t.Run("matches hunks to tool calls", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/commits/"+sha+"?diff=true", nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status: want 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var resp ConversationResponse
+		decodeJSON(t, w, &resp)
+		if resp.Diff == nil || len(resp.Diff.Hunks) != 2 {
+			t.Fatalf("Diff: want 2 hunks, got %+v", resp.Diff)
+		}
+		for _, hunk := range resp.Diff.Hunks {
+			switch hunk.File {
+			case "main.go":
+				if hunk.Human || len(hunk.ToolUseIDs) != 1 || hunk.ToolUseIDs[0] != "tool-write" {
+					t.Errorf("main.go hunk: want tool-write, got %+v", hunk)
+				}
+			case "notes.txt":
+				if !hunk.Human {
+					t.Errorf("notes.txt hunk: want human, got %+v", hunk)
+				}
+			default:
+				t.Errorf("unexpected hunk for %s", hunk.File)
+			}
+		}
+		if len(resp.Diff.Edits) != 1 || !resp.Diff.Edits[0].InCommit {
+			t.Errorf("Edits: want one edit in the commit, got %+v", resp.Diff.Edits)
+		}
+	})
+}
+
+func TestHandleCommitDetailToolDiffs(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	mainGo := filepath.Join(repo.path, "main.go")
+	repo.writeFile("main.go", "package main\n")
+	parent := repo.commit("Add main")
+	repo.writeFile("main.go", "package main\n\nfunc main() {}\n")
+	sha := repo.commit("Add func main")
+
+	// The session's earlier commit makes the view of sha incremental
+	earlier := `{"uuid":"user-0","type":"user","message":{"role":"user","content":"Start"}}` + "\n"
+	repo.addConversation(parent, "session-tool-diffs", []byte(earlier), 1)
+	repo.addConversation(sha, "session-tool-diffs", append([]byte(earlier), writeTranscript(mainGo, "package main\n\nfunc main() {}\n")...), 3)
+
+	srv := NewServer(0, repo.path)
+	get := func(query string) ConversationResponse {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/commits/"+sha+query, nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+		var resp ConversationResponse
+		decodeJSON(t, w, &resp)
+		if len(resp.ToolDiffs) != 1 {
+			t.Fatalf("ToolDiffs: want 1, got %+v", resp.ToolDiffs)
+		}
+		return resp
+	}
+
+	t.Run("incremental views diff Writes against the parent", func(t *testing.T) {
+		diff := get("?incremental=true").ToolDiffs[0]
+		if diff.ToolUseID != "tool-write" || diff.NewFile {
+			t.Errorf("want a diff of tool-write against the parent, got %+v", diff)
+		}
+		want := []string{" package main", "+", "+func main() {}"}
+		if len(diff.Hunks) != 1 || strings.Join(diff.Hunks[0].Lines, "|") != strings.Join(want, "|") {
+			t.Errorf("Hunks: want lines %q, got %+v", want, diff.Hunks)
+		}
+	})
+
+	t.Run("full sessions don't use the parent", func(t *testing.T) {
+		if diff := get("").ToolDiffs[0]; !diff.NewFile {
+			t.Errorf("want tool-write shown as a new file, got %+v", diff)
+		}
+	})
+}
+
+func TestHandleCommitDetailFilters(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("main.go", "package main\n")
+	sha := repo.commit("Add main")
+	repo.addConversation(sha, "session-filters", writeTranscript(filepath.Join(repo.path, "main.go"), "package main\n"), 2)
+
+	srv := NewServer(0, repo.path)
+	req := httptest.NewRequest("GET", "/api/commits/"+sha+"?only=assistant&tools=Bash", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp ConversationResponse
+	decodeJSON(t, w, &resp)
+	if len(resp.Transcript) != 1 || resp.Transcript[0].UUID != "assistant-1" {
+		t.Fatalf("want only the assistant entry, got %+v", resp.Transcript)
+	}
+	if blocks := resp.Transcript[0].Message.Content; len(blocks) != 1 || blocks[0].Type != "text" {
+		t.Errorf("want the Write call filtered out, got %+v", blocks)
+	}
+	if len(resp.ToolDiffs) != 1 {
+		t.Errorf("tool diffs should be made from every entry, got %+v", resp.ToolDiffs)
+	}
+
+	req = httptest.NewRequest("GET", "/api/commits/"+sha+"?thinking=some", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown thinking mode: want status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleAttachment(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("README.md", "# Test\n")
+	sha := repo.commit("Initial commit")
+	entries := []map[string]interface{}{
+		{
+			"uuid": "user-1", "type": "user",
+			"message": map[string]interface{}{
+				"role": "user",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": "What's wrong here?"},
+					{"type": "image", "source": map[string]interface{}{"type": "base64", "media_type": "image/png", "data": "iVBORw0KGgo="}},
+					{"type": "document", "source": map[string]interface{}{"type": "base64", "media_type": "text/html", "data": "PGI+aGk8L2I+"}},
+				},
+			},
+		},
+	}
+	repo.addConversation(sha, "session-attachments", marshalTranscript(entries), 1)
+	srv := NewServer(0, repo.path)
+
+	tests := []struct {
+		id          string
+		status      int
+		contentType string
+		body        string
+	}{
+		{id: "user-1.1", status: http.StatusOK, contentType: "image/png", body: "\x89PNG\r\n\x1a\n"},
+		// Types that could run in the UI's origin are downloaded
+		{id: "user-1.2", status: http.StatusOK, contentType: "application/octet-stream", body: "<b>hi</b>"},
+		{id: "user-1.0", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/attachments/"+sha+"/"+tt.id, nil)
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.id, w.Code, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s: Content-Type = %s, want %s", tt.id, got, tt.contentType)
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: body = %q, want %q", tt.id, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestHandleDiffConversation(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("README.md", "# Test\n")
+	first := repo.commit("Initial commit")
+	repo.addConversation(first, "session-1", sampleTranscript(), 2)
+	repo.writeFile("README.md", "# Test\n\nMore\n")
+	second := repo.commit("Add more")
+	repo.addConversation(second, "session-1", extendedTranscript(), 4)
+
+	srv := NewServer(0, repo.path)
+	req := httptest.NewRequest("GET", "/api/diff-conversation?a="+first+"&b="+second, nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp ConversationDiffResponse
+	decodeJSON(t, w, &resp)
+	if resp.CommitA.SHA != first || resp.CommitB.SHA != second {
+		t.Errorf("commits = %+v, %+v", resp.CommitA, resp.CommitB)
+	}
+	if len(resp.Common) != 2 || resp.ForkUUID != "assistant-1" || len(resp.A) != 0 || len(resp.B) != 2 {
+		t.Errorf("want 2 common entries then 2 only in B, got %+v", resp.Divergence)
+	}
+
+	req = httptest.NewRequest("GET", "/api/diff-conversation?a="+first, nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("missing b: want status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleSessions(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	start := time.Now().Add(-time.Hour).UTC()
+	entry := func(uuid string, offset time.Duration) map[string]interface{} {
+		return map[string]interface{}{
+			"uuid": uuid, "type": "user", "timestamp": start.Add(offset).Format(time.RFC3339Nano),
+			"message": map[string]interface{}{"role": "user", "content": "working"},
+		}
+	}
+	transcript := marshalTranscript([]map[string]interface{}{
+		entry("user-1", 0),
+		entry("user-2", 2*time.Minute),
+		entry("user-3", 30*time.Minute),
+	})
+
+	repo.writeFile("main.go", "package main\n")
+	sha := repo.commit("Add main")
+	repo.addConversation(sha, "session-timeline", transcript, 3)
+
+	srv := NewServer(0, repo.path)
+	req := httptest.NewRequest("GET", "/api/sessions", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: want 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var sessions []stats.SessionSummary
+	decodeJSON(t, w, &sessions)
+	if len(sessions) != 1 {
+		t.Fatalf("sessions: want 1, got %d", len(sessions))
+	}
+	tl := sessions[0].Timeline
+	if sessions[0].SessionID != "session-timeline" {
+		t.Errorf("SessionID: got %q", sessions[0].SessionID)
+	}
+	if tl.Duration() != 30*time.Minute {
+		t.Errorf("Duration: want 30m, got %s", tl.Duration())
+	}
+	if tl.ActiveTime() != 2*time.Minute || len(tl.Gaps) != 1 {
+		t.Errorf("want 2m active and one gap, got %s active and %d gaps", tl.ActiveTime(), len(tl.Gaps))
+	}
+	if len(tl.Commits) != 1 || tl.Commits[0].SHA != sha {
+		t.Errorf("Commits: want [%s], got %+v", sha, tl.Commits)
+	}
+}
+
+func TestExportSite(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("README.md", "# Test\n")
+	repo.commit("Without conversation")
+	repo.writeFile("main.go", "package main\n")
+	sha := repo.commit("Add main")
+	repo.addConversation(sha, "session-site", sampleTranscript(), 2)
+
+	dir := t.TempDir()
+	count, err := NewServer(0, repo.path).ExportSite(dir)
+	if err != nil {
+		t.Fatalf("ExportSite failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count: want 1, got %d", count)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "<script>window.clauditSite = true;</script>") {
+		t.Error("index.html is not switched to static mode")
+	}
+
+	commits, err := os.ReadFile(filepath.Join(dir, "data", "_api_commits.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(commits), `clauditSiteData("/api/commits", [{"sha":"`+sha) {
+		t.Errorf("commits data: got %s", commits)
+	}
+	if strings.Contains(string(commits), "Without conversation") {
+		t.Error("commits data includes a commit without a conversation")
+	}
+
+	for _, query := range []string{"", "_incremental_true", "_diff_true", "_incremental_true_diff_true"} {
+		name := filepath.Join(dir, "data", "_api_commits_"+sha+query+".js")
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Errorf("missing conversation data: %v", err)
+			continue
+		}
+		if !strings.Contains(string(data), `"session_id":"session-site"`) {
+			t.Errorf("%s: got %s", filepath.Base(name), data)
+		}
+	}
+}
+
+func TestExportSiteNoConversations(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+	repo.commit("Without conversation")
+
+	if _, err := NewServer(0, repo.path).ExportSite(t.TempDir()); err == nil {
+		t.Error("expected an error with no conversations to export")
+	}
+}
+
+func TestHandleCommitDetailTree(t *testing.T) {
+	repo := newTestRepo(t)
+	chdir(t, repo.path)
+
+	repo.writeFile("README.md", "# Test\n")
+	sha := repo.commit("Initial commit")
+	message := func(uuid, parent, role, text string) map[string]interface{} {
+		return map[string]interface{}{
+			"uuid": uuid, "parentUuid": parent, "type": role,
+			"message": map[string]interface{}{"role": role, "content": text},
+		}
+	}
+	entries := []map[string]interface{}{
+		message("user-1", "", "user", "Fix the test"),
+		message("assistant-1", "user-1", "assistant", "Retrying"),
+		message("user-2", "assistant-1", "user", "Add a sleep"),
+		message("user-3", "assistant-1", "user", "Mock the clock"),
+	}
+	repo.addConversation(sha, "session-tree", marshalTranscript(entries), 4)
+	srv := NewServer(0, repo.path)
+
+	req := httptest.NewRequest("GET", "/api/commits/"+sha+"?tree=true", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp ConversationResponse
+	decodeJSON(t, w, &resp)
+	if len(resp.Transcript) != 3 || resp.Transcript[2].UUID != "user-3" {
+		t.Fatalf("want the path to user-3, got %+v", resp.Transcript)
+	}
+	if len(resp.Branches) != 1 || resp.Branches[0].After != "assistant-1" || resp.Branches[0].Entries[0].UUID != "user-2" {
+		t.Errorf("want user-2 abandoned after assistant-1, got %+v", resp.Branches)
+	}
+
+	req = httptest.NewRequest("GET", "/api/commits/"+sha, nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	resp = ConversationResponse{}
+	decodeJSON(t, w, &resp)
+	if len(resp.Transcript) != 4 || resp.Branches != nil {
+		t.Errorf("without tree=true, want every entry and no branches, got %d entries and %+v", len(resp.Transcript), resp.Branches)
+	}
+}
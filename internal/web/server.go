@@ -38,8 +38,12 @@ func (s *Server) setupRoutes() {
 	// API endpoints
 	s.mux.HandleFunc("/api/commits", s.handleCommits)
 	s.mux.HandleFunc("/api/commits/", s.handleCommitDetail)
+	s.mux.HandleFunc("/api/attachments/", s.handleAttachment)
+	s.mux.HandleFunc("/api/diff-conversation", s.handleDiffConversation)
 	s.mux.HandleFunc("/api/graph", s.handleGraph)
 	s.mux.HandleFunc("/api/resume/", s.handleResume)
+	s.mux.HandleFunc("/api/blame", s.handleBlame)
+	s.mux.HandleFunc("/api/sessions", s.handleSessions)
 }
 
 // Handler returns the HTTP handler for the server.
@@ -0,0 +1,155 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/DanielJonesEB/claudit/internal/git"
+	"github.com/DanielJonesEB/claudit/internal/storage"
+)
+
+// siteMarker is replaced in index.html to switch the UI to static mode
+const siteMarker = "<!-- claudit:site -->"
+
+// commitDateLayout is the layout of git's %ci dates, as /api/commits gives
+// them
+const commitDateLayout = "2006-01-02 15:04:05 -0700"
+
+// siteDataName matches the characters replaced when naming data files;
+// index.html's siteDataFile must agree
+var siteDataName = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ExportSite writes a static copy of the web UI to dir, together with the
+// API responses it needs to browse every commit with a conversation, so it
+// can be opened without a server. Returns the number of commits exported.
+func (s *Server) ExportSite(dir string) (int, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0755); err != nil {
+		return 0, fmt.Errorf("could not create %s: %w", dir, err)
+	}
+
+	// List every commit with a conversation, on any branch
+	commits, err := siteCommits()
+	if err != nil {
+		return 0, err
+	}
+	if len(commits) == 0 {
+		return 0, fmt.Errorf("no commits with conversations to export")
+	}
+	body, err := json.Marshal(commits)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeSiteData(dir, "/api/commits", body); err != nil {
+		return 0, err
+	}
+	if err := s.writeSiteStatic(dir); err != nil {
+		return 0, err
+	}
+	if err := s.recordSiteData(dir, "/api/sessions"); err != nil {
+		return 0, err
+	}
+
+	// Each view of a conversation the UI can ask for
+	for _, commit := range commits {
+		for _, query := range []string{"", "?incremental=true", "?diff=true", "?incremental=true&diff=true"} {
+			url := "/api/commits/" + commit.SHA + query
+			if err := s.recordSiteData(dir, url); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(commits), nil
+}
+
+// siteCommits lists every commit with a conversation, newest first, as
+// /api/commits describes them
+func siteCommits() ([]CommitInfo, error) {
+	shas, err := git.ListCommitsWithNotes()
+	if err != nil {
+		return nil, fmt.Errorf("could not list commits: %w", err)
+	}
+	if len(shas) == 0 {
+		return nil, nil
+	}
+	metas, err := git.LogCommitsOf(shas, "--no-walk=unsorted")
+	if err != nil {
+		return nil, fmt.Errorf("could not read history: %w", err)
+	}
+	labels, err := storage.AllLabels()
+	if err != nil {
+		return nil, fmt.Errorf("could not read labels: %w", err)
+	}
+
+	commits := make([]CommitInfo, 0, len(metas))
+	for _, meta := range metas {
+		info := CommitInfo{
+			SHA:             meta.SHA,
+			Message:         meta.Subject,
+			Author:          meta.Author,
+			Date:            meta.Date.Format(commitDateLayout),
+			HasConversation: true,
+			Labels:          labels[meta.SHA],
+		}
+		addConversation(&info)
+		commits = append(commits, info)
+	}
+	return commits, nil
+}
+
+// writeSiteStatic copies the UI's static files, switching index.html to
+// read recorded data instead of calling the API
+func (s *Server) writeSiteStatic(dir string) error {
+	staticFS, _ := fs.Sub(staticFiles, "static")
+	return fs.WalkDir(staticFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(staticFS, path)
+		if err != nil {
+			return err
+		}
+		if path == "index.html" {
+			data = []byte(strings.Replace(string(data), siteMarker, "<script>window.clauditSite = true;</script>", 1))
+		}
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return fmt.Errorf("could not write %s: %w", target, err)
+		}
+		return nil
+	})
+}
+
+// recordSiteData serves url through the API and writes the response to the
+// data file the UI loads for it
+func (s *Server) recordSiteData(dir, url string) error {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != http.StatusOK {
+		return fmt.Errorf("could not export %s: %s", url, strings.TrimSpace(rec.Body.String()))
+	}
+	return writeSiteData(dir, url, rec.Body.Bytes())
+}
+
+// writeSiteData writes body to the data file the UI loads for url
+func writeSiteData(dir, url string, body []byte) error {
+	key, err := json.Marshal(url)
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf("clauditSiteData(%s, %s);\n", key, strings.TrimSpace(string(body)))
+	path := filepath.Join(dir, "data", siteDataName.ReplaceAllString(url, "_")+".js")
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package acceptance_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Aggregate Command", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	// Helper to store a transcript on the current commit
+	storeTranscript := func(sessionID, transcript string) string {
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+
+		hookInput := testutil.SampleHookInput(sessionID, transcriptPath, "git commit -m 'test'")
+		_, _, err = testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	// Helper to build a feature branch with two annotated commits and squash it onto master
+	squashFeature := func() (string, string, string) {
+		Expect(repo.Run("git", "checkout", "-b", "feature")).To(Succeed())
+
+		Expect(repo.WriteFile("a.txt", "a")).To(Succeed())
+		Expect(repo.Commit("Add a")).To(Succeed())
+		first := storeTranscript("session-agg", testutil.SampleTranscriptWithIDs(
+			[]string{"u1", "a1"}, []string{"Please add a", "Added a"}))
+
+		Expect(repo.WriteFile("b.txt", "b")).To(Succeed())
+		Expect(repo.Commit("Add b")).To(Succeed())
+		second := storeTranscript("session-agg", testutil.SampleTranscriptWithIDs(
+			[]string{"u1", "a1", "u2", "a2"}, []string{"Please add a", "Added a", "Please add b", "Added b"}))
+
+		Expect(repo.Run("git", "checkout", "master")).To(Succeed())
+		Expect(repo.Run("git", "merge", "--squash", "feature")).To(Succeed())
+		Expect(repo.Run("git", "commit", "--no-gpg-sign", "-m", "Feature (#7)")).To(Succeed())
+		squash, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+
+		return first, second, squash
+	}
+
+	It("combines a range of conversations onto the target commit", func() {
+		first, second, squash := squashFeature()
+
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "aggregate", "master..feature", "--onto", "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("aggregated 2 conversations onto " + squash[:7]))
+		Expect(repo.HasNote("refs/notes/claude-conversations", squash)).To(BeTrue())
+
+		stdout, _, err = testutil.RunClauditInDir(repo.Path, "show", "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("aggregated from 2 commits"))
+		Expect(stdout).To(ContainSubstring(first[:7] + " Add a"))
+		Expect(stdout).To(ContainSubstring(second[:7] + " Add b"))
+		Expect(stdout).To(ContainSubstring("Please add b"))
+
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "show", "HEAD", "--tree")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("--tree can't be used with the aggregated conversation"))
+	})
+
+	It("detects the original commits by patch ID with --auto", func() {
+		_, _, squash := squashFeature()
+
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "aggregate", "--auto")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("refs/heads/feature (by patch-id)"))
+		Expect(repo.HasNote("refs/notes/claude-conversations", squash)).To(BeTrue())
+	})
+
+	It("does not write notes with --dry-run", func() {
+		_, _, squash := squashFeature()
+
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "aggregate", "master..feature", "--onto", "HEAD", "--dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("would aggregate 2 conversations"))
+		Expect(repo.HasNote("refs/notes/claude-conversations", squash)).To(BeFalse())
+	})
+
+	It("refuses to overwrite an existing conversation without --force", func() {
+		squashFeature()
+		storeTranscript("session-other", testutil.SampleTranscript())
+
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "aggregate", "master..feature", "--onto", "HEAD")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("already has a conversation"))
+	})
+})
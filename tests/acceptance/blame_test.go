@@ -0,0 +1,96 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Blame Command", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	// Helper to build a transcript in which the assistant writes a file
+	writeTranscript := func(file, content string) string {
+		entries := []map[string]interface{}{
+			{
+				"uuid": "u1", "type": "user",
+				"message": map[string]interface{}{"role": "user", "content": "Create a greeting program"},
+			},
+			{
+				"uuid": "a1", "parentUuid": "u1", "type": "assistant",
+				"message": map[string]interface{}{
+					"role": "assistant",
+					"content": []map[string]interface{}{
+						{"type": "text", "text": "I'll write a hello world program."},
+						{
+							"type": "tool_use", "id": "t1", "name": "Write",
+							"input": map[string]string{"file_path": filepath.Join(repo.Path, file), "content": content},
+						},
+					},
+				},
+			},
+		}
+		var lines []string
+		for _, entry := range entries {
+			data, err := json.Marshal(entry)
+			Expect(err).NotTo(HaveOccurred())
+			lines = append(lines, string(data))
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	It("links lines to the tool call that wrote them", func() {
+		content := "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"
+		Expect(repo.WriteFile("main.go", content)).To(Succeed())
+		Expect(repo.Commit("Add main")).To(Succeed())
+
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(writeTranscript("main.go", content)), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput("blame-session", transcriptPath, "git commit -m 'Add main'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "blame", "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(`"Add main"`))
+		Expect(stdout).To(ContainSubstring(`println("hello")`))
+		Expect(stdout).To(ContainSubstring("↳ Write in session blame-s"))
+		Expect(stdout).To(ContainSubstring("Prompt: Create a greeting program"))
+		Expect(stdout).To(ContainSubstring("Why: I'll write a hello world program."))
+	})
+
+	It("shows plain blame for commits without conversations", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "blame", "README.md")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(`"Initial commit"`))
+		Expect(stdout).To(ContainSubstring("# Test"))
+		Expect(stdout).NotTo(ContainSubstring("↳"))
+	})
+
+	It("fails for files that do not exist", func() {
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "blame", "missing.go")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("could not blame missing.go"))
+	})
+})
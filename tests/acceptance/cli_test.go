@@ -109,6 +109,35 @@ var _ = Describe("Init Command", func() {
 			Expect(hook.Hooks[0].Timeout).To(Equal(30))
 		})
 
+		It("leaves git's pagers alone by default", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "init")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).NotTo(ContainSubstring("pager.log"))
+
+			_, err = repo.RunOutput("git", "config", "pager.log")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("pages git log and git show through claudit textconv with --log-pager", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "init", "--log-pager")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("pager.log, pager.show"))
+
+			pager, err := repo.RunOutput("git", "config", "pager.log")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pager).To(ContainSubstring("claudit textconv"))
+		})
+
+		It("leaves a pager already configured for git log alone", func() {
+			Expect(repo.Run("git", "config", "pager.log", "less -S")).To(Succeed())
+			_, _, err := testutil.RunClauditInDir(repo.Path, "init", "--log-pager")
+			Expect(err).NotTo(HaveOccurred())
+
+			pager, err := repo.RunOutput("git", "config", "pager.log")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pager).To(ContainSubstring("less -S"))
+		})
+
 		It("installs git hooks", func() {
 			stdout, _, err := testutil.RunClauditInDir(repo.Path, "init")
 			Expect(err).NotTo(HaveOccurred())
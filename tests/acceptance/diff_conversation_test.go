@@ -0,0 +1,89 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Diff Conversation Command", func() {
+	var repo *testutil.GitRepo
+	var first, second string
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		store := func(sessionID, transcript string) string {
+			transcriptPath := filepath.Join(repo.Path, ".git", sessionID+".jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+			hookInput := testutil.SampleHookInput(sessionID, transcriptPath, "git commit -m 'test'")
+			_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+			head, err := repo.GetHead()
+			Expect(err).NotTo(HaveOccurred())
+			return head
+		}
+
+		common := `{"uuid":"u1","type":"user","message":{"role":"user","content":"Fix the flaky test"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Looking at it"}]}}
+`
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Retry")).To(Succeed())
+		first = store("session-retry", common+`{"uuid":"u2","parentUuid":"a1","type":"user","message":{"role":"user","content":"Add a retry"}}
+`)
+
+		Expect(repo.WriteFile("README.md", "# Test\n\nClock")).To(Succeed())
+		Expect(repo.Commit("Mock clock")).To(Succeed())
+		second = store("session-clock", common+`{"uuid":"u3","parentUuid":"a1","type":"user","message":{"role":"user","content":"Mock the clock"}}
+`)
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	It("shows the common history once and the tails side by side", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "diff-conversation", first, second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Common: 2 entries, then 1 in A and 1 in B"))
+		Expect(stdout).To(ContainSubstring("Diverged after a1"))
+		Expect(stdout).To(MatchRegexp(`Add a retry\s+│\s+Mock the clock`))
+	})
+
+	It("writes the comparison as JSON", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "diff-conversation", first, second, "--format", "json")
+		Expect(err).NotTo(HaveOccurred())
+
+		var record struct {
+			CommitA  struct{ SHA string } `json:"commit_a"`
+			Common   []json.RawMessage    `json:"common"`
+			ForkUUID string               `json:"fork_uuid"`
+			A        []json.RawMessage    `json:"a"`
+			B        []json.RawMessage    `json:"b"`
+		}
+		Expect(json.Unmarshal([]byte(stdout), &record)).To(Succeed())
+		Expect(record.CommitA.SHA).To(Equal(first))
+		Expect(record.Common).To(HaveLen(2))
+		Expect(record.ForkUUID).To(Equal("a1"))
+		Expect(record.A).To(HaveLen(1))
+		Expect(record.B).To(HaveLen(1))
+	})
+
+	It("fails when a commit has no conversation", func() {
+		Expect(repo.WriteFile("README.md", "# Test\n\nBy hand")).To(Succeed())
+		Expect(repo.Commit("Edit by hand")).To(Succeed())
+
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "diff-conversation", first, "HEAD")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("no conversation found"))
+	})
+})
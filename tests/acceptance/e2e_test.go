@@ -67,7 +67,7 @@ var _ = Describe("End-to-End Store Flow", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		var stored map[string]interface{}
-		Expect(json.Unmarshal([]byte(noteContent), &stored)).To(Succeed())
+		Expect(json.Unmarshal([]byte(testutil.NotePayload(noteContent)), &stored)).To(Succeed())
 		Expect(stored["session_id"]).To(Equal("e2e-session"))
 
 		// Step 5: Push notes
@@ -99,7 +99,7 @@ var _ = Describe("End-to-End Store Flow", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		var clonedStored map[string]interface{}
-		Expect(json.Unmarshal([]byte(clonedNote), &clonedStored)).To(Succeed())
+		Expect(json.Unmarshal([]byte(testutil.NotePayload(clonedNote)), &clonedStored)).To(Succeed())
 		Expect(clonedStored["session_id"]).To(Equal("e2e-session"))
 	})
 })
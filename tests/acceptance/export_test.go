@@ -0,0 +1,150 @@
+package acceptance_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Export Command", func() {
+	var (
+		repo          *testutil.GitRepo
+		first, second string
+	)
+
+	transcript := []string{
+		`{"uuid":"u-1","type":"user","message":{"role":"user","content":"Create the parser"}}`,
+		`{"uuid":"a-1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t-1","name":"Edit","input":{"file_path":"parser.go","old_string":"old parser","new_string":"new parser"}}]}}`,
+		`{"uuid":"r-1","type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t-1","content":"ok"}]}}`,
+		`{"uuid":"u-2","type":"user","message":{"role":"user","content":"Add a lexer"}}`,
+		`{"uuid":"a-2","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Lexer added."}]}}`,
+	}
+
+	// Helper to commit a file and store the given transcript lines on it
+	commitWithLines := func(file, message string, lines []string) string {
+		Expect(repo.WriteFile(file, "x")).To(Succeed())
+		Expect(repo.Commit(message)).To(Succeed())
+
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput("session-export", transcriptPath, "git commit -m 'test'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+
+		first = commitWithLines("parser.go", "Add parser", transcript[:3])
+		second = commitWithLines("lexer.go", "Add lexer", transcript)
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	It("exports HEAD's conversation as Markdown by default", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("# Add lexer"))
+		Expect(stdout).To(ContainSubstring("- **Showing:** 2 entries since " + first[:7]))
+		Expect(stdout).To(ContainSubstring("## User\n\nAdd a lexer"))
+		Expect(stdout).NotTo(ContainSubstring("Create the parser"))
+	})
+
+	It("shows tool calls as collapsible diffs", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", first)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("<summary>Edit parser.go</summary>"))
+		Expect(stdout).To(ContainSubstring("```diff\n-old parser\n+new parser\n```"))
+	})
+
+	It("exports the full session with --full", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", "--full")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("5 entries (full session)"))
+		Expect(stdout).To(ContainSubstring("Create the parser"))
+	})
+
+	It("exports every conversation in a range, oldest first", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", "HEAD~2..HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(MatchRegexp(`# Add parser[\s\S]*---[\s\S]*# Add lexer`))
+		Expect(stdout).NotTo(ContainSubstring("# Initial commit"))
+	})
+
+	It("exports the recorded transcript with jsonl", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", "--format", "jsonl", "--full")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(Equal(strings.Join(transcript, "\n") + "\n"))
+	})
+
+	It("writes HTML to a file", func() {
+		path := filepath.Join(repo.Path, ".git", "export.html")
+		_, _, err := testutil.RunClauditInDir(repo.Path, "export", "--format", "html", "-o", path)
+		Expect(err).NotTo(HaveOccurred())
+
+		page, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(page)).To(ContainSubstring("<title>Add lexer</title>"))
+		Expect(string(page)).To(ContainSubstring(`<div class="text">Lexer added.</div>`))
+	})
+
+	It("fails for a commit without a conversation", func() {
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "export", "HEAD~2")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("no conversation found for commit"))
+	})
+
+	It("rejects formats it cannot export", func() {
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "export", "--format", "yaml")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring(`unknown export format "yaml"`))
+
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", "--help")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(`Export format: markdown, html, jsonl (default "markdown")`))
+	})
+
+	It("generates a static site with --site", func() {
+		site := filepath.Join(repo.Path, ".git", "site")
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", "--site", site)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Exported the conversations of 2 commits"))
+
+		Expect(filepath.Join(site, "index.html")).To(BeAnExistingFile())
+		Expect(filepath.Join(site, "data", "_api_commits_"+second+"_incremental_true.js")).To(BeAnExistingFile())
+	})
+
+	It("includes conversations on other branches in the site", func() {
+		Expect(repo.Run("git", "checkout", "-b", "feature")).To(Succeed())
+		branched := commitWithLines("feature.go", "Add feature", transcript)
+		Expect(repo.Run("git", "checkout", "master")).To(Succeed())
+
+		site := filepath.Join(repo.Path, ".git", "site")
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "export", "--site", site)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Exported the conversations of 3 commits"))
+
+		commits, err := os.ReadFile(filepath.Join(site, "data", "_api_commits.js"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(commits)).To(ContainSubstring(branched))
+		Expect(filepath.Join(site, "data", "_api_commits_"+branched+".js")).To(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,174 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Import Command", func() {
+	const (
+		sessionA = "5f0c2a1e-7b3d-4c8e-9a21-3e6f0d4b8c17"
+		sessionB = "9d47b6e0-2c81-4f3a-b5e9-71a0c8d2e643"
+	)
+
+	var (
+		repo     *testutil.GitRepo
+		env      *testutil.ClaudeEnv
+		repoPath string
+		start    time.Time
+	)
+
+	// line builds a session file line at the given offset from start
+	line := func(sessionID, uuid, entryType string, offset time.Duration, content string) string {
+		return fmt.Sprintf(`{"uuid":%q,"sessionId":%q,"type":%q,"timestamp":%q,"cwd":%q,"gitBranch":"master","message":{"role":%q,"content":%s}}`,
+			uuid, sessionID, entryType, start.Add(offset).Format(time.RFC3339), repoPath, entryType, content)
+	}
+
+	prompt := func(sessionID, uuid string, offset time.Duration, text string) string {
+		content, _ := json.Marshal(text)
+		return line(sessionID, uuid, "user", offset, string(content))
+	}
+
+	// commitAt commits with the given author date offset from start
+	commitAt := func(message string, offset time.Duration) string {
+		Expect(repo.WriteFile(message+".txt", message)).To(Succeed())
+		Expect(repo.Run("git", "add", "-A")).To(Succeed())
+		Expect(repo.Run("git", "commit", "--no-gpg-sign", "-m", message,
+			"--date", start.Add(offset).Format(time.RFC3339))).To(Succeed())
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	writeSession := func(sessionID string, lines ...string) {
+		_, err := env.WriteSessionFile(repoPath, sessionID, []byte(strings.Join(lines, "\n")+"\n"))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	runImport := func(args ...string) (string, string) {
+		stdout, stderr, err := testutil.RunClauditInDirWithEnv(repo.Path, env.GetEnvVars(), append([]string{"import"}, args...)...)
+		Expect(err).NotTo(HaveOccurred(), stderr)
+		return stdout, stderr
+	}
+
+	hasNote := func(sha string) bool {
+		return repo.Run("git", "notes", "--ref", "refs/notes/claude-conversations", "show", sha) == nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+		env, err = testutil.NewClaudeEnv()
+		Expect(err).NotTo(HaveOccurred())
+
+		repoPath, err = filepath.EvalSymlinks(repo.Path)
+		Expect(err).NotTo(HaveOccurred())
+		start = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+		if env != nil {
+			env.Cleanup()
+		}
+	})
+
+	Describe("matching commits to sessions", func() {
+		var byClaude, byHand, later string
+
+		BeforeEach(func() {
+			byClaude = commitAt("Add parser", time.Minute)
+			byHand = commitAt("Tweak parser", 4*time.Minute)
+			later = commitAt("Unrelated", 3*time.Hour)
+
+			writeSession(sessionA,
+				prompt(sessionA, "u1", 0, "Add a parser and commit it"),
+				line(sessionA, "a1", "assistant", time.Minute,
+					`[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"git commit -m 'Add parser'"}}]`),
+				line(sessionA, "r1", "user", time.Minute,
+					fmt.Sprintf(`[{"type":"tool_result","tool_use_id":"t1","content":"[master %s] Add parser\n 1 file changed"}]`, byClaude[:7])),
+				line(sessionA, "a2", "assistant", 2*time.Minute, `[{"type":"text","text":"Committed."}]`),
+				prompt(sessionA, "u2", 10*time.Minute, "What next?"),
+			)
+		})
+
+		It("previews imports with --dry-run without writing notes", func() {
+			stdout, _ := runImport("--dry-run")
+			Expect(stdout).To(ContainSubstring("would import " + byClaude[:7] + " from session " + sessionA[:7] + " (3 messages, matched by commit output): Add parser"))
+			Expect(stdout).To(ContainSubstring("would import " + byHand[:7] + " from session " + sessionA[:7] + " (4 messages, matched by timestamp): Tweak parser"))
+			Expect(stdout).NotTo(ContainSubstring(later[:7]))
+			Expect(stdout).To(ContainSubstring("2 conversations would be imported from 1 session files"))
+			Expect(hasNote(byClaude)).To(BeFalse())
+		})
+
+		It("stores each commit's conversation as it was at the time", func() {
+			runImport()
+			Expect(hasNote(byClaude)).To(BeTrue())
+			Expect(hasNote(byHand)).To(BeTrue())
+			Expect(hasNote(later)).To(BeFalse())
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--full", byClaude)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Add a parser and commit it"))
+			Expect(stdout).NotTo(ContainSubstring("Committed."))
+
+			stdout, _, err = testutil.RunClauditInDir(repo.Path, "show", byHand)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("since " + byClaude[:7]))
+			Expect(stdout).To(ContainSubstring("Committed."))
+			Expect(stdout).NotTo(ContainSubstring("What next?"))
+		})
+
+		It("skips commits that already have a conversation", func() {
+			runImport()
+			stdout, _ := runImport()
+			Expect(stdout).To(ContainSubstring("no conversations to import from 1 session files"))
+		})
+
+		It("reports commits made while several sessions were active", func() {
+			writeSession(sessionB,
+				prompt(sessionB, "b1", 3*time.Minute, "Meanwhile, in another terminal"),
+			)
+
+			stdout, stderr := runImport("--dry-run")
+			Expect(stdout).To(ContainSubstring("would import " + byClaude[:7]))
+			Expect(stdout).NotTo(ContainSubstring("would import " + byHand[:7]))
+			Expect(stderr).To(ContainSubstring("skipped " + byHand[:7] + ": made while sessions " + sessionA + ", " + sessionB + " were active"))
+		})
+
+		It("outputs the matches as JSON", func() {
+			stdout, _ := runImport("--dry-run", "--format", "json")
+
+			var records []struct {
+				SHA       string `json:"sha"`
+				SessionID string `json:"session_id"`
+				Match     string `json:"match"`
+				Imported  bool   `json:"imported"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &records)).To(Succeed())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].SHA).To(Equal(byClaude))
+			Expect(records[0].SessionID).To(Equal(sessionA))
+			Expect(records[0].Match).To(Equal("commit output"))
+			Expect(records[0].Imported).To(BeFalse())
+			Expect(records[1].Match).To(Equal("timestamp"))
+		})
+	})
+
+	It("reports when there are no session files", func() {
+		commitAt("Initial commit", 0)
+		stdout, _ := runImport()
+		Expect(stdout).To(ContainSubstring("no session files found in"))
+	})
+})
@@ -1,6 +1,7 @@
 package acceptance_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -66,6 +67,41 @@ var _ = Describe("List Command", func() {
 			Expect(stdout).To(MatchRegexp(`\d+ messages`))
 		})
 
+		It("shows the conversation's summary", func() {
+			storeConversation("session-list-summary")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Hello, can you help me with a task?"))
+
+			stdout, _, err = testutil.RunClauditInDir(repo.Path, "list", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring(`"summary": "Hello, can you help me with a task?`))
+		})
+
+		It("summarizes with the configured command", func() {
+			clauditDir := filepath.Join(repo.Path, ".claudit")
+			Expect(os.MkdirAll(clauditDir, 0755)).To(Succeed())
+			config := `{"summarizer": "echo Summarized session $CLAUDIT_SESSION_ID"}`
+			Expect(os.WriteFile(filepath.Join(clauditDir, "config"), []byte(config), 0644)).To(Succeed())
+			storeConversation("session-list-command")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Summarized session session-list-command"))
+		})
+
+		It("falls back to an offline summary when the command fails", func() {
+			clauditDir := filepath.Join(repo.Path, ".claudit")
+			Expect(os.MkdirAll(clauditDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(clauditDir, "config"), []byte(`{"summarizer": "exit 1"}`), 0644)).To(Succeed())
+			storeConversation("session-list-fallback")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Hello, can you help me with a task?"))
+		})
+
 		It("lists multiple conversations", func() {
 			// First commit with conversation
 			storeConversation("session-multi-1")
@@ -142,6 +178,94 @@ var _ = Describe("List Command", func() {
 		})
 	})
 
+	Describe("filtering", func() {
+		// Helper to store a conversation with a given number of messages on HEAD
+		storeMessages := func(sessionID string, count int) string {
+			var uuids []string
+			for i := 0; i < count; i++ {
+				uuids = append(uuids, fmt.Sprintf("%s-%d", sessionID, i))
+			}
+			transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(testutil.SampleTranscriptWithIDs(uuids, nil)), 0644)).To(Succeed())
+
+			hookInput := testutil.SampleHookInput(sessionID, transcriptPath, "git commit -m 'test'")
+			_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+
+			head, err := repo.GetHead()
+			Expect(err).NotTo(HaveOccurred())
+			return head
+		}
+
+		var initial, featureOne, featureTwo string
+
+		BeforeEach(func() {
+			initial = storeMessages("session-alpha", 2)
+
+			Expect(repo.Run("git", "checkout", "-q", "-b", "feature")).To(Succeed())
+			Expect(repo.WriteFile("one.txt", "one")).To(Succeed())
+			Expect(repo.Commit("Feature one")).To(Succeed())
+			featureOne = storeMessages("session-beta", 6)
+
+			Expect(repo.WriteFile("two.txt", "two")).To(Succeed())
+			Expect(repo.Run("git", "add", "-A")).To(Succeed())
+			Expect(repo.Run("git", "commit", "-q", "--no-gpg-sign", "--author=Alice <alice@example.com>", "-m", "Feature two")).To(Succeed())
+			featureTwo = storeMessages("session-beta", 8)
+		})
+
+		// Helper returning the short SHAs listed, in order
+		listed := func(args ...string) []string {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, append([]string{"list", "--template", "{{short .sha}}"}, args...)...)
+			Expect(err).NotTo(HaveOccurred())
+			return strings.Fields(stdout)
+		}
+
+		It("accepts revision ranges", func() {
+			Expect(listed("master..feature")).To(Equal([]string{featureTwo[:7], featureOne[:7]}))
+		})
+
+		It("filters by branch", func() {
+			Expect(listed("--branch", "master")).To(Equal([]string{initial[:7]}))
+		})
+
+		It("filters by author", func() {
+			Expect(listed("--author", "Alice")).To(Equal([]string{featureTwo[:7]}))
+		})
+
+		It("filters by session ID prefix", func() {
+			Expect(listed("--session", "session-b")).To(Equal([]string{featureTwo[:7], featureOne[:7]}))
+		})
+
+		It("filters by message count", func() {
+			Expect(listed("--min-messages", "7")).To(Equal([]string{featureTwo[:7]}))
+		})
+
+		It("filters by date", func() {
+			Expect(listed("--since", "1.hour")).To(HaveLen(3))
+			Expect(listed("--until", "2000-01-01")).To(BeEmpty())
+		})
+
+		It("sorts oldest first or by message count", func() {
+			Expect(listed("--sort", "oldest")).To(Equal([]string{initial[:7], featureOne[:7], featureTwo[:7]}))
+			Expect(listed("--sort", "messages")).To(Equal([]string{featureTwo[:7], featureOne[:7], initial[:7]}))
+		})
+
+		It("pages through the list with --skip and --limit", func() {
+			Expect(listed("--skip", "1", "--limit", "1")).To(Equal([]string{featureOne[:7]}))
+			Expect(listed("--skip", "5")).To(BeEmpty())
+		})
+
+		It("passes git log options after --", func() {
+			Expect(listed("feature", "--", "--max-count=2")).To(Equal([]string{featureTwo[:7], featureOne[:7]}))
+		})
+
+		It("rejects unknown sort orders", func() {
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "list", "--sort", "sideways")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring(`unknown sort order "sideways"`))
+		})
+	})
+
 	Describe("outside git repository", func() {
 		It("fails with error", func() {
 			// Create a temp directory that's not a git repo
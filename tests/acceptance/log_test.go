@@ -0,0 +1,137 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Log Command", func() {
+	var (
+		repo                    *testutil.GitRepo
+		humanSHA, first, second string
+	)
+
+	// Transcript lines for a session in which Claude writes two files
+	sessionLines := func() []string {
+		write := func(uuid, prompt, file, summary string) []string {
+			input, err := json.Marshal(map[string]string{"file_path": filepath.Join(repo.Path, file), "content": "x"})
+			Expect(err).NotTo(HaveOccurred())
+			return []string{
+				`{"uuid":"u-` + uuid + `","type":"user","message":{"role":"user","content":"` + prompt + `"}}`,
+				`{"uuid":"a-` + uuid + `","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t-` + uuid + `","name":"Write","input":` + string(input) + `}]}}`,
+				`{"uuid":"s-` + uuid + `","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"` + summary + `"}]}}`,
+			}
+		}
+		return append(write("1", "Create the parser", "parser.go", "Parser created."),
+			write("2", "Add a lexer", "lexer.go", "Lexer added.")...)
+	}
+
+	// Helper to store a transcript of the given lines on HEAD
+	storeLines := func(lines []string) string {
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput("session-log", transcriptPath, "git commit -m 'test'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Written by hand")).To(Succeed())
+		humanSHA, err = repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+
+		lines := sessionLines()
+		Expect(repo.WriteFile("parser.go", "x")).To(Succeed())
+		Expect(repo.Commit("Add parser")).To(Succeed())
+		first = storeLines(lines[:3])
+
+		Expect(repo.WriteFile("lexer.go", "x")).To(Succeed())
+		Expect(repo.Commit("Add lexer")).To(Succeed())
+		second = storeLines(lines)
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	It("lists every commit, newest first", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "log")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(MatchRegexp(second[:7] + ` Add lexer[\s\S]*` + first[:7] + ` Add parser[\s\S]*` + humanSHA[:7] + ` Written by hand`))
+	})
+
+	It("digests only the conversation since the previous commit", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "log", "--", "-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Session session, 6 messages (since " + first[:7] + ")"))
+		Expect(stdout).To(MatchRegexp(`Prompt:.*Add a lexer`))
+		Expect(stdout).To(MatchRegexp(`Summary:.*Lexer added\.`))
+		Expect(stdout).To(MatchRegexp(`Tools:.*Write ×1`))
+		Expect(stdout).To(MatchRegexp(`Files:.*lexer\.go`))
+		Expect(stdout).NotTo(ContainSubstring("Create the parser"))
+	})
+
+	It("shows the first commit of a session with the full session", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "log", "--", "-1", first)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("(full session)"))
+		Expect(stdout).To(MatchRegexp(`Prompt:.*Create the parser`))
+	})
+
+	It("accepts revision ranges", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "log", humanSHA+".."+first)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Add parser"))
+		Expect(stdout).NotTo(ContainSubstring("Add lexer"))
+		Expect(stdout).NotTo(ContainSubstring("Written by hand"))
+	})
+
+	It("draws the commit graph with --graph", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "log", "--graph")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("* " + second[:7] + " Add lexer"))
+		Expect(stdout).To(MatchRegexp(`\|\s+.*Prompt:.*Add a lexer`))
+	})
+
+	It("outputs digests as JSON", func() {
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "log", "--format", "json")
+		Expect(err).NotTo(HaveOccurred())
+
+		var entries []struct {
+			SHA       string `json:"sha"`
+			ParentSHA string `json:"parent_sha"`
+			Checksum  string `json:"checksum"`
+			Digest    *struct {
+				Prompts []string `json:"prompts"`
+				Summary string   `json:"summary"`
+				Files   []string `json:"files"`
+			} `json:"digest"`
+		}
+		Expect(json.Unmarshal([]byte(stdout), &entries)).To(Succeed())
+		Expect(entries).To(HaveLen(3))
+		Expect(entries[0].SHA).To(Equal(second))
+		Expect(entries[0].ParentSHA).To(Equal(first))
+		Expect(entries[0].Digest.Prompts).To(Equal([]string{"Add a lexer"}))
+		Expect(entries[0].Digest.Files).To(Equal([]string{"lexer.go"}))
+		Expect(entries[2].Checksum).To(Equal("none"))
+		Expect(entries[2].Digest).To(BeNil())
+	})
+})
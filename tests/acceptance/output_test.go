@@ -0,0 +1,199 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Output Formats", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	// Helper to store the sample conversation on a new commit
+	commitWithConversation := func(sessionID, message string) string {
+		Expect(repo.WriteFile(sessionID+".txt", sessionID)).To(Succeed())
+		Expect(repo.Commit(message)).To(Succeed())
+
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(testutil.SampleTranscript()), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput(sessionID, transcriptPath, "git commit -m 'test'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	type commitRecord struct {
+		SHA          string `json:"sha"`
+		Date         string `json:"date"`
+		Message      string `json:"message"`
+		SessionID    string `json:"session_id"`
+		MessageCount int    `json:"message_count"`
+		Checksum     string `json:"checksum"`
+	}
+
+	Describe("list", func() {
+		var first, second string
+
+		BeforeEach(func() {
+			first = commitWithConversation("session-one", "First change")
+			second = commitWithConversation("session-two", "Second change")
+		})
+
+		It("outputs commits as a JSON array", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var records []commitRecord
+			Expect(json.Unmarshal([]byte(stdout), &records)).To(Succeed())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].SHA).To(Equal(second))
+			Expect(records[0].Message).To(Equal("Second change"))
+			Expect(records[0].SessionID).To(Equal("session-two"))
+			Expect(records[0].MessageCount).To(BeNumerically(">", 0))
+			Expect(records[0].Checksum).To(Equal("valid"))
+			Expect(records[0].Date).To(MatchRegexp(`^\d{4}-\d{2}-\d{2}T`))
+			Expect(records[1].SHA).To(Equal(first))
+		})
+
+		It("outputs one JSON object per line with jsonl", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "jsonl")
+			Expect(err).NotTo(HaveOccurred())
+
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			Expect(lines).To(HaveLen(2))
+			var record commitRecord
+			Expect(json.Unmarshal([]byte(lines[1]), &record)).To(Succeed())
+			Expect(record.SessionID).To(Equal("session-one"))
+		})
+
+		It("outputs YAML with the same field names", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("- sha: " + second))
+			Expect(stdout).To(ContainSubstring("  session_id: session-two"))
+			Expect(stdout).To(ContainSubstring("  checksum: valid"))
+		})
+
+		It("formats each commit with a template", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list", "--template", "{{short .sha}} {{.session_id}} {{.message}}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(Equal(second[:7] + " session-two Second change\n" + first[:7] + " session-one First change\n"))
+		})
+
+		It("outputs an empty array when there are no conversations", func() {
+			Expect(repo.Run("git", "notes", "--ref", "refs/notes/claude-conversations", "remove", first, second)).To(Succeed())
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.TrimSpace(stdout)).To(Equal("[]"))
+		})
+	})
+
+	Describe("show", func() {
+		It("outputs the conversation with its entries", func() {
+			sha := commitWithConversation("session-show", "Add feature")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var record struct {
+				commitRecord
+				Incremental bool `json:"incremental"`
+				Entries     []struct {
+					UUID string `json:"uuid"`
+					Type string `json:"type"`
+				} `json:"entries"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &record)).To(Succeed())
+			Expect(record.SHA).To(Equal(sha))
+			Expect(record.SessionID).To(Equal("session-show"))
+			Expect(record.Incremental).To(BeFalse())
+			Expect(record.Entries).NotTo(BeEmpty())
+			Expect(record.Entries[0].UUID).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("doctor", func() {
+		It("reports each check and exits non-zero on failure", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "doctor", "--format", "json")
+			Expect(err).To(HaveOccurred())
+
+			var diagnosis struct {
+				OK     bool `json:"ok"`
+				Checks []struct {
+					Name   string `json:"name"`
+					Status string `json:"status"`
+				} `json:"checks"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &diagnosis)).To(Succeed())
+			Expect(diagnosis.OK).To(BeFalse())
+			Expect(diagnosis.Checks).To(HaveLen(4))
+			Expect(diagnosis.Checks[0].Name).To(Equal("git repository"))
+			Expect(diagnosis.Checks[0].Status).To(Equal("ok"))
+			Expect(diagnosis.Checks[2].Status).To(Equal("fail"))
+		})
+	})
+
+	Describe("sync", func() {
+		It("reports a failed push", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "sync", "push", "--remote", "nowhere", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var result struct {
+				Action string `json:"action"`
+				Remote string `json:"remote"`
+				OK     bool   `json:"ok"`
+				Error  string `json:"error"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &result)).To(Succeed())
+			Expect(result.Action).To(Equal("push"))
+			Expect(result.Remote).To(Equal("nowhere"))
+			Expect(result.OK).To(BeFalse())
+			Expect(result.Error).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("invalid options", func() {
+		It("rejects unknown formats", func() {
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "xml")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring(`unknown format "xml"`))
+		})
+
+		It("requires a template for the template format", func() {
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "template")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring("--format template requires --template"))
+		})
+
+		It("rejects formats on commands without machine-readable output", func() {
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "init", "--format", "json")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring("'claudit init' does not support --format json"))
+		})
+	})
+})
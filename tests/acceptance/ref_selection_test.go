@@ -42,12 +42,24 @@ var _ = Describe("Notes Ref", func() {
 			_, _, err := testutil.RunClauditInDir(repo.Path, "init")
 			Expect(err).NotTo(HaveOccurred())
 
-			cmd := exec.Command("git", "config", "notes.rewriteRef")
+			cmd := exec.Command("git", "config", "--get-all", "notes.rewriteRef")
 			cmd.Dir = repo.Path
 			output, err := cmd.Output()
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(output)).To(ContainSubstring("refs/notes/claude-conversations"))
 		})
+
+		It("adds claude-labels to notes.rewriteRef once, keeping other refs", func() {
+			Expect(repo.Run("git", "config", "notes.rewriteRef", "refs/notes/commits")).To(Succeed())
+			for range 2 {
+				_, _, err := testutil.RunClauditInDir(repo.Path, "init")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			output, err := repo.RunOutput("git", "config", "--get-all", "notes.rewriteRef")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("refs/notes/commits\nrefs/notes/claude-conversations\nrefs/notes/claude-labels\n"))
+		})
 	})
 
 	Describe("note storage isolation", func() {
@@ -1,8 +1,11 @@
 package acceptance_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -96,6 +99,125 @@ var _ = Describe("Show Command", func() {
 		})
 	})
 
+	Describe("filters", func() {
+		It("shows only entries of the given types", func() {
+			storeConversation("session-filter-only")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--only", "user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Filtered: 2 entries hidden"))
+			Expect(stdout).To(ContainSubstring("Please create a file"))
+			Expect(stdout).NotTo(ContainSubstring("Assistant:"))
+		})
+
+		It("shows only calls to the given tools", func() {
+			storeConversation("session-filter-tools")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--tools", "Read")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("I'll create that file"))
+			Expect(stdout).NotTo(ContainSubstring("tool: Bash"))
+		})
+
+		It("shows only entries matching --grep", func() {
+			storeConversation("session-filter-grep")
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--grep", "test\\.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Showing: 2 entries"))
+			Expect(stdout).NotTo(ContainSubstring("Hello, can you help"))
+		})
+
+		It("rejects unknown thinking modes", func() {
+			storeConversation("session-filter-thinking")
+
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "show", "--thinking", "some")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring("unknown thinking mode"))
+		})
+	})
+
+	Describe("attachments", func() {
+		BeforeEach(func() {
+			transcript := `{"uuid":"u1","type":"user","message":{"role":"user","content":[{"type":"text","text":"This looks broken"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBORw0KGgo="}}]}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"I see"}]}}
+`
+			transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+			hookInput := testutil.SampleHookInput("session-attachments", transcriptPath, "git commit -m 'test'")
+			_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("shows a placeholder for images", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("[image]"))
+			Expect(stdout).To(ContainSubstring("image/png"))
+		})
+
+		It("extracts attachments to a directory", func() {
+			dir := filepath.Join(repo.Path, ".git", "attachments")
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--extract-attachments", dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Extracted 1 attachments"))
+			Expect(stdout).NotTo(ContainSubstring("I see"))
+
+			data, err := os.ReadFile(filepath.Join(dir, "u1.1.png"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("\x89PNG\r\n\x1a\n"))
+		})
+	})
+
+	Describe("tree", func() {
+		BeforeEach(func() {
+			transcript := `{"uuid":"u1","type":"user","message":{"role":"user","content":"Fix the flaky test"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Adding a retry"}]}}
+{"uuid":"u2","parentUuid":"a1","type":"user","message":{"role":"user","content":"Add a sleep"}}
+{"uuid":"a2","parentUuid":"u2","type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Sleeping for a second"}]}}
+{"uuid":"u3","parentUuid":"a1","type":"user","message":{"role":"user","content":"Mock the clock instead"}}
+`
+			transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+			hookInput := testutil.SampleHookInput("session-tree", transcriptPath, "git commit -m 'test'")
+			_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("shows the path to the commit with abandoned branches collapsed", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--tree")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Branches: 1 abandoned"))
+			Expect(stdout).To(ContainSubstring("abandoned branch, 2 messages: Add a sleep"))
+			Expect(stdout).To(ContainSubstring("Mock the clock instead"))
+			Expect(stdout).NotTo(ContainSubstring("Sleeping for a second"))
+		})
+
+		It("expands abandoned branches with --branches", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--tree", "--branches")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Sleeping for a second"))
+		})
+
+		It("writes the branches as JSON", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--tree", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var record struct {
+				Entries  []json.RawMessage `json:"entries"`
+				Branches []struct {
+					ForkUUID string            `json:"fork_uuid"`
+					Entries  []json.RawMessage `json:"entries"`
+				} `json:"branches"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &record)).To(Succeed())
+			Expect(record.Entries).To(HaveLen(3))
+			Expect(record.Branches).To(HaveLen(1))
+			Expect(record.Branches[0].ForkUUID).To(Equal("a1"))
+			Expect(record.Branches[0].Entries).To(HaveLen(2))
+		})
+	})
+
 	Describe("without conversation", func() {
 		It("shows error when commit has no conversation", func() {
 			head, err := repo.GetHead()
@@ -296,4 +418,137 @@ var _ = Describe("Show Command", func() {
 			Expect(stdout).To(ContainSubstring("full session"))
 		})
 	})
+
+	Describe("diff annotation", func() {
+		BeforeEach(func() {
+			Expect(repo.WriteFile("main.go", "package main\n")).To(Succeed())
+			Expect(repo.WriteFile("NOTES.md", "written by hand\n")).To(Succeed())
+			Expect(repo.Commit("Add main")).To(Succeed())
+
+			toolUse := func(id, file, content string) string {
+				input, err := json.Marshal(map[string]string{"file_path": filepath.Join(repo.Path, file), "content": content})
+				Expect(err).NotTo(HaveOccurred())
+				return `{"type":"tool_use","id":"` + id + `","name":"Write","input":` + string(input) + `}`
+			}
+			transcript := `{"uuid":"u1","type":"user","message":{"role":"user","content":"Create main.go"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[` +
+				toolUse("t1", "main.go", "package main\n") + `,` + toolUse("t2", "scratch.txt", "draft") + `]}}
+`
+			transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+
+			hookInput := testutil.SampleHookInput("session-diff", transcriptPath, "git commit -m 'Add main'")
+			_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("interleaves hunks with the tool calls that produced them", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--diff")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("main.go @@ -0,0 +1 @@"))
+			Expect(stdout).To(ContainSubstring("+package main"))
+		})
+
+		It("flags edits that are not in the commit", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--diff")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("⚠ edit not in commit"))
+		})
+
+		It("lists changes no tool call explains", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--diff")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Changes not made by any tool call, likely human edits (1 of 2 hunks)"))
+			Expect(stdout).To(ContainSubstring("+written by hand"))
+		})
+
+		It("omits the diff without --diff", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).NotTo(ContainSubstring("@@"))
+		})
+	})
+
+	Describe("tool call diffs", func() {
+		BeforeEach(func() {
+			Expect(repo.WriteFile("main.go", "package main\n\nfunc main() {}\n")).To(Succeed())
+			Expect(repo.Commit("Add main")).To(Succeed())
+			Expect(repo.WriteFile("main.go", "package main\n\nfunc main() { run() }\n\nfunc run() {}\n")).To(Succeed())
+			Expect(repo.Commit("Call run")).To(Succeed())
+
+			path := filepath.Join(repo.Path, "main.go")
+			edit, err := json.Marshal(map[string]string{"file_path": path, "old_string": "func main() {}", "new_string": "func main() { run() }"})
+			Expect(err).NotTo(HaveOccurred())
+			write, err := json.Marshal(map[string]string{"file_path": path, "content": "package main\n\nfunc main() { run() }\n\nfunc run() {}\n"})
+			Expect(err).NotTo(HaveOccurred())
+			transcript := `{"uuid":"u1","type":"user","message":{"role":"user","content":"Call run"}}
+{"uuid":"a1","parentUuid":"u1","type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":` + string(edit) + `},{"type":"tool_use","id":"t2","name":"Write","input":` + string(write) + `}]}}
+`
+			transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+
+			hookInput := testutil.SampleHookInput("session-edit", transcriptPath, "git commit -m 'Call run'")
+			_, _, err = testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("shows edits as unified diffs", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("@@ -1,1 +1,1 @@"))
+			Expect(stdout).To(ContainSubstring("-func main() {}"))
+			Expect(stdout).To(ContainSubstring("+func main() { run() }"))
+		})
+
+		It("diffs writes against the file as the parent commit and earlier edits left it", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("@@ -1,3 +1,5 @@"))
+			Expect(stdout).NotTo(ContainSubstring("+package main"))
+			// Only the Edit removes the old line; the Write starts from its result
+			Expect(strings.Count(stdout, "-func main() {}")).To(Equal(1))
+		})
+
+		It("doesn't diff writes against the parent commit with --full", func() {
+			// The parent doesn't hold the files as they were before earlier commits
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--full")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).NotTo(ContainSubstring("@@ -1,3 +1,5 @@"))
+			Expect(stdout).To(ContainSubstring("content:"))
+		})
+	})
+
+	Describe("timeline", func() {
+		BeforeEach(func() {
+			start := time.Now().Add(-40 * time.Minute).Truncate(time.Second).UTC()
+			promptAt := func(uuid string, offset time.Duration) string {
+				return `{"uuid":"` + uuid + `","type":"user","timestamp":"` + start.Add(offset).Format(time.RFC3339) +
+					`","message":{"role":"user","content":"Keep going"}}`
+			}
+			transcript := promptAt("u1", 0) + "\n" + promptAt("u2", 2*time.Minute) + "\n" + promptAt("u3", 30*time.Minute) + "\n"
+			transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+			Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+
+			Expect(repo.WriteFile("a.txt", "a")).To(Succeed())
+			Expect(repo.Commit("Add a")).To(Succeed())
+			hookInput := testutil.SampleHookInput("session-timeline", transcriptPath, "git commit -m 'Add a'")
+			_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("summarizes active and idle time", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--timeline")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Timeline for session session-timeline"))
+			Expect(stdout).To(ContainSubstring("Duration: 30m0s (active 2m0s, idle 28m0s)"))
+			Expect(stdout).To(ContainSubstring("First commit after:"))
+		})
+
+		It("lists active periods, idle gaps and commits", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show", "--timeline")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(MatchRegexp(`▶ active for 2m0s[^\n]*\n[^\n]*┄ idle for 28m0s[^\n]*\n[^\n]*▶ active for 0s[^\n]*\n[^\n]*◆ commit [0-9a-f]{7} Add a`))
+			Expect(stdout).NotTo(ContainSubstring("User:"))
+		})
+	})
 })
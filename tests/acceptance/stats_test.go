@@ -0,0 +1,303 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Stats Command", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test\nWritten by hand\n")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	// Helper to store a transcript on HEAD from raw JSONL lines
+	storeEntries := func(sessionID string, entries ...map[string]interface{}) {
+		var lines []string
+		for _, entry := range entries {
+			data, err := json.Marshal(entry)
+			Expect(err).NotTo(HaveOccurred())
+			lines = append(lines, string(data))
+		}
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+
+		hookInput := testutil.SampleHookInput(sessionID, transcriptPath, "git commit -m 'test'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// Helper to build an assistant entry that writes a file
+	writeEntry := func(uuid, file, content string) map[string]interface{} {
+		return map[string]interface{}{
+			"uuid": uuid, "type": "assistant",
+			"message": map[string]interface{}{
+				"role": "assistant",
+				"content": []map[string]interface{}{{
+					"type": "tool_use", "id": "tool-" + uuid, "name": "Write",
+					"input": map[string]string{"file_path": filepath.Join(repo.Path, file), "content": content},
+				}},
+			},
+		}
+	}
+
+	Describe("authorship", func() {
+		BeforeEach(func() {
+			Expect(repo.WriteFile("main.go", "package main\n\nfunc main() {}\n")).To(Succeed())
+			Expect(repo.WriteFile("NOTES.md", "by hand\n")).To(Succeed())
+			Expect(repo.Commit("Add main")).To(Succeed())
+			storeEntries("session-stats", writeEntry("a1", "main.go", "package main\n\nfunc main() {}\n"))
+		})
+
+		It("reports AI and human lines per commit", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "authorship")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(MatchRegexp(`COMMIT\s+DATE\s+AUTHOR\s+AI\s+HUMAN\s+AI%\s+SUBJECT`))
+			Expect(stdout).To(MatchRegexp(`\s2\s+1\s+66\.7%\s+Add main`))
+			Expect(stdout).To(MatchRegexp(`\s0\s+2\s+0\.0%\s+Initial commit`))
+			Expect(stdout).To(MatchRegexp(`TOTAL\s+2\s+3\s+40\.0%`))
+		})
+
+		It("reports per file", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "authorship", "--by", "file")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(MatchRegexp(`main\.go\s+2\s+0\s+100\.0%`))
+			Expect(stdout).To(MatchRegexp(`NOTES\.md\s+0\s+1\s+0\.0%`))
+		})
+
+		It("limits the report to a range", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "authorship", "HEAD~1..HEAD")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Add main"))
+			Expect(stdout).NotTo(ContainSubstring("Initial commit"))
+		})
+
+		It("outputs a time series as CSV", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "authorship", "--by", "month", "--format", "csv")
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).To(Equal("PERIOD,COMMITS,AI,HUMAN,AI%"))
+			Expect(lines[1]).To(MatchRegexp(`^\d{4}-\d{2}-01,2,2,3,40\.0%$`))
+		})
+
+		It("outputs JSON", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "authorship", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var report struct {
+				Total struct {
+					AILines    int `json:"ai_lines"`
+					HumanLines int `json:"human_lines"`
+				} `json:"total"`
+				Commits []struct {
+					Subject         string `json:"subject"`
+					HasConversation bool   `json:"has_conversation"`
+				} `json:"commits"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+			Expect(report.Total.AILines).To(Equal(2))
+			Expect(report.Total.HumanLines).To(Equal(3))
+			Expect(report.Commits).To(HaveLen(2))
+			Expect(report.Commits[0].HasConversation).To(BeTrue())
+		})
+
+		It("rejects unknown formats", func() {
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "stats", "authorship", "--format", "xml")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring(`unknown format "xml"`))
+		})
+	})
+	Describe("usage", func() {
+		// Helper to build an assistant response with token usage
+		responseEntry := func(uuid, messageID, model string, input, output int) map[string]interface{} {
+			return map[string]interface{}{
+				"uuid": uuid, "type": "assistant", "timestamp": "2024-03-01T12:00:00Z",
+				"message": map[string]interface{}{
+					"id": messageID, "role": "assistant", "model": model,
+					"usage":   map[string]int{"input_tokens": input, "output_tokens": output},
+					"content": []map[string]string{{"type": "text", "text": "ok"}},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			Expect(repo.WriteFile("a.txt", "a")).To(Succeed())
+			Expect(repo.Commit("Add a")).To(Succeed())
+			storeEntries("session-usage",
+				responseEntry("a1", "msg_1", "claude-sonnet-4-20250514", 1000000, 0),
+				// A second content block of the same response repeats its usage
+				responseEntry("a2", "msg_1", "claude-sonnet-4-20250514", 1000000, 0),
+				responseEntry("a3", "msg_2", "claude-sonnet-4-20250514", 0, 100000),
+			)
+		})
+
+		It("totals tokens and estimated cost per commit", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "usage")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(MatchRegexp(`COMMIT\s+RESPONSES\s+INPUT\s+OUTPUT\s+CACHE WRITE\s+CACHE READ\s+COST`))
+			// $3 for a million input tokens plus $1.50 for 100k output tokens
+			Expect(stdout).To(MatchRegexp(`TOTAL\s+2\s+1000000\s+100000\s+0\s+0\s+\$4\.50`))
+		})
+
+		It("groups by session", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "usage", "--by", "session", "--format", "csv")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("SESSION,RESPONSES,INPUT,OUTPUT,CACHE WRITE,CACHE READ,COST"))
+			Expect(stdout).To(ContainSubstring("session,2,1000000,100000,0,0,$4.50"))
+		})
+
+		It("uses prices from the config", func() {
+			config := `{"pricing": {"claude-sonnet-4": {"input": 1, "output": 10}}}`
+			Expect(repo.WriteFile(".claudit/config", config)).To(Succeed())
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "usage", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var report struct {
+				Total struct {
+					CostUSD float64 `json:"cost_usd"`
+				} `json:"total"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+			Expect(report.Total.CostUSD).To(BeNumerically("~", 2.0, 0.001))
+		})
+	})
+	Describe("tools", func() {
+		BeforeEach(func() {
+			bashCall := func(id, command string) map[string]interface{} {
+				return map[string]interface{}{
+					"uuid": "call-" + id, "type": "assistant",
+					"message": map[string]interface{}{
+						"role": "assistant",
+						"content": []map[string]interface{}{{
+							"type": "tool_use", "id": id, "name": "Bash",
+							"input": map[string]string{"command": command},
+						}},
+					},
+				}
+			}
+			bashResult := func(id, output string, isError bool) map[string]interface{} {
+				return map[string]interface{}{
+					"uuid": "result-" + id, "type": "user",
+					"message": map[string]interface{}{
+						"role": "user",
+						"content": []map[string]interface{}{{
+							"type": "tool_result", "tool_use_id": id, "content": output, "is_error": isError,
+						}},
+					},
+				}
+			}
+
+			Expect(repo.WriteFile("a.txt", "a")).To(Succeed())
+			Expect(repo.Commit("Add a")).To(Succeed())
+			storeEntries("session-tools",
+				bashCall("t1", "go test ./..."), bashResult("t1", "FAIL", true),
+				bashCall("t2", "go test ./..."), bashResult("t2", "ok", false),
+				bashCall("t3", "git status"), bashResult("t3", "clean", false),
+			)
+		})
+
+		It("reports tool usage, failures and test runs", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "tools")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Commits with conversations: 1"))
+			Expect(stdout).To(ContainSubstring("Tool calls: 3 (3.0 per commit, 1 failed)"))
+			Expect(stdout).To(ContainSubstring("Commits that ran tests: 1 (100.0%)"))
+			Expect(stdout).To(MatchRegexp(`Bash\s+3\s+1\s+33\.3%`))
+			Expect(stdout).To(MatchRegexp(`go test\s+2\s+1\s+50\.0%`))
+			Expect(stdout).To(MatchRegexp(`FAILED COMMAND[^\n]*\ngo test \./\.\.\.\s+1\s+1`))
+		})
+
+		It("outputs the full report as JSON", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "tools", "--json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var report struct {
+				ToolCalls     int `json:"tool_calls"`
+				TestedCommits int `json:"tested_commits"`
+				BashCommands  []struct {
+					Name   string `json:"name"`
+					Calls  int    `json:"calls"`
+					Errors int    `json:"errors"`
+				} `json:"bash_commands"`
+				Trend []struct {
+					Commits int `json:"commits"`
+				} `json:"trend"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+			Expect(report.ToolCalls).To(Equal(3))
+			Expect(report.TestedCommits).To(Equal(1))
+			Expect(report.BashCommands[0].Name).To(Equal("go test"))
+			Expect(report.BashCommands[0].Errors).To(Equal(1))
+			Expect(report.Trend).To(HaveLen(1))
+		})
+	})
+
+	Describe("sessions", func() {
+		BeforeEach(func() {
+			start := time.Now().Add(-40 * time.Minute).Truncate(time.Second).UTC()
+			promptAt := func(uuid string, offset time.Duration) map[string]interface{} {
+				return map[string]interface{}{
+					"uuid": uuid, "type": "user", "timestamp": start.Add(offset).Format(time.RFC3339),
+					"message": map[string]interface{}{"role": "user", "content": "Keep going"},
+				}
+			}
+
+			Expect(repo.WriteFile("a.txt", "a")).To(Succeed())
+			Expect(repo.Commit("Add a")).To(Succeed())
+			storeEntries("session-timeline",
+				promptAt("u1", 0), promptAt("u2", 2*time.Minute), promptAt("u3", 30*time.Minute),
+			)
+		})
+
+		It("reports duration, active and idle time per session", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "sessions")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(MatchRegexp(`SESSION\s+BRANCH\s+START\s+DURATION\s+ACTIVE\s+IDLE\s+COMMITS\s+FIRST COMMIT\s+BETWEEN`))
+			Expect(stdout).To(MatchRegexp(`session-timeline\s+\S+\s+[\d-]+ [\d:]+\s+30m0s\s+2m0s\s+28m0s\s+1\s+\S+\s+-`))
+		})
+
+		It("counts a longer gap as active with --idle", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "stats", "sessions", "--idle", "1h", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var sessions []struct {
+				SessionID string `json:"session_id"`
+				Timeline  struct {
+					Active  []interface{} `json:"active"`
+					Gaps    []interface{} `json:"gaps"`
+					Commits []struct {
+						SHA string `json:"sha"`
+					} `json:"commits"`
+				} `json:"timeline"`
+			}
+			Expect(json.Unmarshal([]byte(stdout), &sessions)).To(Succeed())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].SessionID).To(Equal("session-timeline"))
+			Expect(sessions[0].Timeline.Active).To(HaveLen(1))
+			Expect(sessions[0].Timeline.Gaps).To(BeEmpty())
+			Expect(sessions[0].Timeline.Commits).To(HaveLen(1))
+		})
+	})
+})
@@ -66,9 +66,9 @@ var _ = Describe("Store Command", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			var stored map[string]interface{}
-			Expect(json.Unmarshal([]byte(noteContent), &stored)).To(Succeed())
+			Expect(json.Unmarshal([]byte(testutil.NotePayload(noteContent)), &stored)).To(Succeed())
 
-			Expect(stored["version"]).To(BeEquivalentTo(1))
+			Expect(stored["version"]).To(BeEquivalentTo(2))
 			Expect(stored["session_id"]).To(Equal("session-456"))
 			Expect(stored["checksum"]).To(HavePrefix("sha256:"))
 			Expect(stored["transcript"]).NotTo(BeEmpty())
@@ -90,7 +90,7 @@ var _ = Describe("Store Command", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			var stored map[string]interface{}
-			Expect(json.Unmarshal([]byte(noteContent), &stored)).To(Succeed())
+			Expect(json.Unmarshal([]byte(testutil.NotePayload(noteContent)), &stored)).To(Succeed())
 
 			// Decode and decompress
 			encoded := stored["transcript"].(string)
@@ -144,6 +144,67 @@ var _ = Describe("Store Command", func() {
 		})
 	})
 
+	Describe("with a transcript from another assistant", func() {
+		aiderHistory := "# aider chat started at 2025-05-01 10:00:00\n\n" +
+			"#### add a hello function\n\n" +
+			"Here it is.\n\n" +
+			"> Applied edit to hello.py\n"
+
+		It("detects the format and records the provider", func() {
+			historyPath := filepath.Join(repo.Path, ".git", ".aider.chat.history.md")
+			Expect(os.WriteFile(historyPath, []byte(aiderHistory), 0644)).To(Succeed())
+
+			head, err := repo.GetHead()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "store", "--transcript", historyPath)
+			Expect(err).NotTo(HaveOccurred(), stderr)
+
+			noteContent, err := repo.GetNote("refs/notes/claude-conversations", head)
+			Expect(err).NotTo(HaveOccurred())
+			var stored map[string]interface{}
+			Expect(json.Unmarshal([]byte(testutil.NotePayload(noteContent)), &stored)).To(Succeed())
+			Expect(stored["provider"]).To(Equal("aider"))
+			Expect(stored["session_id"]).To(Equal("aider.chat.history"))
+			Expect(stored["message_count"]).To(BeEquivalentTo(3))
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "show")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("Provider: aider"))
+			Expect(stdout).To(ContainSubstring("add a hello function"))
+			Expect(stdout).To(ContainSubstring("Applied edit to hello.py"))
+
+			_, stderr, err = testutil.RunClauditInDir(repo.Path, "resume", "--force", "HEAD")
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring("only Claude Code sessions can be resumed"))
+		})
+
+		It("uses the given provider and session", func() {
+			chatPath := filepath.Join(repo.Path, ".git", "chat.md")
+			Expect(os.WriteFile(chatPath, []byte("**User**\n\nSplit the parser\n\n---\n\n**Cursor**\n\nDone.\n"), 0644)).To(Succeed())
+
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "store", "--transcript", chatPath, "--provider", "cursor", "--session", "refactor")
+			Expect(err).NotTo(HaveOccurred(), stderr)
+
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list", "--format", "json")
+			Expect(err).NotTo(HaveOccurred())
+			var records []map[string]interface{}
+			Expect(json.Unmarshal([]byte(stdout), &records)).To(Succeed())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0]["provider"]).To(Equal("cursor"))
+			Expect(records[0]["session_id"]).To(Equal("refactor"))
+		})
+
+		It("fails for a transcript in an unknown format", func() {
+			notesPath := filepath.Join(repo.Path, ".git", "notes.txt")
+			Expect(os.WriteFile(notesPath, []byte("some notes\n"), 0644)).To(Succeed())
+
+			_, stderr, err := testutil.RunClauditInDir(repo.Path, "store", "--transcript", notesPath)
+			Expect(err).To(HaveOccurred())
+			Expect(stderr).To(ContainSubstring("could not detect the transcript format"))
+		})
+	})
+
 	Describe("with missing transcript file", func() {
 		It("exits with error when transcript is missing", func() {
 			hookInput := testutil.SampleHookInput("session-123", "/nonexistent/path.jsonl", "git commit -m 'test'")
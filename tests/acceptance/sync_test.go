@@ -328,8 +328,8 @@ var _ = Describe("Sync Command", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			var original, cloned map[string]interface{}
-			Expect(json.Unmarshal([]byte(originalNote), &original)).To(Succeed())
-			Expect(json.Unmarshal([]byte(clonedNote), &cloned)).To(Succeed())
+			Expect(json.Unmarshal([]byte(testutil.NotePayload(originalNote)), &original)).To(Succeed())
+			Expect(json.Unmarshal([]byte(testutil.NotePayload(clonedNote)), &cloned)).To(Succeed())
 
 			Expect(cloned["session_id"]).To(Equal(original["session_id"]))
 			Expect(cloned["checksum"]).To(Equal(original["checksum"]))
@@ -0,0 +1,185 @@
+package acceptance_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Tag Command", func() {
+	var local, remote *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		local, remote, err = testutil.NewGitRepoWithRemote()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(local.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(local.Commit("Initial commit")).To(Succeed())
+		Expect(local.Run("git", "push", "-u", "origin", "master")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if local != nil {
+			local.Cleanup()
+		}
+		if remote != nil {
+			remote.Cleanup()
+		}
+	})
+
+	// Helper to commit and store a conversation, returning the commit's SHA
+	storeConversation := func(sessionID, message string) string {
+		Expect(local.WriteFile(sessionID+".txt", sessionID)).To(Succeed())
+		Expect(local.Commit(message)).To(Succeed())
+
+		transcriptPath := filepath.Join(local.Path, "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(testutil.SampleTranscript()), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput(sessionID, transcriptPath, "git commit -m 'test'")
+		_, _, err := testutil.RunClauditInDirWithStdin(local.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+
+		head, err := local.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	It("labels a conversation and lists its labels", func() {
+		head := storeConversation("session-tag", "Tagged commit")
+
+		stdout, _, err := testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident", "good example")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(head[:7] + ": good example, incident"))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "tag", "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("good example, incident"))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "show", "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Labels: good example, incident"))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "tag", "HEAD", "--format", "json")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(`"incident"`))
+	})
+
+	It("keeps labels apart from the conversation note", func() {
+		head := storeConversation("session-apart", "Tagged commit")
+		before, err := local.GetNote("refs/notes/claude-conversations", head)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, err = testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident")
+		Expect(err).NotTo(HaveOccurred())
+
+		after, err := local.GetNote("refs/notes/claude-conversations", head)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+		Expect(local.HasNote("refs/notes/claude-labels", head)).To(BeTrue())
+	})
+
+	It("removes labels with untag", func() {
+		head := storeConversation("session-untag", "Tagged commit")
+		_, _, err := testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident", "bug")
+		Expect(err).NotTo(HaveOccurred())
+
+		stdout, stderr, err := testutil.RunClauditInDir(local.Path, "untag", "HEAD", "bug", "missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(head[:7] + ": incident"))
+		Expect(stderr).To(ContainSubstring("missing"))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "untag", "HEAD", "--all")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("has no labels"))
+		Expect(local.HasNote("refs/notes/claude-labels", head)).To(BeFalse())
+	})
+
+	It("keeps labels when a commit is amended", func() {
+		_, _, err := testutil.RunClauditInDir(local.Path, "init")
+		Expect(err).NotTo(HaveOccurred())
+		storeConversation("session-amend", "Tagged commit")
+		_, _, err = testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(local.Run("git", "commit", "--amend", "-m", "Amended commit")).To(Succeed())
+		amended, err := local.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(local.HasNote("refs/notes/claude-labels", amended)).To(BeTrue())
+	})
+
+	It("requires labels or --all to untag", func() {
+		storeConversation("session-untag-args", "Tagged commit")
+
+		_, _, err := testutil.RunClauditInDir(local.Path, "untag", "HEAD")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses to label a commit without a conversation", func() {
+		_, stderr, err := testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("no conversation"))
+	})
+
+	It("filters list by label", func() {
+		tagged := storeConversation("session-labelled", "Labelled commit")
+		_, _, err := testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident", "bug")
+		Expect(err).NotTo(HaveOccurred())
+		plain := storeConversation("session-unlabelled", "Unlabelled commit")
+
+		stdout, _, err := testutil.RunClauditInDir(local.Path, "list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("[bug, incident]"))
+		Expect(stdout).To(ContainSubstring(plain[:7]))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "list", "--label", "incident", "--label", "bug")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring(tagged[:7]))
+		Expect(stdout).NotTo(ContainSubstring(plain[:7]))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "list", "--label", "incident", "--label", "other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).NotTo(ContainSubstring(tagged[:7]))
+	})
+
+	It("syncs labels between clones, merging both sides", func() {
+		head := storeConversation("session-sync", "Synced commit")
+		Expect(local.Run("git", "push", "origin", "master")).To(Succeed())
+		_, _, err := testutil.RunClauditInDir(local.Path, "tag", "HEAD", "incident")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = testutil.RunClauditInDir(local.Path, "sync", "push")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.HasNote("refs/notes/claude-labels", head)).To(BeTrue())
+
+		clone, err := testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+		defer clone.Cleanup()
+		Expect(clone.AddRemote("origin", remote.Path)).To(Succeed())
+		Expect(clone.Run("git", "fetch", "origin")).To(Succeed())
+
+		_, _, err = testutil.RunClauditInDir(clone.Path, "sync", "pull")
+		Expect(err).NotTo(HaveOccurred())
+		stdout, _, err := testutil.RunClauditInDir(clone.Path, "tag", head, "needs review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("incident, needs review"))
+		_, _, err = testutil.RunClauditInDir(clone.Path, "sync", "push")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Label locally too, so both sides have changed
+		_, _, err = testutil.RunClauditInDir(local.Path, "tag", "HEAD", "bug")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = testutil.RunClauditInDir(local.Path, "sync", "pull")
+		Expect(err).NotTo(HaveOccurred())
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "tag", "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("bug, incident, needs review"))
+
+		stdout, _, err = testutil.RunClauditInDir(local.Path, "sync", "push")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Pushed"))
+	})
+})
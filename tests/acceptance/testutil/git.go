@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
 // GitRepo represents a temporary git repository for testing
@@ -180,6 +181,15 @@ func (r *GitRepo) GetNote(ref, commit string) (string, error) {
 	return r.RunOutput("git", "notes", "--ref", ref, "show", commit)
 }
 
+// NotePayload returns the JSON of a conversation note, after its readable
+// header
+func NotePayload(note string) string {
+	if i := strings.Index(note, "\n\n{"); i >= 0 {
+		return note[i+2:]
+	}
+	return note
+}
+
 // HasNote checks if a commit has a note
 func (r *GitRepo) HasNote(ref, commit string) bool {
 	err := r.Run("git", "notes", "--ref", ref, "show", commit)
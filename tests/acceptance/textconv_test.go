@@ -0,0 +1,57 @@
+package acceptance_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Textconv Command", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(testutil.SampleTranscript()), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput("session-textconv", transcriptPath, "git commit -m 'test'")
+		_, _, err = testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	It("stores a readable header before the note's payload", func() {
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		note, err := repo.GetNote("refs/notes/claude-conversations", head)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(note).To(HavePrefix("claudit: claude session session-textconv"))
+		Expect(note).To(ContainSubstring("> Hello, can you help me with a task?"))
+	})
+
+	It("shortens conversation notes in git log output to their header", func() {
+		log, err := repo.RunOutput("git", "log", "--notes=claude-conversations")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).To(ContainSubstring(`"transcript"`))
+
+		stdout, _, err := testutil.RunClauditInDirWithStdin(repo.Path, log, "textconv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Initial commit"))
+		Expect(stdout).To(ContainSubstring("claudit: claude session session-textconv"))
+		Expect(stdout).NotTo(ContainSubstring(`"transcript"`))
+	})
+})
@@ -0,0 +1,118 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Commit Trailers", func() {
+	const notesRef = "refs/notes/claude-conversations"
+
+	var (
+		repo           *testutil.GitRepo
+		transcriptPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+		repo.SetBinaryPath(testutil.BinaryPath())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+
+		_, _, err = testutil.RunClauditInDir(repo.Path, "init", "--trailers")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Record an active session whose transcript lives outside the repo
+		transcriptPath = filepath.Join(repo.Path, ".git", "trailer-session.jsonl")
+		activeSession := map[string]string{
+			"session_id":      "trailer-session",
+			"transcript_path": transcriptPath,
+			"started_at":      time.Now().UTC().Format(time.RFC3339),
+			"project_path":    repo.Path,
+		}
+		data, _ := json.Marshal(activeSession)
+		Expect(repo.WriteFile(".claudit/active-session.json", string(data))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	// Helper to commit a file while the session transcript holds the given lines
+	commitInSession := func(file string, lines []string) string {
+		transcript := strings.Join(lines, "\n") + "\n"
+		Expect(os.WriteFile(transcriptPath, []byte(transcript), 0644)).To(Succeed())
+
+		Expect(repo.WriteFile(file, file)).To(Succeed())
+		Expect(repo.Commit("Add " + file)).To(Succeed())
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		return head
+	}
+
+	// The session transcript as it grows; built once so entries keep their timestamps
+	sessionLines := func() []string {
+		transcript := testutil.SampleTranscriptWithIDs(
+			[]string{"u1", "a1", "u2", "a2"},
+			[]string{"Add file a", "Added a", "Add file b", "Added b"})
+		return strings.Split(strings.TrimSpace(transcript), "\n")
+	}
+
+	It("adds session trailers to commits made during a session", func() {
+		commitInSession("a.txt", sessionLines()[:2])
+
+		message, err := repo.RunOutput("git", "log", "-1", "--format=%B")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(message).To(ContainSubstring("Claude-Session: trailer-session"))
+		Expect(message).To(MatchRegexp(`Claude-Transcript-Checksum: sha256:[0-9a-f]{64}`))
+	})
+
+	Describe("when a note is lost", func() {
+		var first string
+
+		BeforeEach(func() {
+			lines := sessionLines()
+			first = commitInSession("a.txt", lines[:2])
+			commitInSession("b.txt", lines)
+			Expect(repo.HasNote(notesRef, first)).To(BeTrue())
+
+			Expect(repo.Run("git", "notes", "--ref", notesRef, "remove", first)).To(Succeed())
+		})
+
+		It("lists the commit as having a missing note", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "list")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring(first[:7]))
+			Expect(stdout).To(ContainSubstring("note missing, session trailer"))
+		})
+
+		It("shows the conversation as it was at commit time", func() {
+			stdout, stderr, err := testutil.RunClauditInDir(repo.Path, "show", first)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stderr).To(ContainSubstring("found conversation via Claude-Session trailer"))
+			Expect(stdout).To(ContainSubstring("Add file a"))
+			Expect(stdout).NotTo(ContainSubstring("Add file b"))
+		})
+
+		It("reattaches the note", func() {
+			stdout, _, err := testutil.RunClauditInDir(repo.Path, "reattach", first)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(ContainSubstring("reattached " + first[:7]))
+			Expect(stdout).To(ContainSubstring("verified"))
+			Expect(repo.HasNote(notesRef, first)).To(BeTrue())
+		})
+	})
+})
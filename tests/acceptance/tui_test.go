@@ -0,0 +1,48 @@
+package acceptance_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("TUI Command", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("README.md", "# Test")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	It("fails when there are no conversations", func() {
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "tui")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("no conversations found"))
+	})
+
+	It("fails when not run in a terminal", func() {
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(`{"uuid":"u1","type":"user","message":{"role":"user","content":"Hello"}}`+"\n"), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput("session-tui", transcriptPath, "git commit -m 'test'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "tui")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("needs a terminal"))
+	})
+})
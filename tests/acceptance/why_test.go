@@ -0,0 +1,118 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/DanielJonesEB/claudit/tests/acceptance/testutil"
+)
+
+var _ = Describe("Why Command", func() {
+	var repo *testutil.GitRepo
+
+	BeforeEach(func() {
+		var err error
+		repo, err = testutil.NewGitRepo()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(repo.WriteFile("main.go", "package main\n\nfunc main() {\n}\n")).To(Succeed())
+		Expect(repo.Commit("Initial commit")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if repo != nil {
+			repo.Cleanup()
+		}
+	})
+
+	// Helper to store a transcript in which the assistant edits main.go, then
+	// carries on with an unrelated request
+	storeEditTranscript := func() {
+		entries := []map[string]interface{}{
+			{
+				"uuid": "u1", "type": "user",
+				"message": map[string]interface{}{"role": "user", "content": "Make main print a greeting"},
+			},
+			{
+				"uuid": "a1", "parentUuid": "u1", "type": "assistant",
+				"message": map[string]interface{}{
+					"role": "assistant",
+					"content": []map[string]interface{}{
+						{"type": "text", "text": "I'll add a println call."},
+						{
+							"type": "tool_use", "id": "t1", "name": "Edit",
+							"input": map[string]string{
+								"file_path":  filepath.Join(repo.Path, "main.go"),
+								"old_string": "func main() {\n}",
+								"new_string": "func main() {\n\tprintln(\"hello\")\n}",
+							},
+						},
+					},
+				},
+			},
+			{
+				"uuid": "r1", "parentUuid": "a1", "type": "user",
+				"message": map[string]interface{}{
+					"role": "user",
+					"content": []map[string]interface{}{
+						{"type": "tool_result", "tool_use_id": "t1", "content": "File updated"},
+					},
+				},
+			},
+			{
+				"uuid": "u2", "parentUuid": "r1", "type": "user",
+				"message": map[string]interface{}{"role": "user", "content": "Now commit it"},
+			},
+		}
+		var lines []string
+		for _, entry := range entries {
+			data, err := json.Marshal(entry)
+			Expect(err).NotTo(HaveOccurred())
+			lines = append(lines, string(data))
+		}
+
+		transcriptPath := filepath.Join(repo.Path, ".git", "transcript.jsonl")
+		Expect(os.WriteFile(transcriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+		hookInput := testutil.SampleHookInput("why-session", transcriptPath, "git commit -m 'Print greeting'")
+		_, _, err := testutil.RunClauditInDirWithStdin(repo.Path, hookInput, "store")
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("shows the exchange that introduced the line", func() {
+		Expect(repo.WriteFile("main.go", "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")).To(Succeed())
+		Expect(repo.Commit("Print greeting")).To(Succeed())
+		head, err := repo.GetHead()
+		Expect(err).NotTo(HaveOccurred())
+		storeEditTranscript()
+
+		// A later human commit touching a different line must not hide the origin
+		Expect(repo.WriteFile("main.go", "package main // entry point\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")).To(Succeed())
+		Expect(repo.Commit("Comment package")).To(Succeed())
+
+		stdout, _, err := testutil.RunClauditInDir(repo.Path, "why", "main.go:4")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("main.go:4 was last changed by " + head[:7]))
+		Expect(stdout).To(ContainSubstring("Session: why-session (Edit call)"))
+		Expect(stdout).To(ContainSubstring("Make main print a greeting"))
+		Expect(stdout).To(ContainSubstring("I'll add a println call."))
+		Expect(stdout).To(ContainSubstring("File updated"))
+		Expect(stdout).NotTo(ContainSubstring("Now commit it"))
+	})
+
+	It("reports lines from commits without conversations", func() {
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "why", "main.go:1")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("no conversation found for commit"))
+	})
+
+	It("rejects arguments without a line number", func() {
+		_, stderr, err := testutil.RunClauditInDir(repo.Path, "why", "main.go")
+		Expect(err).To(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("expected <file>:<line>"))
+	})
+})
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -211,6 +212,11 @@ func TestClaudeCodeIntegration(t *testing.T) {
 		t.Fatalf("FAIL: Note exists but cannot be read: %v", err)
 	}
 
+	// The note's JSON follows a readable header
+	if i := bytes.Index(noteContent, []byte("\n\n{")); i >= 0 {
+		noteContent = noteContent[i+2:]
+	}
+
 	var noteData map[string]interface{}
 	if err := json.Unmarshal(noteContent, &noteData); err != nil {
 		t.Fatalf("FAIL: Note content is not valid JSON: %v\nContent: %s", err, noteContent[:min(len(noteContent), 500)])